@@ -0,0 +1,290 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"sort"
+	"time"
+)
+
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+)
+
+// Migration — одна нумерована зворотна міграція модуля
+type Migration struct {
+	Module  string
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+func (m Migration) ID() string {
+	return fmt.Sprintf("%s/%04d_%s", m.Module, m.Version, m.Name)
+}
+
+// SchemaMigration — запис про застосовану міграцію в БД тентанта
+type SchemaMigration struct {
+	Module    string    `gorm:"primaryKey;size:64" json:"module"`
+	Version   int       `gorm:"primaryKey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"not null" json:"name"`
+	AppliedAt time.Time `gorm:"not null" json:"applied_at"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Step описує дію, яку виконано або буде виконано (у режимі dry-run)
+type Step struct {
+	ID        string `json:"id"`
+	Module    string `json:"module"`
+	Version   int    `json:"version"`
+	Name      string `json:"name"`
+	Direction string `json:"direction"`
+}
+
+// Status — стан однієї міграції в конкретній БД
+type Status struct {
+	ID        string     `json:"id"`
+	Module    string     `json:"module"`
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// Validate перевіряє, що версії в межах модуля унікальні й зростають
+func Validate(migrations []Migration) error {
+	last := make(map[string]int)
+	for _, m := range migrations {
+		if m.Module == "" || m.Version <= 0 || m.Up == nil || m.Down == nil {
+			return fmt.Errorf("migration %s is incomplete", m.ID())
+		}
+		if m.Version <= last[m.Module] {
+			return fmt.Errorf("migration %s is out of order", m.ID())
+		}
+		last[m.Module] = m.Version
+	}
+	return nil
+}
+
+func ensureTable(db *gorm.DB) error {
+	return db.AutoMigrate(&SchemaMigration{})
+}
+
+// lockKey — ключ advisory-блокування міграцій. Advisory-блокування діють у межах
+// БД, а current_schema() розрізняє тентантів зі спільною БД (режим схем).
+const lockKey = `hashtext('schema_migrations:' || current_schema())`
+
+// withLock виконує fn на одному з'єднанні під сесійним pg_advisory_lock, щоб два
+// інстанси (або масова міграція паралельно з провіженінгом) не застосували ту саму
+// міграцію двічі. Блокування сесійне, тому lock і unlock мають йти одним з'єднанням.
+func withLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.Connection(func(conn *gorm.DB) (err error) {
+		if err := conn.Exec(`SELECT pg_advisory_lock(` + lockKey + `)`).Error; err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+		defer func() {
+			if unlockErr := conn.Exec(`SELECT pg_advisory_unlock(` + lockKey + `)`).Error; unlockErr != nil && err == nil {
+				err = fmt.Errorf("release migration lock: %w", unlockErr)
+			}
+		}()
+		return fn(conn)
+	})
+}
+
+// applied лише читає: якщо таблиці ще немає, жодна міграція не застосована.
+// Так dry-run і перегляд стану нічого не записують у БД тентанта.
+func applied(db *gorm.DB) (map[string]SchemaMigration, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return map[string]SchemaMigration{}, nil
+	}
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[string]SchemaMigration, len(rows))
+	for _, row := range rows {
+		result[key(row.Module, row.Version)] = row
+	}
+	return result, nil
+}
+
+func key(module string, version int) string {
+	return fmt.Sprintf("%s/%d", module, version)
+}
+
+func toStep(m Migration, direction string) Step {
+	return Step{ID: m.ID(), Module: m.Module, Version: m.Version, Name: m.Name, Direction: direction}
+}
+
+// Plan повертає міграції, які ще не застосовані, у порядку виконання
+func Plan(db *gorm.DB, migrations []Migration) ([]Step, error) {
+	if err := Validate(migrations); err != nil {
+		return nil, err
+	}
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []Step
+	for _, m := range migrations {
+		if _, ok := done[key(m.Module, m.Version)]; !ok {
+			steps = append(steps, toStep(m, DirectionUp))
+		}
+	}
+	return steps, nil
+}
+
+// Up застосовує всі незастосовані міграції, кожну в окремій транзакції.
+// У режимі dryRun лише повертає план.
+func Up(db *gorm.DB, migrations []Migration, dryRun bool) ([]Step, error) {
+	if dryRun {
+		return Plan(db, migrations)
+	}
+
+	var executed []Step
+	err := withLock(db, func(conn *gorm.DB) error {
+		// План читаємо вже під блокуванням: інший процес міг щойно застосувати частину міграцій
+		plan, err := Plan(conn, migrations)
+		if err != nil || len(plan) == 0 {
+			return err
+		}
+		if err := ensureTable(conn); err != nil {
+			return err
+		}
+
+		pending := make(map[string]bool, len(plan))
+		for _, step := range plan {
+			pending[step.ID] = true
+		}
+
+		for _, m := range migrations {
+			if !pending[m.ID()] {
+				continue
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{
+					Module:    m.Module,
+					Version:   m.Version,
+					Name:      m.Name,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %s failed: %w", m.ID(), err)
+			}
+			executed = append(executed, toStep(m, DirectionUp))
+		}
+		return nil
+	})
+	return executed, err
+}
+
+// Down відкочує останні steps застосованих міграцій модуля.
+// Якщо module порожній — відкочує останні міграції незалежно від модуля.
+func Down(db *gorm.DB, migrations []Migration, module string, steps int, dryRun bool) ([]Step, error) {
+	if steps <= 0 {
+		return nil, errors.New("steps must be positive")
+	}
+	if err := Validate(migrations); err != nil {
+		return nil, err
+	}
+	if dryRun {
+		done, err := applied(db)
+		if err != nil {
+			return nil, err
+		}
+		var result []Step
+		for _, m := range rollbackCandidates(migrations, done, module, steps) {
+			result = append(result, toStep(m, DirectionDown))
+		}
+		return result, nil
+	}
+
+	var result []Step
+	err := withLock(db, func(conn *gorm.DB) error {
+		done, err := applied(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range rollbackCandidates(migrations, done, module, steps) {
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := m.Down(tx); err != nil {
+					return err
+				}
+				return tx.Where("module = ? AND version = ?", m.Module, m.Version).
+					Delete(&SchemaMigration{}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("rollback %s failed: %w", m.ID(), err)
+			}
+			result = append(result, toStep(m, DirectionDown))
+		}
+		return nil
+	})
+	return result, err
+}
+
+// rollbackCandidates — застосовані міграції для відкату, у зворотному порядку реєстрації
+func rollbackCandidates(migrations []Migration, done map[string]SchemaMigration, module string, steps int) []Migration {
+	var candidates []Migration
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if module != "" && m.Module != module {
+			continue
+		}
+		if _, ok := done[key(m.Module, m.Version)]; ok {
+			candidates = append(candidates, m)
+		}
+		if len(candidates) == steps {
+			break
+		}
+	}
+	return candidates
+}
+
+// List повертає стан кожної зареєстрованої міграції
+func List(db *gorm.DB, migrations []Migration) ([]Status, error) {
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(migrations))
+	for _, m := range migrations {
+		status := Status{ID: m.ID(), Module: m.Module, Version: m.Version, Name: m.Name}
+		if row, ok := done[key(m.Module, m.Version)]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// LastApplied повертає останню за часом застосовану міграцію або nil
+func LastApplied(db *gorm.DB) (*SchemaMigration, error) {
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+	if len(done) == 0 {
+		return nil, nil
+	}
+	rows := make([]SchemaMigration, 0, len(done))
+	for _, row := range done {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].AppliedAt.After(rows[j].AppliedAt) })
+	return &rows[0], nil
+}
@@ -26,19 +26,8 @@ func Connect() error {
 	//fmt.Println("POSTGRES_USER:", os.Getenv("POSTGRES_USER"))
 	//fmt.Println("POSTGRES_DB:", os.Getenv("POSTGRES_DB"))
 
-	// Формування DSN рядка для підключення
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
-		os.Getenv("POSTGRES_SERVER"),
-		os.Getenv("POSTGRES_USER"),
-		os.Getenv("POSTGRES_PASSWORD"),
-		os.Getenv("POSTGRES_DB"),
-		os.Getenv("POSTGRES_PORT"),
-		os.Getenv("POSTGRES_SSLMODE"),
-		os.Getenv("POSTGRES_TIMEZONE"),
-	)
-
 	// Підключення до бази даних
-	d, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+	d, err := gorm.Open(postgres.Open(adminDSN()), &gorm.Config{
 		PrepareStmt: true,
 	})
 	if err != nil {
@@ -70,3 +59,16 @@ func GetDB() *gorm.DB {
 	}
 	return DB
 }
+
+// adminDSN — рядок підключення до адмінської БД
+func adminDSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
+		os.Getenv("POSTGRES_SERVER"),
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+		os.Getenv("POSTGRES_PORT"),
+		os.Getenv("POSTGRES_SSLMODE"),
+		os.Getenv("POSTGRES_TIMEZONE"),
+	)
+}
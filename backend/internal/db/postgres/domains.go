@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"backend/internal/entities"
+	"container/list"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Хост береться з заголовка запиту, тож кеш обмежений за розміром (LRU),
+// а негативні записи живуть недовго — щойно підтверджений домен запрацює швидко
+const (
+	hostCacheSize   = 10000
+	negativeHostTTL = 10 // секунд
+)
+
+type cachedHost struct {
+	Host      string
+	Domain    string // порожній — хост не зареєстрований, працює fallback по субдомену
+	ExpiresAt int64
+}
+
+var (
+	hostMu    sync.Mutex
+	hostCache = make(map[string]*list.Element)
+	hostLRU   = list.New() // спереду — нещодавно використані
+)
+
+func cachedHostDomain(host string, now int64) (string, bool) {
+	hostMu.Lock()
+	defer hostMu.Unlock()
+	elem, found := hostCache[host]
+	if !found {
+		return "", false
+	}
+	entry := elem.Value.(*cachedHost)
+	if now >= entry.ExpiresAt {
+		removeHostLocked(elem)
+		return "", false
+	}
+	hostLRU.MoveToFront(elem)
+	return entry.Domain, true
+}
+
+func storeHost(host, domain string, now int64) {
+	ttl := int64(cacheTTL)
+	if domain == "" {
+		ttl = negativeHostTTL
+	}
+	hostMu.Lock()
+	defer hostMu.Unlock()
+	if elem, found := hostCache[host]; found {
+		removeHostLocked(elem)
+	}
+	hostCache[host] = hostLRU.PushFront(&cachedHost{Host: host, Domain: domain, ExpiresAt: now + ttl})
+	for hostLRU.Len() > hostCacheSize {
+		removeHostLocked(hostLRU.Back())
+	}
+}
+
+func removeHostLocked(elem *list.Element) {
+	delete(hostCache, elem.Value.(*cachedHost).Host)
+	hostLRU.Remove(elem)
+}
+
+// NormalizeHost прибирає порт, крапку в кінці та приводить хост до нижнього регістру
+func NormalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if i := strings.LastIndex(host, ":"); i != -1 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// lookupHost шукає підтверджений хост у tenant_domains з урахуванням кешу
+func lookupHost(host string) (string, error) {
+	now := time.Now().Unix()
+	if domain, found := cachedHostDomain(host, now); found {
+		return domain, nil
+	}
+
+	var domain string
+	err := GetDB().Model(&entities.TenantDomain{}).
+		Select("tenants.domain").
+		Joins("JOIN tenants ON tenants.id = tenant_domains.tenant_id").
+		Where("tenant_domains.host = ? AND tenant_domains.verified = ?", host, true).
+		Limit(1).
+		Scan(&domain).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", fmt.Errorf("tenant domain lookup failed: %w", err)
+	}
+
+	storeHost(host, domain, now)
+	return domain, nil
+}
+
+// ResolveHost повертає домен тентанта для хоста запиту: спочатку точний збіг
+// у tenant_domains, інакше — перша частина хоста (субдомен)
+func (m *DBManager) ResolveHost(host string) (string, error) {
+	host = NormalizeHost(host)
+	domain, err := lookupHost(host)
+	if err != nil {
+		return "", err
+	}
+	if domain != "" {
+		return domain, nil
+	}
+	return strings.Split(host, ".")[0], nil
+}
+
+// IsVerifiedHost — чи зареєстрований хост як підтверджений домен тентанта (для CORS)
+func (m *DBManager) IsVerifiedHost(host string) bool {
+	domain, err := lookupHost(NormalizeHost(host))
+	return err == nil && domain != ""
+}
+
+// ClearHostCache скидає кеш для конкретного хоста або для всіх хостів тентанта
+func ClearHostCache(host, domain string) {
+	hostMu.Lock()
+	defer hostMu.Unlock()
+	if host != "" {
+		if elem, found := hostCache[NormalizeHost(host)]; found {
+			removeHostLocked(elem)
+		}
+	}
+	if domain == "" {
+		return
+	}
+	for elem := hostLRU.Front(); elem != nil; {
+		next := elem.Next()
+		if elem.Value.(*cachedHost).Domain == domain {
+			removeHostLocked(elem)
+		}
+		elem = next
+	}
+}
+
+// PrimaryCustomHost — перший підтверджений власний домен тентанта або "",
+// якщо тентант працює лише на субдомені
+func PrimaryCustomHost(tenantID uuid.UUID) (string, error) {
+	var host string
+	err := GetDB().Model(&entities.TenantDomain{}).
+		Select("host").
+		Where("tenant_id = ? AND kind = ? AND verified = ?", tenantID, entities.TenantDomainCustom, true).
+		Order("verified_at ASC").
+		Limit(1).
+		Scan(&host).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	return host, nil
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"backend/internal/entities"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// GetLoginAttempt повертає лічильник або порожній запис, якщо помилок ще не було
+func GetLoginAttempt(tenantID uuid.UUID, email, ip, scope string) (*entities.LoginAttempt, error) {
+	var attempt entities.LoginAttempt
+	err := GetDB().
+		Where("tenant_id = ? AND email = ? AND ip = ? AND scope = ?", tenantID, email, ip, scope).
+		First(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &entities.LoginAttempt{TenantID: tenantID, Email: email, IP: ip, Scope: scope}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// SaveLoginAttempt записує лічильник upsert-ом за унікальним ключем: паралельні
+// перші помилки не створюють дублікатів, а оновлюють один рядок
+func SaveLoginAttempt(attempt *entities.LoginAttempt) error {
+	return GetDB().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "email"}, {Name: "ip"}, {Name: "scope"}},
+		DoUpdates: clause.AssignmentColumns([]string{"attempts", "last_attempt", "banned_until", "updated_at"}),
+	}).Create(attempt).Error
+}
+
+// ClearLoginAttempts скидає лічильники email після успішного входу: пару з цим IP і обліковий запис
+func ClearLoginAttempts(tenantID uuid.UUID, email, ip string) error {
+	return GetDB().
+		Where("tenant_id = ? AND email = ? AND ((scope = ? AND ip = ?) OR scope = ?)",
+			tenantID, email, entities.LoginScopeIP, ip, entities.LoginScopeAccount).
+		Delete(&entities.LoginAttempt{}).Error
+}
+
+// ClearSecondFactorAttempts скидає лічильник другого фактора після входу через 2FA
+func ClearSecondFactorAttempts(tenantID uuid.UUID, email string) error {
+	return GetDB().
+		Where("tenant_id = ? AND email = ? AND scope = ?", tenantID, email, entities.LoginScopeMFA).
+		Delete(&entities.LoginAttempt{}).Error
+}
+
+// ListLoginBans — чинні блокування тентанта, найновіші першими
+func ListLoginBans(tenantID uuid.UUID) ([]entities.LoginAttempt, error) {
+	var bans []entities.LoginAttempt
+	err := GetDB().
+		Where("tenant_id = ? AND banned_until > ?", tenantID, time.Now()).
+		Order("banned_until DESC").
+		Find(&bans).Error
+	return bans, err
+}
+
+// DeleteLoginBan знімає одне блокування тентанта
+func DeleteLoginBan(tenantID, id uuid.UUID) error {
+	result := GetDB().Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&entities.LoginAttempt{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("login ban not found")
+	}
+	return nil
+}
+
+// DeleteLoginBansByEmail знімає всі блокування і лічильники облікового запису
+func DeleteLoginBansByEmail(tenantID uuid.UUID, email string) (int64, error) {
+	result := GetDB().Where("tenant_id = ? AND email = ?", tenantID, email).Delete(&entities.LoginAttempt{})
+	return result.RowsAffected, result.Error
+}
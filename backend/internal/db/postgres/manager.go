@@ -2,7 +2,9 @@ package postgres
 
 import (
 	"backend/internal/entities"
+	"backend/internal/services/audit"
 	"backend/internal/services/utils"
+	"container/list"
 	"fmt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -14,24 +16,22 @@ const cacheTTL = 60 // час життя кешу в секундах
 
 type CachedTenant struct {
 	Tenant     entities.Tenant
+	Settings   entities.TenantSettings
+	Replicas   []entities.TenantReplica
 	LastUpdate int64
 }
 
 type DBManager struct {
 	mu          sync.RWMutex
-	connections map[string]*gorm.DB
 	tenantCache map[string]CachedTenant
 }
 
 var Manager = &DBManager{
-	connections: make(map[string]*gorm.DB),
 	tenantCache: make(map[string]CachedTenant),
 }
 
-// Отримати підключення до БД тентанта
-func (m *DBManager) GetConnectionByDomain(domain string) (*gorm.DB, error) {
-	var tenant entities.Tenant
-
+// cached повертає тентанта і його налаштування з кешу, оновлюючи їх після cacheTTL
+func (m *DBManager) cached(domain string) (CachedTenant, error) {
 	now := time.Now().Unix()
 
 	m.mu.RLock()
@@ -39,47 +39,119 @@ func (m *DBManager) GetConnectionByDomain(domain string) (*gorm.DB, error) {
 	m.mu.RUnlock()
 
 	if found && now-cachedTenant.LastUpdate < cacheTTL {
-		tenant = cachedTenant.Tenant
-	} else {
-		// Оновлюємо tenant з БД
-		if err := GetDB().Where("domain = ?", domain).First(&tenant).Error; err != nil {
-			return nil, fmt.Errorf("tenant not found: %w", err)
-		}
-
-		m.mu.Lock()
-		m.tenantCache[domain] = CachedTenant{
-			Tenant:     tenant,
-			LastUpdate: now,
-		}
-		m.mu.Unlock()
+		return cachedTenant, nil
+	}
+
+	// Оновлюємо tenant з БД
+	var tenant entities.Tenant
+	if err := GetDB().Where("domain = ?", domain).First(&tenant).Error; err != nil {
+		return CachedTenant{}, fmt.Errorf("tenant not found: %w", err)
+	}
+	settings, err := LoadTenantSettings(tenant.ID)
+	if err != nil {
+		return CachedTenant{}, err
+	}
+	replicas, err := loadTenantReplicas(tenant.ID)
+	if err != nil {
+		return CachedTenant{}, err
+	}
+
+	cachedTenant = CachedTenant{Tenant: tenant, Settings: settings, Replicas: replicas, LastUpdate: now}
+	m.mu.Lock()
+	m.tenantCache[domain] = cachedTenant
+	m.mu.Unlock()
+	return cachedTenant, nil
+}
+
+// opener перевіряє активність тентанта і повертає функцію відкриття його БД
+func (m *DBManager) opener(domain string) (func() (*gorm.DB, error), error) {
+	cachedTenant, err := m.cached(domain)
+	if err != nil {
+		return nil, err
 	}
+	tenant := cachedTenant.Tenant
 
 	// Перевіряємо активність тентанта
 	if !tenant.Status {
 		return nil, fmt.Errorf("tenant inactive")
 	}
+	return func() (*gorm.DB, error) {
+		return openTenantDB(&tenant, cachedTenant.Settings.Location().String())
+	}, nil
+}
 
-	// Повертаємо чинне підключення або створюємо нове
-	if conn, exists := Pool.Get(domain); exists {
-		return conn, nil
+// Отримати підключення до БД тентанта. Пул може бути закритий після витіснення,
+// тож фонові задачі мають брати підключення заново на кожен запуск.
+func (m *DBManager) GetConnectionByDomain(domain string) (*gorm.DB, error) {
+	open, err := m.opener(domain)
+	if err != nil {
+		return nil, err
 	}
+	// Повертаємо чинне підключення або створюємо нове (одне на всі паралельні запити)
+	return Pool.GetOrOpen(domain, open)
+}
 
-	tenantCreds, err := utils.DecryptTenantCreds(&tenant)
+// AcquireConnectionByDomain — як GetConnectionByDomain, але пул не закриється,
+// доки не викликано release. Для запитів і довгих підключень (WebSocket, SSE).
+func (m *DBManager) AcquireConnectionByDomain(domain string) (*gorm.DB, func(), error) {
+	open, err := m.opener(domain)
+	if err != nil {
+		return nil, nil, err
+	}
+	return Pool.Acquire(domain, open)
+}
+
+// TenantSettings повертає налаштування тентанта (з кешу, якщо вони свіжі)
+func (m *DBManager) TenantSettings(domain string) (entities.TenantSettings, error) {
+	cachedTenant, err := m.cached(domain)
+	if err != nil {
+		return entities.TenantSettings{}, err
+	}
+	return cachedTenant.Settings, nil
+}
+
+// OpenTenantDB відкриває нове підключення до БД тентанта без кешування.
+// Використовується там, де тентант ще не активний (провіженінг, міграції).
+func OpenTenantDB(tenant *entities.Tenant) (*gorm.DB, error) {
+	settings, err := LoadTenantSettings(tenant.ID)
+	if err != nil {
+		return nil, err
+	}
+	return openTenantDB(tenant, settings.Location().String())
+}
+
+func openTenantDB(tenant *entities.Tenant, timezone string) (*gorm.DB, error) {
+	tenantCreds, err := utils.DecryptTenantCreds(tenant)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt tenant credentials: %w", err)
 	}
+	return openTenantDSN(tenant, tenantCreds, tenant.DBPort, timezone)
+}
 
+// TenantDSN будує рядок підключення до БД тентанта
+func TenantDSN(tenant *entities.Tenant, tenantCreds *utils.DecryptedTenantCreds, port, timezone string) string {
 	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Europe/Warsaw",
-		tenantCreds.DBHost, tenantCreds.DBUser, tenantCreds.DBPassword, tenantCreds.DBName, tenant.DBPort,
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=%s",
+		tenantCreds.DBHost, tenantCreds.DBUser, tenantCreds.DBPassword, tenantCreds.DBName, port, timezone,
 	)
+	// У режимі schema всі з'єднання пулу тентанта працюють у його схемі;
+	// public лишається в search_path заради розширень (uuid_generate_v4),
+	// але створювати в ній об'єкти тентантам заборонено (TenantSchemaStatements)
+	if tenant.IsolationMode == entities.IsolationSchema {
+		dsn += fmt.Sprintf(" search_path=%s,public", tenant.SchemaName)
+	}
+	return dsn
+}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// openTenantDSN підключається з вказаними обліковими даними (основна БД або репліка)
+func openTenantDSN(tenant *entities.Tenant, tenantCreds *utils.DecryptedTenantCreds, port, timezone string) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(TenantDSN(tenant, tenantCreds, port, timezone)), &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to tenant DB: %w", err)
 	}
-
-	Pool.Set(domain, db)
+	if err := audit.Register(db); err != nil {
+		return nil, fmt.Errorf("failed to register audit log: %w", err)
+	}
 	return db, nil
 }
 
@@ -87,13 +159,45 @@ func (m *DBManager) GetConnectionByDomain(domain string) (*gorm.DB, error) {
 func (m *DBManager) ClearTenantCache(domain string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	replicas := m.tenantCache[domain].Replicas
+	for _, replica := range replicas {
+		Pool.Delete(replicaPoolKey(domain, replica.ID))
+	}
+	forgetReplicaLag(replicas)
 	delete(m.tenantCache, domain)
+	ClearHostCache("", domain)
 	Pool.Delete(domain) // 💡 очищаємо і пул
 }
 
+// ClearAll скидає кеш усіх тентантів і хостів (пули з'єднань лишаються)
+func (m *DBManager) ClearAll() {
+	m.mu.Lock()
+	m.tenantCache = make(map[string]CachedTenant)
+	m.mu.Unlock()
+
+	hostMu.Lock()
+	hostCache = make(map[string]*list.Element)
+	hostLRU.Init()
+	hostMu.Unlock()
+}
+
+// InvalidateTenant скидає лише кешовані дані тентанта, не чіпаючи пул з'єднань
+func (m *DBManager) InvalidateTenant(domain string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tenantCache, domain)
+}
+
 // Дістати тентанта з кешу
 func (m *DBManager) TenantFromCache(domain string) entities.Tenant {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	return m.tenantCache[domain].Tenant
 }
+
+// Дістати налаштування тентанта з кешу
+func (m *DBManager) SettingsFromCache(domain string) entities.TenantSettings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.tenantCache[domain].Settings
+}
@@ -1,17 +1,87 @@
 package postgres
 
 import (
-	"github.com/google/uuid"
+	"backend/internal/db/migrator"
+	"backend/internal/entities"
+	"log"
 	"sync"
 )
 
-var migratedTenants sync.Map
+const (
+	MigrationStatusMigrated = "migrated"
+	MigrationStatusUpToDate = "up_to_date"
+	MigrationStatusPlanned  = "planned"
+	MigrationStatusFailed   = "failed"
+)
+
+// migrateAllConcurrency — скільки тентантів мігруємо одночасно
+const migrateAllConcurrency = 4
+
+// TenantMigrationReport — результат міграції одного тентанта
+type TenantMigrationReport struct {
+	TenantID string          `json:"tenant_id"`
+	Domain   string          `json:"domain"`
+	Status   string          `json:"status"`
+	Steps    []migrator.Step `json:"steps"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// MigrateAllTenants проходить по всіх тентантах і застосовує незастосовані міграції.
+// Помилка одного тентанта не зупиняє решту — вона потрапляє у звіт.
+func MigrateAllTenants(dryRun bool) ([]TenantMigrationReport, error) {
+	var tenants []entities.Tenant
+	if err := GetDB().Order("domain ASC").Find(&tenants).Error; err != nil {
+		return nil, err
+	}
 
-func MarkTenantMigrated(tenantID uuid.UUID) {
-	migratedTenants.Store(tenantID, true)
+	reports := make([]TenantMigrationReport, len(tenants))
+	sem := make(chan struct{}, migrateAllConcurrency)
+	var wg sync.WaitGroup
+
+	for i := range tenants {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			reports[i] = MigrateSingleTenant(&tenants[i], dryRun)
+		}(i)
+	}
+	wg.Wait()
+
+	return reports, nil
 }
 
-func IsTenantMigrated(tenantID uuid.UUID) bool {
-	_, ok := migratedTenants.Load(tenantID)
-	return ok
+// MigrateSingleTenant мігрує одного тентанта, навіть якщо він неактивний
+func MigrateSingleTenant(tenant *entities.Tenant, dryRun bool) TenantMigrationReport {
+	report := TenantMigrationReport{TenantID: tenant.ID.String(), Domain: tenant.Domain}
+
+	db, err := OpenTenantDB(tenant)
+	if err != nil {
+		report.Status = MigrationStatusFailed
+		report.Error = err.Error()
+		return report
+	}
+	defer CloseTenantDB(db)
+
+	steps, err := migrator.Up(db, TenantMigrations(), dryRun)
+	report.Steps = steps
+	switch {
+	case err != nil:
+		report.Status = MigrationStatusFailed
+		report.Error = err.Error()
+	case len(steps) == 0:
+		report.Status = MigrationStatusUpToDate
+	case dryRun:
+		report.Status = MigrationStatusPlanned
+	default:
+		report.Status = MigrationStatusMigrated
+	}
+
+	if !dryRun && err == nil && !tenant.Migrated {
+		if err := GetDB().Model(tenant).Update("migrated", true).Error; err != nil {
+			log.Printf("❌ [%s] cannot mark tenant migrated: %v", tenant.Domain, err)
+		}
+	}
+	return report
 }
@@ -1,22 +1,28 @@
 package postgres
 
 import (
+	"backend/internal/db/migrator"
 	"backend/internal/entities"
 	"backend/internal/services/utils"
-	blog "backend/modules/blog/models"
-	calendar "backend/modules/calendar/models"
-	messages "backend/modules/chat/messages/models"
-	chatRooms "backend/modules/chat/rooms/models"
-	directMessage "backend/modules/direct/models"
-	employees "backend/modules/employees/models"
-	item "backend/modules/item/models"
-	media "backend/modules/media/models"
-	property "backend/modules/property/models"
-	reactions "backend/modules/reaction/models"
+	auditMigrations "backend/modules/audit/migrations"
+	blog "backend/modules/blog/migrations"
+	calendar "backend/modules/calendar/migrations"
+	chat "backend/modules/chat/migrations"
+	direct "backend/modules/direct/migrations"
+	employees "backend/modules/employees/migrations"
+	invitationMigrations "backend/modules/invitation/migrations"
+	item "backend/modules/item/migrations"
+	media "backend/modules/media/migrations"
+	property "backend/modules/property/migrations"
+	reactions "backend/modules/reaction/migrations"
+	roleMigrations "backend/modules/role/migrations"
+	userMigrations "backend/modules/user/migrations"
 	user "backend/modules/user/models"
 
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"log"
 )
 
@@ -32,40 +38,104 @@ func InitAdminDB() {
 	log.Println("Successfully connected to the database")
 	db := GetDB()
 
+	if err := PrepareLoginAttemptsKey(db); err != nil {
+		log.Fatalf("Failed to prepare login attempts: %v", err)
+	}
+
 	// Виконання міграцій для таблиць
-	err = db.AutoMigrate(&user.User{}, &entities.Tenant{}, &entities.LoginAttempt{})
+	err = db.AutoMigrate(
+		&user.User{},
+		&entities.Tenant{},
+		&entities.LoginAttempt{},
+		&entities.ProvisioningJob{},
+		&entities.PlatformAdmin{},
+		&entities.TenantDomain{},
+		&entities.TenantSettings{},
+		&entities.TenantUsage{},
+		&entities.TenantQuota{},
+		&entities.TenantReplica{},
+	)
 	if err != nil {
 		log.Fatalf("Failed to migrate: %v", err)
 	}
+	// Спроби входу тепер рахуються в межах тентанта; старі глобальні
+	// записи (разом з безстроковими блокуваннями) більше не діють
+	if err := db.Where("tenant_id IS NULL").Delete(&entities.LoginAttempt{}).Error; err != nil {
+		log.Fatalf("Failed to clean up login attempts: %v", err)
+	}
+	if err := InstallTenantChangeTriggers(); err != nil {
+		log.Fatalf("Failed to install tenant change triggers: %v", err)
+	}
 	fmt.Println("Successfully migrated the database")
 
 }
 
-func InitDB(ctx *gin.Context) {
+// PrepareLoginAttemptsKey готує таблицю до унікального ключа: прибирає дублікати
+// лічильників (лишається найсвіжіший) і старий неунікальний індекс.
+// У старій схемі ще немає tenant_id і scope: їх додасть AutoMigrate, а всі
+// старі записи отримають tenant_id NULL (унікальному індексу не заважають)
+// і видаляються одразу після міграції — дедуплікувати там нічого.
+func PrepareLoginAttemptsKey(db *gorm.DB) error {
+	schema := db.Migrator()
+	if !schema.HasTable(&entities.LoginAttempt{}) {
+		return nil
+	}
+	if !schema.HasColumn(&entities.LoginAttempt{}, "TenantID") || !schema.HasColumn(&entities.LoginAttempt{}, "Scope") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`DELETE FROM login_attempts a USING login_attempts b
+			WHERE a.tenant_id = b.tenant_id AND a.email = b.email AND a.ip = b.ip AND a.scope = b.scope
+			AND (a.updated_at, a.id) < (b.updated_at, b.id)`).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec("DROP INDEX IF EXISTS idx_login_attempt_key").Error
+	})
+}
+
+// TenantMigrations повертає всі міграції тентанта в порядку виконання.
+// Порядок модулів важливий: таблиці з зовнішніми ключами йдуть після users.
+func TenantMigrations() []migrator.Migration {
+	var all []migrator.Migration
+	for _, module := range [][]migrator.Migration{
+		// Журнал першим: міграції даних інших модулів уже пишуть у нього
+		auditMigrations.Migrations,
+		userMigrations.Migrations,
+		roleMigrations.Migrations,
+		invitationMigrations.Migrations,
+		employees.Migrations,
+		calendar.Migrations,
+		blog.Migrations,
+		media.Migrations,
+		item.Migrations,
+		property.Migrations,
+		chat.Migrations,
+		direct.Migrations,
+		reactions.Migrations,
+	} {
+		all = append(all, module...)
+	}
+	return all
+}
+
+// InitDB мігрує БД тентанта з контексту запиту
+func InitDB(ctx *gin.Context, dryRun bool) ([]migrator.Step, error) {
 	db, ok := utils.GetDBFromContext(ctx)
 	if !ok {
-		log.Println("Database not found in context")
-		return
+		return nil, errors.New("database not found in context")
 	}
 
+	return migrator.Up(db, TenantMigrations(), dryRun)
+}
+
+// MigrateTenant виконує міграції таблиць тентанта на переданому підключенні
+func MigrateTenant(db *gorm.DB) error {
 	log.Println("Running tenant-specific migrations...")
 
-	err := db.AutoMigrate(
-		&user.User{},
-		&employees.Employees{},
-		&calendar.Calendar{},
-		&blog.Blog{},
-		&media.Media{},
-		&item.Items{},
-		&property.Property{},
-		&chatRooms.ChatRooms{},
-		&messages.Messages{},
-		&directMessage.DirectMessage{},
-		&directMessage.DirectChat{},
-		&messages.MessageRead{},
-		&reactions.Reaction{},
-	)
-	if err != nil {
-		log.Fatalf("Failed to migrate: %v", err)
+	steps, err := migrator.Up(db, TenantMigrations(), false)
+	for _, step := range steps {
+		log.Printf("✅ Applied migration %s", step.ID)
 	}
+	return err
 }
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gorm.io/gorm"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TenantChangesChannel — канал NOTIFY, через який інстанси дізнаються про зміни тентантів
+const TenantChangesChannel = "tenant_changes"
+
+// tenantConnectionColumns — колонки tenants, від яких залежить пул з'єднань
+// (DSN і ключ пулу); зміна інших колонок лише оновлює кешований запис
+const tenantConnectionColumns = `'domain', 'db_host', 'db_port', 'db_user', 'db_password', 'db_name', 'isolation_mode', 'schema_name'`
+
+// tenantChangesFunction публікує зміни tenants, tenant_settings, tenant_domains і tenant_replicas.
+// Payload: {"table", "domain", "old_domain", "host", "old_host", "reconnect"}.
+const tenantChangesFunction = `
+CREATE OR REPLACE FUNCTION notify_tenant_changes() RETURNS trigger AS $$
+DECLARE
+	payload jsonb;
+	row_data jsonb;
+	old_data jsonb;
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		row_data := to_jsonb(OLD);
+	ELSE
+		row_data := to_jsonb(NEW);
+	END IF;
+	IF TG_OP <> 'INSERT' THEN
+		old_data := to_jsonb(OLD);
+	END IF;
+
+	IF TG_TABLE_NAME = 'tenants' THEN
+		payload := jsonb_build_object(
+			'table', TG_TABLE_NAME,
+			'domain', row_data->>'domain',
+			'old_domain', old_data->>'domain',
+			'reconnect', TG_OP <> 'UPDATE' OR EXISTS (
+				SELECT 1 FROM unnest(ARRAY[` + tenantConnectionColumns + `]) AS col
+				WHERE old_data->col IS DISTINCT FROM row_data->col));
+	ELSIF TG_TABLE_NAME = 'tenant_settings' THEN
+		payload := jsonb_build_object(
+			'table', TG_TABLE_NAME,
+			'domain', (SELECT domain FROM tenants WHERE id = (row_data->>'tenant_id')::uuid),
+			'reconnect', false);
+	ELSIF TG_TABLE_NAME = 'tenant_replicas' THEN
+		payload := jsonb_build_object(
+			'table', TG_TABLE_NAME,
+			'domain', (SELECT domain FROM tenants WHERE id = (row_data->>'tenant_id')::uuid),
+			'reconnect', true);
+	ELSE
+		payload := jsonb_build_object(
+			'table', TG_TABLE_NAME,
+			'host', row_data->>'host',
+			'old_host', old_data->>'host');
+	END IF;
+
+	PERFORM pg_notify('` + TenantChangesChannel + `', payload::text);
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql`
+
+var notifiedTables = []string{"tenants", "tenant_settings", "tenant_domains", "tenant_replicas"}
+
+type tenantChange struct {
+	Table     string `json:"table"`
+	Domain    string `json:"domain"`
+	OldDomain string `json:"old_domain"`
+	Host      string `json:"host"`
+	OldHost   string `json:"old_host"`
+	Reconnect bool   `json:"reconnect"`
+}
+
+var listenerConnected atomic.Bool
+
+// InstallTenantChangeTriggers створює тригери NOTIFY в адмінській БД
+func InstallTenantChangeTriggers() error {
+	statements := []string{tenantChangesFunction}
+	for _, table := range notifiedTables {
+		trigger := quoteIdent(table + "_notify_changes")
+		statements = append(statements,
+			fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", trigger, quoteIdent(table)),
+			fmt.Sprintf("CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE FUNCTION notify_tenant_changes()",
+				trigger, quoteIdent(table)),
+		)
+	}
+
+	return GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, sql := range statements {
+			if err := tx.Exec(sql).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListenerConnected — чи активна підписка на зміни (інакше працює лише TTL кешу)
+func ListenerConnected() bool {
+	return listenerConnected.Load()
+}
+
+// StartTenantChangeListener підписується на канал змін і скидає кеш тентантів
+// та пул з'єднань одразу після змін на будь-якому інстансі. Якщо підключення
+// обривається, кеш продовжує жити за TTL, а слухач перепідключається з паузою.
+func StartTenantChangeListener(ctx context.Context) {
+	go func() {
+		backoff := time.Second
+		for {
+			err := listenTenantChanges(ctx)
+			listenerConnected.Store(false)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("⚠️ Tenant change listener disconnected: %v (retry in %s)", err, backoff)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < time.Minute {
+				backoff *= 2
+			}
+		}
+	}()
+}
+
+func listenTenantChanges(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, adminDSN())
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+TenantChangesChannel); err != nil {
+		return err
+	}
+
+	// Поки слухача не було, могли пропустити зміни — скидаємо кеш повністю
+	Manager.ClearAll()
+	listenerConnected.Store(true)
+	log.Println("📡 Listening for tenant changes")
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		applyTenantChange(notification.Payload)
+	}
+}
+
+func applyTenantChange(payload string) {
+	var change tenantChange
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		log.Printf("❌ Invalid tenant change payload %q: %v", payload, err)
+		return
+	}
+
+	switch change.Table {
+	case "tenant_domains":
+		ClearHostCache(change.Host, "")
+		ClearHostCache(change.OldHost, "")
+	default:
+		for _, domain := range []string{change.Domain, change.OldDomain} {
+			if domain == "" {
+				continue
+			}
+			if change.Reconnect {
+				Manager.ClearTenantCache(domain)
+			} else {
+				Manager.InvalidateTenant(domain)
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"fmt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"os"
+	"strings"
+	"sync"
+)
+
+// quoteIdent екранує ідентифікатор Postgres (ім'я ролі чи бази)
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral екранує рядковий літерал Postgres
+func quoteLiteral(value string) string {
+	return `'` + strings.ReplaceAll(value, `'`, `''`) + `'`
+}
+
+// CreateTenantRole створює роль, від імені якої тентант підключається до своєї БД
+func CreateTenantRole(user, password string) error {
+	sql := fmt.Sprintf("CREATE ROLE %s LOGIN PASSWORD %s", quoteIdent(user), quoteLiteral(password))
+	if err := GetDB().Exec(sql).Error; err != nil {
+		return fmt.Errorf("create role %s: %w", user, err)
+	}
+	return nil
+}
+
+// DropTenantRole видаляє роль тентанта
+func DropTenantRole(user string) error {
+	if err := GetDB().Exec("DROP ROLE IF EXISTS " + quoteIdent(user)).Error; err != nil {
+		return fmt.Errorf("drop role %s: %w", user, err)
+	}
+	return nil
+}
+
+// CreateTenantDatabase створює окрему БД тентанта з власником owner
+// і вмикає розширення, на які розраховують моделі (uuid_generate_v4)
+func CreateTenantDatabase(name, owner string) error {
+	sql := fmt.Sprintf("CREATE DATABASE %s OWNER %s", quoteIdent(name), quoteIdent(owner))
+	if err := GetDB().Exec(sql).Error; err != nil {
+		return fmt.Errorf("create database %s: %w", name, err)
+	}
+
+	// Розширення створюємо від імені адміністратора, бо роль тентанта не має таких прав
+	db, err := openAdminDBFor(name)
+	if err != nil {
+		return err
+	}
+	defer closeDB(db)
+
+	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`).Error; err != nil {
+		return fmt.Errorf("create extension in %s: %w", name, err)
+	}
+	return nil
+}
+
+// DropTenantDatabase видаляє БД тентанта, примусово розриваючи активні підключення
+func DropTenantDatabase(name string) error {
+	if err := GetDB().Exec("DROP DATABASE IF EXISTS " + quoteIdent(name) + " WITH (FORCE)").Error; err != nil {
+		return fmt.Errorf("drop database %s: %w", name, err)
+	}
+	return nil
+}
+
+// openAdminDBFor підключається до бази dbName з обліковими даними адмін-БД
+func openAdminDBFor(dbName string) (*gorm.DB, error) {
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
+		os.Getenv("POSTGRES_SERVER"),
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		dbName,
+		os.Getenv("POSTGRES_PORT"),
+		os.Getenv("POSTGRES_SSLMODE"),
+		os.Getenv("POSTGRES_TIMEZONE"),
+	)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s as admin: %w", dbName, err)
+	}
+	return db, nil
+}
+
+// closeDB закриває пул з'єднань *gorm.DB
+func closeDB(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
+
+// CloseTenantDB закриває пул з'єднань, відкритий через OpenTenantDB
+func CloseTenantDB(db *gorm.DB) {
+	if db != nil {
+		closeDB(db)
+	}
+}
+
+var sharedDBMu sync.Mutex
+
+// SharedTenantDBName — спільна БД для тентантів у режимі schema
+func SharedTenantDBName() string {
+	if name := os.Getenv("TENANT_SHARED_DB_NAME"); name != "" {
+		return name
+	}
+	return "tenants_shared"
+}
+
+// ensureSharedTenantDatabase створює спільну БД при першому тентанті в режимі schema
+func ensureSharedTenantDatabase() error {
+	sharedDBMu.Lock()
+	defer sharedDBMu.Unlock()
+
+	name := SharedTenantDBName()
+	var count int64
+	if err := GetDB().Raw("SELECT COUNT(*) FROM pg_database WHERE datname = ?", name).Scan(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if err := GetDB().Exec("CREATE DATABASE " + quoteIdent(name)).Error; err != nil {
+		return fmt.Errorf("create shared database %s: %w", name, err)
+	}
+	db, err := openAdminDBFor(name)
+	if err != nil {
+		return err
+	}
+	defer closeDB(db)
+
+	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`).Error; err != nil {
+		return fmt.Errorf("create extension in %s: %w", name, err)
+	}
+	return nil
+}
+
+// CreateTenantSchema створює схему тентанта у спільній БД, власником якої є
+// роль тентанта; інші тентанти до неї доступу не мають
+func CreateTenantSchema(schema, owner string) error {
+	if err := ensureSharedTenantDatabase(); err != nil {
+		return err
+	}
+	db, err := openAdminDBFor(SharedTenantDBName())
+	if err != nil {
+		return err
+	}
+	defer closeDB(db)
+
+	for _, sql := range TenantSchemaStatements(SharedTenantDBName(), schema, owner) {
+		if err := db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("create schema %s: %w", schema, err)
+		}
+	}
+	return nil
+}
+
+// TenantSchemaStatements — SQL для створення схеми тентанта у спільній БД.
+// public лишається в search_path заради uuid-ossp, тому CREATE у ній
+// відкликається при кожному створенні схеми (в тому числі для вже існуючої БД),
+// інакше тентанти могли б підкладати одне одному таблиці й функції в public
+func TenantSchemaStatements(dbName, schema, owner string) []string {
+	return []string{
+		"REVOKE CREATE ON SCHEMA public FROM PUBLIC",
+		fmt.Sprintf("GRANT CONNECT ON DATABASE %s TO %s", quoteIdent(dbName), quoteIdent(owner)),
+		fmt.Sprintf("CREATE SCHEMA %s AUTHORIZATION %s", quoteIdent(schema), quoteIdent(owner)),
+		fmt.Sprintf("REVOKE ALL ON SCHEMA %s FROM PUBLIC", quoteIdent(schema)),
+	}
+}
+
+// DropTenantSchema видаляє схему тентанта з усіма таблицями і відкликає доступ
+// ролі до спільної БД, щоб її можна було видалити
+func DropTenantSchema(schema, owner string) error {
+	db, err := openAdminDBFor(SharedTenantDBName())
+	if err != nil {
+		return err
+	}
+	defer closeDB(db)
+
+	if err := db.Exec("DROP SCHEMA IF EXISTS " + quoteIdent(schema) + " CASCADE").Error; err != nil {
+		return fmt.Errorf("drop schema %s: %w", schema, err)
+	}
+	sql := fmt.Sprintf("REVOKE ALL ON DATABASE %s FROM %s", quoteIdent(SharedTenantDBName()), quoteIdent(owner))
+	if err := db.Exec(sql).Error; err != nil && !strings.Contains(err.Error(), "does not exist") {
+		return fmt.Errorf("revoke shared database access: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"backend/internal/entities"
+	"backend/internal/services/utils"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// lagCheckTTL — як довго вважаємо виміряне відставання репліки актуальним
+const lagCheckTTL = 5 * time.Second
+
+// lagSampleMaxAge — заміри, старші за цей вік, видаляються (репліку прибрали
+// або тентант більше не читає з неї)
+const lagSampleMaxAge = time.Minute
+
+// replicaLagSQL повертає відставання репліки у секундах відносно позиції WAL
+// основної БД (параметр). Репліка, що програла WAL до цієї позиції, не відстає,
+// навіть коли на основній БД давно не було записів; якщо ж вона відірвалась
+// від основної, позиція не наздоганяється і відставання росте з часом
+const replicaLagSQL = `
+SELECT CASE
+	WHEN NOT pg_is_in_recovery() THEN 0
+	WHEN pg_last_wal_replay_lsn() >= ?::pg_lsn THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())::float8, 'Infinity'::float8)
+END`
+
+type lagSample struct {
+	seconds   float64
+	err       error
+	checkedAt time.Time
+}
+
+var (
+	lagMu   sync.Mutex
+	lagByID = make(map[uuid.UUID]lagSample)
+)
+
+func loadTenantReplicas(tenantID uuid.UUID) ([]entities.TenantReplica, error) {
+	var replicas []entities.TenantReplica
+	err := GetDB().
+		Where("tenant_id = ? AND enabled = ?", tenantID, true).
+		Order("priority ASC, created_at ASC").
+		Find(&replicas).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tenant replicas: %w", err)
+	}
+	return replicas, nil
+}
+
+func replicaPoolKey(domain string, replicaID uuid.UUID) string {
+	return domain + "#replica:" + replicaID.String()
+}
+
+// HasReplicas — чи оголошені в тентанта репліки (за даними кешу)
+func (m *DBManager) HasReplicas(domain string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.tenantCache[domain].Replicas) > 0
+}
+
+// GetReadConnectionByDomain повертає підключення лише для читання: першу за пріоритетом
+// репліку, що доступна і не відстає більше за MaxLagSeconds, інакше — основну БД
+func (m *DBManager) GetReadConnectionByDomain(domain string) (*gorm.DB, error) {
+	cachedTenant, err := m.cached(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, replica := range cachedTenant.Replicas {
+		db, err := m.replicaConnection(&cachedTenant, replica)
+		if err != nil {
+			log.Printf("⚠️ Replica %s of %s unavailable: %v", replica.ID, domain, err)
+			continue
+		}
+		lag, err := m.replicaLag(domain, db, replica.ID)
+		if err != nil {
+			log.Printf("⚠️ Replica %s of %s lag check failed: %v", replica.ID, domain, err)
+			continue
+		}
+		if lag > float64(replica.MaxLagSeconds) {
+			continue
+		}
+		return db, nil
+	}
+
+	return m.GetConnectionByDomain(domain)
+}
+
+func (m *DBManager) replicaConnection(cachedTenant *CachedTenant, replica entities.TenantReplica) (*gorm.DB, error) {
+	if !cachedTenant.Tenant.Status {
+		return nil, fmt.Errorf("tenant inactive")
+	}
+	tenant := cachedTenant.Tenant
+	timezone := cachedTenant.Settings.Location().String()
+
+	return Pool.GetOrOpen(replicaPoolKey(tenant.Domain, replica.ID), func() (*gorm.DB, error) {
+		creds, err := utils.DecryptCreds(replica.DBUser, replica.DBName, replica.DBHost, replica.DBPassword)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt replica credentials: %w", err)
+		}
+		port := replica.DBPort
+		if port == "" {
+			port = tenant.DBPort
+		}
+		return openTenantDSN(&tenant, creds, port, timezone)
+	})
+}
+
+// replicaLag вимірює відставання репліки, кешуючи результат на lagCheckTTL
+func (m *DBManager) replicaLag(domain string, db *gorm.DB, replicaID uuid.UUID) (float64, error) {
+	lagMu.Lock()
+	sample, ok := lagByID[replicaID]
+	lagMu.Unlock()
+	if ok && time.Since(sample.checkedAt) < lagCheckTTL {
+		return sample.seconds, sample.err
+	}
+
+	sample = lagSample{checkedAt: time.Now()}
+	sample.seconds, sample.err = m.measureReplicaLag(domain, db)
+
+	lagMu.Lock()
+	for id, old := range lagByID {
+		if time.Since(old.checkedAt) > lagSampleMaxAge {
+			delete(lagByID, id)
+		}
+	}
+	lagByID[replicaID] = sample
+	lagMu.Unlock()
+	return sample.seconds, sample.err
+}
+
+// measureReplicaLag спершу бере позицію WAL основної БД, потім порівнює з нею репліку
+func (m *DBManager) measureReplicaLag(domain string, replica *gorm.DB) (float64, error) {
+	primary, err := m.GetConnectionByDomain(domain)
+	if err != nil {
+		return 0, err
+	}
+	var primaryLSN string
+	if err := primary.Raw("SELECT pg_current_wal_lsn()::text").Scan(&primaryLSN).Error; err != nil {
+		return 0, fmt.Errorf("primary WAL position: %w", err)
+	}
+	var seconds float64
+	if err := replica.Raw(replicaLagSQL, primaryLSN).Scan(&seconds).Error; err != nil {
+		return 0, err
+	}
+	return seconds, nil
+}
+
+// forgetReplicaLag прибирає заміри реплік, які більше не обслуговуються
+func forgetReplicaLag(replicas []entities.TenantReplica) {
+	lagMu.Lock()
+	defer lagMu.Unlock()
+	for _, replica := range replicas {
+		delete(lagByID, replica.ID)
+	}
+}
+
+// ReplicaLag повертає останнє виміряне відставання репліки (для діагностики)
+func ReplicaLag(replicaID uuid.UUID) (float64, bool) {
+	lagMu.Lock()
+	defer lagMu.Unlock()
+	sample, ok := lagByID[replicaID]
+	// Нескінченне відставання (репліка ще нічого не програла) JSON не передасть
+	if !ok || sample.err != nil || math.IsInf(sample.seconds, 0) {
+		return 0, false
+	}
+	return sample.seconds, true
+}
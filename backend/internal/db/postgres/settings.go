@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"backend/internal/entities"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoadTenantSettings читає налаштування тентанта; якщо їх ще немає — повертає типові
+func LoadTenantSettings(tenantID uuid.UUID) (entities.TenantSettings, error) {
+	var settings entities.TenantSettings
+	err := GetDB().Where("tenant_id = ?", tenantID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.DefaultTenantSettings(tenantID), nil
+	}
+	if err != nil {
+		return settings, fmt.Errorf("failed to load tenant settings: %w", err)
+	}
+	return settings, nil
+}
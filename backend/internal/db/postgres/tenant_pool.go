@@ -1,37 +1,391 @@
 package postgres
 
 import (
+	"container/list"
+	"context"
+	"fmt"
+	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
+	"log"
+	"os"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// closeGracePeriod — скільки чекаємо перед закриттям витісненого пулу,
+// щоб горутини, які отримали *gorm.DB без закріплення, встигли завершитися.
+// Закріплені через Acquire пули закриваються лише після останнього release.
+const closeGracePeriod = 30 * time.Second
+
+type PoolConfig struct {
+	MaxOpenPerTenant int
+	MaxIdlePerTenant int
+	GlobalMaxOpen    int
+	ConnMaxLifetime  time.Duration
+	IdleTimeout      time.Duration
+	HealthInterval   time.Duration
+}
+
+// PoolConfigFromEnv читає ліміти пулу з оточення, підставляючи значення за замовчуванням
+func PoolConfigFromEnv() PoolConfig {
+	return PoolConfig{
+		MaxOpenPerTenant: envInt("TENANT_DB_MAX_OPEN_CONNS", 5),
+		MaxIdlePerTenant: envInt("TENANT_DB_MAX_IDLE_CONNS", 2),
+		GlobalMaxOpen:    envInt("TENANT_DB_GLOBAL_MAX_CONNS", 100),
+		ConnMaxLifetime:  time.Duration(envInt("TENANT_DB_CONN_MAX_LIFETIME_SEC", 1800)) * time.Second,
+		IdleTimeout:      time.Duration(envInt("TENANT_POOL_IDLE_TIMEOUT_SEC", 900)) * time.Second,
+		HealthInterval:   time.Duration(envInt("TENANT_POOL_HEALTH_INTERVAL_SEC", 30)) * time.Second,
+	}
+}
+
+func envInt(name string, def int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+	return def
+}
+
+type poolEntry struct {
+	domain   string
+	db       *gorm.DB
+	openedAt time.Time
+	lastUsed time.Time
+	elem     *list.Element
+	refs     int  // скільки підключень (запитів, WebSocket, SSE) тримають пул
+	retired  bool // вилучено з кешу; закриється, коли refs стане 0
+}
+
 type tenantPool struct {
-	mu   sync.RWMutex
-	pool map[string]*gorm.DB
+	mu    sync.Mutex
+	cfg   PoolConfig
+	pool  map[string]*poolEntry
+	lru   *list.List // спереду — нещодавно використані
+	group singleflight.Group
+	once  sync.Once
+
+	hits               atomic.Int64
+	misses             atomic.Int64
+	openErrors         atomic.Int64
+	idleEvictions      atomic.Int64
+	budgetEvictions    atomic.Int64
+	unhealthyEvictions atomic.Int64
 }
 
 var Pool = &tenantPool{
-	pool: make(map[string]*gorm.DB),
+	cfg:  PoolConfigFromEnv(),
+	pool: make(map[string]*poolEntry),
+	lru:  list.New(),
+}
+
+// Configure змінює ліміти пулу. Викликається до обслуговування запитів.
+func (tp *tenantPool) Configure(cfg PoolConfig) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	tp.cfg = cfg
+}
+
+// maxTenants — скільки пулів тентантів вміщається в глобальний бюджет з'єднань
+func (tp *tenantPool) maxTenants() int {
+	if tp.cfg.MaxOpenPerTenant <= 0 {
+		return tp.cfg.GlobalMaxOpen
+	}
+	n := tp.cfg.GlobalMaxOpen / tp.cfg.MaxOpenPerTenant
+	if n < 1 {
+		n = 1
+	}
+	return n
 }
 
 // Get returns *gorm.DB from cache if exists
 func (tp *tenantPool) Get(domain string) (*gorm.DB, bool) {
-	tp.mu.RLock()
-	defer tp.mu.RUnlock()
-	conn, ok := tp.pool[domain]
-	return conn, ok
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	entry, ok := tp.pool[domain]
+	if !ok {
+		return nil, false
+	}
+	entry.lastUsed = time.Now()
+	tp.lru.MoveToFront(entry.elem)
+	return entry.db, true
+}
+
+// GetOrOpen повертає кешоване підключення або відкриває нове.
+// Паралельні запити до одного домену чекають на одне відкриття.
+func (tp *tenantPool) GetOrOpen(domain string, open func() (*gorm.DB, error)) (*gorm.DB, error) {
+	if db, ok := tp.Get(domain); ok {
+		tp.hits.Add(1)
+		return db, nil
+	}
+
+	result, err, _ := tp.group.Do(domain, func() (interface{}, error) {
+		if db, ok := tp.Get(domain); ok {
+			return db, nil
+		}
+		tp.misses.Add(1)
+
+		db, err := open()
+		if err != nil {
+			tp.openErrors.Add(1)
+			return nil, err
+		}
+		tp.Set(domain, db)
+		return db, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*gorm.DB), nil
 }
 
-// Set caches *gorm.DB for domain
+// Acquire повертає пул тентанта і закріплює його: поки не викликано release,
+// витіснення лише прибирає пул з кешу, але не закриває його
+func (tp *tenantPool) Acquire(domain string, open func() (*gorm.DB, error)) (*gorm.DB, func(), error) {
+	if db, release, ok := tp.pin(domain); ok {
+		tp.hits.Add(1)
+		return db, release, nil
+	}
+	if _, err := tp.GetOrOpen(domain, open); err != nil {
+		return nil, nil, err
+	}
+	if db, release, ok := tp.pin(domain); ok {
+		return db, release, nil
+	}
+	return nil, nil, fmt.Errorf("tenant pool %s was evicted while opening", domain)
+}
+
+func (tp *tenantPool) pin(domain string) (*gorm.DB, func(), bool) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	entry, ok := tp.pool[domain]
+	if !ok {
+		return nil, nil, false
+	}
+	entry.refs++
+	entry.lastUsed = time.Now()
+	tp.lru.MoveToFront(entry.elem)
+
+	var once sync.Once
+	return entry.db, func() { once.Do(func() { tp.release(entry) }) }, true
+}
+
+func (tp *tenantPool) release(entry *poolEntry) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	entry.refs--
+	entry.lastUsed = time.Now()
+	if entry.refs == 0 && entry.retired {
+		log.Printf("🔒 Closing retired tenant pool %s", entry.domain)
+		go closeDB(entry.db)
+	}
+}
+
+// Set caches *gorm.DB for domain, застосовуючи ліміти з'єднань
 func (tp *tenantPool) Set(domain string, db *gorm.DB) {
 	tp.mu.Lock()
 	defer tp.mu.Unlock()
-	tp.pool[domain] = db
+
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.SetMaxOpenConns(tp.cfg.MaxOpenPerTenant)
+		sqlDB.SetMaxIdleConns(tp.cfg.MaxIdlePerTenant)
+		sqlDB.SetConnMaxLifetime(tp.cfg.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(tp.cfg.IdleTimeout)
+	}
+
+	if old, ok := tp.pool[domain]; ok {
+		tp.removeLocked(old, true)
+	}
+
+	// Звільняємо місце в глобальному бюджеті, витісняючи найдавніше використані пули
+	for len(tp.pool) >= tp.maxTenants() {
+		victim := tp.oldestUnpinnedLocked()
+		if victim == nil {
+			log.Printf("⚠️ All tenant pools are in use, opening %s over the connection budget", domain)
+			break
+		}
+		log.Printf("♻️ Evicting tenant pool %s (connection budget)", victim.domain)
+		tp.removeLocked(victim, true)
+		tp.budgetEvictions.Add(1)
+	}
+
+	now := time.Now()
+	entry := &poolEntry{domain: domain, db: db, openedAt: now, lastUsed: now}
+	entry.elem = tp.lru.PushFront(entry)
+	tp.pool[domain] = entry
 }
 
-// Delete removes connection from cache
+// Delete removes connection from cache and closes it
 func (tp *tenantPool) Delete(domain string) {
 	tp.mu.Lock()
 	defer tp.mu.Unlock()
-	delete(tp.pool, domain)
+	if entry, ok := tp.pool[domain]; ok {
+		tp.removeLocked(entry, true)
+	}
+}
+
+// oldestUnpinnedLocked — найдавніше використаний пул, який зараз ніхто не тримає
+func (tp *tenantPool) oldestUnpinnedLocked() *poolEntry {
+	for elem := tp.lru.Back(); elem != nil; elem = elem.Prev() {
+		if entry := elem.Value.(*poolEntry); entry.refs == 0 {
+			return entry
+		}
+	}
+	return nil
+}
+
+func (tp *tenantPool) removeLocked(entry *poolEntry, graceful bool) {
+	delete(tp.pool, entry.domain)
+	tp.lru.Remove(entry.elem)
+
+	// Закритий пул зламав би живі WebSocket/SSE — закриємо його після останнього release
+	if entry.refs > 0 {
+		entry.retired = true
+		return
+	}
+	if graceful {
+		time.AfterFunc(closeGracePeriod, func() { closeDB(entry.db) })
+		return
+	}
+	closeDB(entry.db)
+}
+
+// StartMaintenance запускає фонову перевірку: витіснення неактивних пулів і пінг живих
+func (tp *tenantPool) StartMaintenance() {
+	tp.once.Do(func() {
+		go func() {
+			ticker := time.NewTicker(tp.cfg.HealthInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				tp.evictIdle()
+				tp.checkHealth()
+			}
+		}()
+	})
+}
+
+func (tp *tenantPool) evictIdle() {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	deadline := time.Now().Add(-tp.cfg.IdleTimeout)
+	for elem := tp.lru.Back(); elem != nil; {
+		entry := elem.Value.(*poolEntry)
+		prev := elem.Prev()
+		if entry.lastUsed.After(deadline) {
+			break
+		}
+		if entry.refs > 0 {
+			elem = prev
+			continue
+		}
+		log.Printf("💤 Evicting idle tenant pool %s", entry.domain)
+		tp.removeLocked(entry, true)
+		tp.idleEvictions.Add(1)
+		elem = prev
+	}
+}
+
+func (tp *tenantPool) checkHealth() {
+	tp.mu.Lock()
+	entries := make([]*poolEntry, 0, len(tp.pool))
+	for _, entry := range tp.pool {
+		entries = append(entries, entry)
+	}
+	tp.mu.Unlock()
+
+	for _, entry := range entries {
+		if err := pingDB(entry.db); err != nil {
+			log.Printf("❌ Tenant pool %s is unhealthy, evicting: %v", entry.domain, err)
+			tp.mu.Lock()
+			if current, ok := tp.pool[entry.domain]; ok && current == entry {
+				tp.removeLocked(entry, false)
+				tp.unhealthyEvictions.Add(1)
+			}
+			tp.mu.Unlock()
+		}
+	}
+}
+
+func pingDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
+
+type TenantPoolStats struct {
+	Domain          string    `json:"domain"`
+	OpenedAt        time.Time `json:"opened_at"`
+	LastUsed        time.Time `json:"last_used"`
+	Holders         int       `json:"holders"`
+	OpenConnections int       `json:"open_connections"`
+	InUse           int       `json:"in_use"`
+	Idle            int       `json:"idle"`
+	WaitCount       int64     `json:"wait_count"`
+	WaitDuration    string    `json:"wait_duration"`
+}
+
+type PoolStats struct {
+	Tenants            int               `json:"tenants"`
+	MaxTenants         int               `json:"max_tenants"`
+	MaxOpenPerTenant   int               `json:"max_open_per_tenant"`
+	GlobalMaxOpen      int               `json:"global_max_open"`
+	OpenConnections    int               `json:"open_connections"`
+	Hits               int64             `json:"hits"`
+	Misses             int64             `json:"misses"`
+	OpenErrors         int64             `json:"open_errors"`
+	IdleEvictions      int64             `json:"idle_evictions"`
+	BudgetEvictions    int64             `json:"budget_evictions"`
+	UnhealthyEvictions int64             `json:"unhealthy_evictions"`
+	Entries            []TenantPoolStats `json:"entries"`
+}
+
+// Stats повертає знімок стану пулу для адміністраторів
+func (tp *tenantPool) Stats() PoolStats {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	stats := PoolStats{
+		Tenants:            len(tp.pool),
+		MaxTenants:         tp.maxTenants(),
+		MaxOpenPerTenant:   tp.cfg.MaxOpenPerTenant,
+		GlobalMaxOpen:      tp.cfg.GlobalMaxOpen,
+		Hits:               tp.hits.Load(),
+		Misses:             tp.misses.Load(),
+		OpenErrors:         tp.openErrors.Load(),
+		IdleEvictions:      tp.idleEvictions.Load(),
+		BudgetEvictions:    tp.budgetEvictions.Load(),
+		UnhealthyEvictions: tp.unhealthyEvictions.Load(),
+		Entries:            make([]TenantPoolStats, 0, len(tp.pool)),
+	}
+
+	for elem := tp.lru.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*poolEntry)
+		item := TenantPoolStats{Domain: entry.domain, OpenedAt: entry.openedAt, LastUsed: entry.lastUsed, Holders: entry.refs}
+		if sqlDB, err := entry.db.DB(); err == nil {
+			dbStats := sqlDB.Stats()
+			item.OpenConnections = dbStats.OpenConnections
+			item.InUse = dbStats.InUse
+			item.Idle = dbStats.Idle
+			item.WaitCount = dbStats.WaitCount
+			item.WaitDuration = dbStats.WaitDuration.String()
+			stats.OpenConnections += dbStats.OpenConnections
+		}
+		stats.Entries = append(stats.Entries, item)
+	}
+	return stats
+}
+
+// TenantStats повертає статистику пулу одного тентанта, якщо він відкритий
+func (tp *tenantPool) TenantStats(domain string) (*TenantPoolStats, bool) {
+	for _, entry := range tp.Stats().Entries {
+		if entry.Domain == domain {
+			return &entry, true
+		}
+	}
+	return nil, false
 }
@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+// LoginPolicy — захист входу тентанта від перебору. Незаповнені поля
+// беруться з DefaultLoginPolicy.
+type LoginPolicy struct {
+	// Лічильник помилок скидається, якщо за WindowMinutes не було нових
+	WindowMinutes int `json:"window_minutes"`
+	// Ступені блокування пари email+IP, за зростанням кількості спроб
+	IPSteps []LockoutStep `json:"ip_steps"`
+	// Блокування облікового запису незалежно від IP (перебір з багатьох адрес)
+	AccountMaxAttempts    int `json:"account_max_attempts"`
+	AccountLockoutMinutes int `json:"account_lockout_minutes"`
+	// Лист власнику облікового запису, коли його блокують
+	NotifyOnLockout *bool `json:"notify_on_lockout"`
+}
+
+type LockoutStep struct {
+	Attempts       int `json:"attempts"`
+	LockoutMinutes int `json:"lockout_minutes"`
+}
+
+// Найдовше блокування, яке можна налаштувати
+const maxLockoutMinutes = 30 * 24 * 60
+
+func DefaultLoginPolicy() LoginPolicy {
+	notify := true
+	return LoginPolicy{
+		WindowMinutes: 60,
+		IPSteps: []LockoutStep{
+			{Attempts: 3, LockoutMinutes: 5},
+			{Attempts: 6, LockoutMinutes: 10},
+			{Attempts: 9, LockoutMinutes: 24 * 60},
+		},
+		AccountMaxAttempts:    20,
+		AccountLockoutMinutes: 30,
+		NotifyOnLockout:       &notify,
+	}
+}
+
+// WithDefaults заповнює відсутні поля типовими значеннями
+func (p LoginPolicy) WithDefaults() LoginPolicy {
+	defaults := DefaultLoginPolicy()
+	if p.WindowMinutes == 0 {
+		p.WindowMinutes = defaults.WindowMinutes
+	}
+	if len(p.IPSteps) == 0 {
+		p.IPSteps = defaults.IPSteps
+	}
+	if p.AccountMaxAttempts == 0 {
+		p.AccountMaxAttempts = defaults.AccountMaxAttempts
+	}
+	if p.AccountLockoutMinutes == 0 {
+		p.AccountLockoutMinutes = defaults.AccountLockoutMinutes
+	}
+	if p.NotifyOnLockout == nil {
+		p.NotifyOnLockout = defaults.NotifyOnLockout
+	}
+	return p
+}
+
+func (p LoginPolicy) Validate() error {
+	if p.WindowMinutes < 0 || p.WindowMinutes > 24*60 {
+		return errors.New("login policy window must be between 1 and 1440 minutes")
+	}
+	if len(p.IPSteps) > 5 {
+		return errors.New("login policy allows at most 5 lockout steps")
+	}
+	previous := 0
+	for _, step := range p.IPSteps {
+		if step.Attempts <= previous || step.Attempts > 100 {
+			return errors.New("login policy steps must have increasing attempts up to 100")
+		}
+		if step.LockoutMinutes < 1 || step.LockoutMinutes > maxLockoutMinutes {
+			return errors.New("login policy lockout must be between 1 minute and 30 days")
+		}
+		previous = step.Attempts
+	}
+	if p.AccountMaxAttempts < 0 || p.AccountMaxAttempts > 1000 {
+		return errors.New("login policy account attempts must be between 1 and 1000")
+	}
+	if p.AccountLockoutMinutes < 0 || p.AccountLockoutMinutes > maxLockoutMinutes {
+		return errors.New("login policy lockout must be between 1 minute and 30 days")
+	}
+	return nil
+}
+
+// Window — проміжок, після якого старі помилки забуваються
+func (p LoginPolicy) Window() time.Duration {
+	return time.Duration(p.WindowMinutes) * time.Minute
+}
+
+// IPLockout — тривалість блокування пари email+IP після attempts помилок; 0 — не блокувати
+func (p LoginPolicy) IPLockout(attempts int) time.Duration {
+	var lockout time.Duration
+	for _, step := range p.IPSteps {
+		if attempts >= step.Attempts {
+			lockout = time.Duration(step.LockoutMinutes) * time.Minute
+		}
+	}
+	return lockout
+}
+
+// AccountLockout — тривалість блокування облікового запису; 0 — не блокувати
+func (p LoginPolicy) AccountLockout(attempts int) time.Duration {
+	if attempts < p.AccountMaxAttempts {
+		return 0
+	}
+	return time.Duration(p.AccountLockoutMinutes) * time.Minute
+}
@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// TenantReplica — репліка БД тентанта для читання; облікові дані зашифровані, як у Tenant
+type TenantReplica struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	TenantID   uuid.UUID `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	DBHost     string    `gorm:"not null" json:"-"`
+	DBPort     string    `json:"db_port"`
+	DBUser     string    `gorm:"not null" json:"-"`
+	DBPassword string    `gorm:"not null" json:"-"`
+	DBName     string    `gorm:"not null" json:"-"`
+	// Priority — менше значення опитується першим
+	Priority int `gorm:"not null;default:0" json:"priority"`
+	// MaxLagSeconds — репліка з більшим відставанням пропускається
+	MaxLagSeconds int       `gorm:"not null;default:10" json:"max_lag_seconds"`
+	Enabled       bool      `gorm:"not null;default:true" json:"enabled"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (replica *TenantReplica) BeforeCreate(*gorm.DB) error {
+	if replica.ID == uuid.Nil {
+		replica.ID = uuid.New()
+	}
+	return nil
+}
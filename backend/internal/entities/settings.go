@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	DefaultTimezone = "Europe/Warsaw"
+	DefaultLanguage = "pl"
+)
+
+// TenantSettings — налаштування тентанта, що зберігаються в адмінській БД
+type TenantSettings struct {
+	ID                 uuid.UUID                           `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"-"`
+	TenantID           uuid.UUID                           `gorm:"type:uuid;uniqueIndex;not null" json:"tenant_id"`
+	Timezone           string                              `gorm:"not null;default:Europe/Warsaw" json:"timezone"`
+	DefaultLanguage    string                              `gorm:"not null;default:pl" json:"default_language"`
+	AvailableLanguages datatypes.JSONSlice[string]         `gorm:"type:jsonb" json:"available_languages"`
+	SMTPHost           string                              `json:"smtp_host"`
+	SMTPPort           int                                 `json:"smtp_port"`
+	SMTPUser           string                              `json:"smtp_user"`
+	SMTPPassword       string                              `json:"-"` // зашифрований
+	SMTPFrom           string                              `json:"smtp_from"`
+	SMTPFromName       string                              `json:"smtp_from_name"`
+	BrandName          string                              `json:"brand_name"`
+	LogoURL            string                              `json:"logo_url"`
+	PrimaryColor       string                              `json:"primary_color"`
+	Features           datatypes.JSONType[map[string]bool] `gorm:"type:jsonb" json:"features"`
+	Require2FA         bool                                `gorm:"default:false" json:"require_2fa"` // двофакторна автентифікація обов'язкова для всіх
+	// Вхід через корпоративний IdP (OpenID Connect)
+	OIDCEnabled           bool                            `gorm:"default:false" json:"oidc_enabled"`
+	OIDCIssuer            string                          `json:"oidc_issuer"`
+	OIDCClientID          string                          `json:"oidc_client_id"`
+	OIDCClientSecret      string                          `json:"-"`                                            // зашифрований
+	OIDCAutoProvision     bool                            `gorm:"default:false" json:"oidc_auto_provision"`     // створювати користувача при першому вході
+	OIDCAllowedDomains    datatypes.JSONSlice[string]     `gorm:"type:jsonb" json:"oidc_allowed_domains"`       // обмеження для автоматичного створення
+	PasswordLoginDisabled bool                            `gorm:"default:false" json:"password_login_disabled"` // лише SSO
+	LoginPolicy           datatypes.JSONType[LoginPolicy] `gorm:"type:jsonb;not null;default:'{}'" json:"login_policy"`
+	CreatedAt             time.Time                       `json:"created_at"`
+	UpdatedAt             time.Time                       `json:"updated_at"`
+}
+
+func (settings *TenantSettings) BeforeCreate(*gorm.DB) error {
+	if settings.ID == uuid.Nil {
+		settings.ID = uuid.New()
+	}
+	return nil
+}
+
+// DefaultTenantSettings — значення для тентантів, які ще не мають налаштувань
+func DefaultTenantSettings(tenantID uuid.UUID) TenantSettings {
+	return TenantSettings{
+		TenantID:           tenantID,
+		Timezone:           DefaultTimezone,
+		DefaultLanguage:    DefaultLanguage,
+		AvailableLanguages: datatypes.JSONSlice[string]{DefaultLanguage},
+		Features:           datatypes.NewJSONType(map[string]bool{}),
+	}
+}
+
+// Location повертає часовий пояс тентанта, а при некоректному значенні — типовий
+func (settings TenantSettings) Location() *time.Location {
+	if loc, err := time.LoadLocation(settings.Timezone); err == nil && settings.Timezone != "" {
+		return loc
+	}
+	if loc, err := time.LoadLocation(DefaultTimezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// Language повертає мову, якщо вона доступна тентанту, інакше мову за замовчуванням
+func (settings TenantSettings) Language(requested string) string {
+	fallback := settings.DefaultLanguage
+	if fallback == "" {
+		fallback = DefaultLanguage
+	}
+	if requested == "" {
+		return fallback
+	}
+	if len(settings.AvailableLanguages) == 0 {
+		return requested
+	}
+	for _, lang := range settings.AvailableLanguages {
+		if lang == requested {
+			return requested
+		}
+	}
+	return fallback
+}
+
+// EffectiveLoginPolicy — політика входу з типовими значеннями для незаданих полів
+func (settings TenantSettings) EffectiveLoginPolicy() LoginPolicy {
+	return settings.LoginPolicy.Data().WithDefaults()
+}
+
+// SSOConfigured — чи можна входити через OIDC
+func (settings TenantSettings) SSOConfigured() bool {
+	return settings.OIDCEnabled && settings.OIDCIssuer != "" && settings.OIDCClientID != ""
+}
+
+func (settings TenantSettings) FeatureEnabled(name string) bool {
+	return settings.Features.Data()[name]
+}
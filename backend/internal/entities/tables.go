@@ -17,10 +17,18 @@ type Tenant struct {
 	DBName     string    `json:"db_name"`
 	Migrated   bool      `gorm:"default:false" json:"migrated"`
 	Status     bool      `gorm:"default:false" json:"status"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	// IsolationMode — окрема БД (database) або схема у спільній БД (schema)
+	IsolationMode string `gorm:"not null;default:database" json:"isolation_mode"`
+	SchemaName    string `json:"schema_name,omitempty"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
+const (
+	IsolationDatabase = "database"
+	IsolationSchema   = "schema"
+)
+
 func (attempt *Tenant) BeforeCreate(*gorm.DB) error {
 	if attempt.ID == uuid.Nil {
 		attempt.ID = uuid.New()
@@ -28,10 +36,21 @@ func (attempt *Tenant) BeforeCreate(*gorm.DB) error {
 	return nil
 }
 
+// Області лічильника спроб входу
+const (
+	LoginScopeIP      = "ip"      // пара email+IP
+	LoginScopeAccount = "account" // обліковий запис з будь-якої адреси, IP порожній
+	LoginScopeMFA     = "mfa"     // другий фактор облікового запису, IP порожній
+)
+
+// LoginAttempt — лічильник невдалих входів у межах тентанта; на кожну
+// комбінацію (тентант, email, IP, область) — один рядок
 type LoginAttempt struct {
 	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	Email       string     `gorm:"not null" json:"email"`
-	IP          string     `gorm:"not null" json:"ip"`
+	TenantID    uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_login_attempt_unique" json:"tenant_id"`
+	Email       string     `gorm:"not null;uniqueIndex:idx_login_attempt_unique" json:"email"`
+	IP          string     `gorm:"not null;uniqueIndex:idx_login_attempt_unique" json:"ip"`
+	Scope       string     `gorm:"not null;default:ip;uniqueIndex:idx_login_attempt_unique" json:"scope"`
 	Attempts    int        `gorm:"default:0" json:"attempts"`
 	LastAttempt time.Time  `gorm:"not null" json:"last_attempt"`
 	BannedUntil *time.Time `json:"banned_until"`
@@ -45,3 +64,70 @@ func (attempt *LoginAttempt) BeforeCreate(*gorm.DB) error {
 	}
 	return nil
 }
+
+// ProvisioningJob відстежує створення нового тентанта крок за кроком
+type ProvisioningJob struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	TenantID   *uuid.UUID `gorm:"type:uuid" json:"tenant_id"`
+	Domain     string     `gorm:"not null;index" json:"domain"`
+	Status     string     `gorm:"not null" json:"status"`
+	Step       string     `json:"step"`
+	Progress   int        `gorm:"default:0" json:"progress"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (job *ProvisioningJob) BeforeCreate(*gorm.DB) error {
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	return nil
+}
+
+// PlatformAdmin — оператор платформи, не прив'язаний до жодного тентанта
+type PlatformAdmin struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Email       string     `gorm:"unique;not null" json:"email"`
+	FullName    string     `gorm:"not null" json:"fullName"`
+	Password    string     `gorm:"not null" json:"-"`
+	IsActive    bool       `gorm:"default:true" json:"isActive"`
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (admin *PlatformAdmin) BeforeCreate(*gorm.DB) error {
+	if admin.ID == uuid.Nil {
+		admin.ID = uuid.New()
+	}
+	return nil
+}
+
+const (
+	TenantDomainSubdomain = "subdomain"
+	TenantDomainCustom    = "custom"
+	TenantDomainAlias     = "alias"
+)
+
+// TenantDomain — хост, за яким доступний тентант (субдомен, власний домен або аліас)
+type TenantDomain struct {
+	ID                uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	TenantID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	Host              string     `gorm:"uniqueIndex;not null" json:"host"`
+	Kind              string     `gorm:"not null;default:custom" json:"kind"`
+	Verified          bool       `gorm:"default:false" json:"verified"`
+	VerificationToken string     `gorm:"not null" json:"verification_token"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+func (domain *TenantDomain) BeforeCreate(*gorm.DB) error {
+	if domain.ID == uuid.Nil {
+		domain.ID = uuid.New()
+	}
+	return nil
+}
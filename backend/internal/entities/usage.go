@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// TenantUsage — накопичене значення метрики тентанта за період (перший день місяця)
+type TenantUsage struct {
+	TenantID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"tenant_id"`
+	Metric    string    `gorm:"primaryKey" json:"metric"`
+	Period    time.Time `gorm:"type:date;primaryKey" json:"period"`
+	Value     int64     `gorm:"not null;default:0" json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TenantQuota — ліміт тарифного плану для метрики; відсутній запис означає без обмежень
+type TenantQuota struct {
+	TenantID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"tenant_id"`
+	Metric    string    `gorm:"primaryKey" json:"metric"`
+	MaxValue  int64     `gorm:"not null" json:"limit"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -1,7 +1,12 @@
 package middleware
 
 import (
+	"backend/internal/services/audit"
 	"backend/internal/services/utils"
+	"backend/modules/user/models"
+	"backend/modules/user/repository"
+	"backend/modules/user/service"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strings"
@@ -21,16 +26,79 @@ func AuthMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if strings.HasPrefix(tokenString, models.APIKeyPrefix) {
+			authenticateAPIKey(c, tokenString)
+			return
+		}
 		claims, err := utils.ParseJWTToken(tokenString)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 			c.Abort()
 			return
 		}
+		// Токен має бути прив'язаний до живої сесії: logout, зміна пароля,
+		// видалення чи деактивація користувача діють одразу
+		db, ok := utils.GetDBFromContext(c)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not found in context"})
+			c.Abort()
+			return
+		}
+		active, err := repository.IsSessionActive(db, claims.SessionID, claims.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Cannot verify session"})
+			c.Abort()
+			return
+		}
+		if !active {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "session revoked"})
+			c.Abort()
+			return
+		}
+
 		c.Set("id", claims.ID)
 		c.Set("email", claims.Email)
 		c.Set("tenant", claims.Tenant)
 		c.Set("tenant_id", claims.TenantID)
+		c.Set("sid", claims.SessionID)
+		audit.SetActor(c, claims.ID, claims.Email, nil)
 		c.Next()
 	}
 }
+
+// authenticateAPIKey приймає персональний токен або ключ сервісного акаунта.
+// Сесії в нього немає ("sid" не задається), тож керувати сесіями, паролем
+// і самими ключами через API-ключ не можна; дозволи звужуються до scopes.
+func authenticateAPIKey(c *gin.Context, raw string) {
+	db, ok := utils.GetDBFromContext(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database not found in context"})
+		c.Abort()
+		return
+	}
+	tenant, ok := utils.GetTenantFromContext(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		c.Abort()
+		return
+	}
+	key, user, err := service.AuthenticateAPIKey(db, raw, c.ClientIP())
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Cannot verify api key"})
+		}
+		c.Abort()
+		return
+	}
+
+	c.Set("id", user.ID)
+	c.Set("email", user.Email)
+	c.Set("tenant", tenant.Domain)
+	c.Set("tenant_id", tenant.ID)
+	c.Set("api_key_id", key.ID)
+	c.Set("scopes", []string(key.Scopes))
+	audit.SetActor(c, user.ID, user.Email, &key.ID)
+	c.Next()
+}
@@ -3,24 +3,76 @@ package middleware
 import (
 	"backend/internal/db/postgres"
 	entities2 "backend/internal/entities"
+	"backend/internal/services/utils"
+	"backend/modules/user/repository"
 	"bytes"
 	"encoding/json"
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
+	"github.com/google/uuid"
 	"io"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 )
 
-func LoginLimiterMiddleware() gin.HandlerFunc {
-	db := postgres.DB
+const passwordLoginPath = "/v1/login/access-token"
+
+// Маршрути, де перебір обмежується: помилки пароля рахуються за email+IP
+// і обліковим записом, помилки другого фактора — в окремому лічильнику
+var limitedLoginPaths = map[string]bool{
+	passwordLoginPath:              true,
+	"/v1/login/2fa":                true,
+	"/v1/login/2fa/enroll/confirm": true,
+}
+
+// loginEmail дістає email з тіла запиту; на кроках 2FA — з проміжного токена
+func loginEmail(path string, rawData []byte) (string, bool) {
+	if path == passwordLoginPath {
+		var body entities2.LoginRequest
+		if err := json.Unmarshal(rawData, &body); err != nil || body.Email == "" {
+			return "", false
+		}
+		return body.Email, true
+	}
 
+	var body struct {
+		MFAToken string `json:"mfa_token"`
+	}
+	if err := json.Unmarshal(rawData, &body); err != nil || body.MFAToken == "" {
+		return "", false
+	}
+	claims, err := utils.ParseMFAToken(body.MFAToken)
+	if err != nil {
+		return "", false
+	}
+	return claims.Email, true
+}
+
+// isFailedLogin — невдалою вважаємо лише відмову в автентифікації
+// (невірний пароль чи код, невідомий email), а не помилки запиту чи сервера
+func isFailedLogin(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusNotFound
+}
+
+// LoginLimiterMiddleware блокує перебір за політикою тентанта: окремо пару
+// email+IP і обліковий запис з будь-яких адрес. Працює після TenantMiddleware.
+func LoginLimiterMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if c.Request.Method != http.MethodPost || c.FullPath() != "/v1/login/access-token" {
+		if c.Request.Method != http.MethodPost || !limitedLoginPaths[c.FullPath()] {
 			c.Next()
 			return
 		}
 
+		tenant, ok := utils.GetTenantFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+			return
+		}
+		settings := utils.GetTenantSettingsFromContext(c)
+		policy := settings.EffectiveLoginPolicy()
+
 		// Зчитуємо сире тіло запиту, бо ShouldBindJSON споживає його
 		rawData, err := c.GetRawData()
 		if err != nil {
@@ -31,65 +83,186 @@ func LoginLimiterMiddleware() gin.HandlerFunc {
 		// Відновлюємо тіло запиту для наступного хендлера
 		c.Request.Body = io.NopCloser(bytes.NewBuffer(rawData))
 
-		// Парсимо JSON вручну
-		var body entities2.LoginRequest
-		if err := json.Unmarshal(rawData, &body); err != nil || body.Email == "" {
+		email, ok := loginEmail(c.FullPath(), rawData)
+		if !ok {
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Email is not specified or the request body is incorrect"})
 			return
 		}
+		guard := loginGuard{
+			tenantID:     tenant.ID,
+			label:        "tenant " + tenant.Domain,
+			policy:       policy,
+			secondFactor: c.FullPath() != passwordLoginPath,
+		}
+		if *policy.NotifyOnLockout {
+			guard.onLockout = func(email, ip string, until time.Time) {
+				notifyLockout(c, &settings, email, ip, until)
+			}
+		}
+		guard.run(c, email)
+	}
+}
 
-		ip := c.ClientIP()
-		email := body.Email
+// Спроби входу в консоль платформи рахуються під нульовим tenant_id:
+// жоден тентант не має такого ідентифікатора
+var platformLoginTenantID = uuid.Nil
 
-		var attempt entities2.LoginAttempt
-		result := db.Where("email = ? AND ip = ?", email, ip).First(&attempt)
-		if result.Error != nil && result.Error != gorm.ErrRecordNotFound {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "DB error"})
+// PlatformLoginLimiterMiddleware обмежує перебір паролів операторів платформи
+// за типовою політикою, без листів про блокування
+func PlatformLoginLimiterMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawData, err := c.GetRawData()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
 			return
 		}
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(rawData))
 
-		// Перевірка блокування
-		if attempt.BannedUntil != nil && time.Now().Before(*attempt.BannedUntil) {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error": "Blocked until " + attempt.BannedUntil.Format("15:04:05"),
-			})
+		var body struct {
+			Email string `json:"email"`
+		}
+		if err := json.Unmarshal(rawData, &body); err != nil || body.Email == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid login request"})
 			return
 		}
 
-		// Зберігаємо email для післяобробки
-		c.Set("loginLimiter:email", email)
-		c.Set("loginLimiter:ip", ip)
+		guard := loginGuard{
+			tenantID: platformLoginTenantID,
+			label:    "platform console",
+			policy:   entities2.DefaultLoginPolicy(),
+		}
+		guard.run(c, body.Email)
+	}
+}
+
+// loginGuard — лічильники невдалих входів і блокування для одного простору
+// облікових записів (тентанта або консолі платформи)
+type loginGuard struct {
+	tenantID uuid.UUID
+	label    string
+	policy   entities2.LoginPolicy
+	// secondFactor — крок 2FA: помилки йдуть у лічильник другого фактора,
+	// який вхід паролем не скидає
+	secondFactor bool
+	onLockout    func(email, ip string, until time.Time)
+}
+
+// run перевіряє блокування, виконує хендлер і рахує результат входу
+func (g loginGuard) run(c *gin.Context, email string) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	ip := c.ClientIP()
 
-		c.Next()
+	ipAttempt, err := postgres.GetLoginAttempt(g.tenantID, email, ip, entities2.LoginScopeIP)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "DB error"})
+		return
+	}
+	accountAttempt, err := postgres.GetLoginAttempt(g.tenantID, email, "", entities2.LoginScopeAccount)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "DB error"})
+		return
+	}
+	mfaAttempt, err := postgres.GetLoginAttempt(g.tenantID, email, "", entities2.LoginScopeMFA)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "DB error"})
+		return
+	}
 
-		// Якщо статус OK — скидаємо спроби
-		if c.Writer.Status() == http.StatusOK {
-			db.Where("email = ? AND ip = ?", email, ip).Delete(&entities2.LoginAttempt{})
+	// Перевірка блокування
+	now := time.Now()
+	for _, attempt := range []*entities2.LoginAttempt{accountAttempt, mfaAttempt, ipAttempt} {
+		if attempt.BannedUntil != nil && now.Before(*attempt.BannedUntil) {
+			c.Header("Retry-After", strconv.Itoa(int(attempt.BannedUntil.Sub(now).Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":        "Too many failed login attempts",
+				"banned_until": attempt.BannedUntil,
+			})
 			return
 		}
+	}
+
+	// Зберігаємо email для післяобробки
+	c.Set("loginLimiter:email", email)
+	c.Set("loginLimiter:ip", ip)
+
+	c.Next()
 
-		// Інакше — інкремент
-		attempt.Email = email
-		attempt.IP = ip
-		attempt.LastAttempt = time.Now()
-		attempt.Attempts++
+	// Лічильники скидаються лише після видачі сесії: 200 з викликом 2FA
+	// означає тільки вірний пароль
+	if utils.SessionIssued(c) {
+		if err := postgres.ClearLoginAttempts(g.tenantID, email, ip); err != nil {
+			log.Println("⚠️ Cannot clear login attempts:", err)
+		}
+		if g.secondFactor {
+			if err := postgres.ClearSecondFactorAttempts(g.tenantID, email); err != nil {
+				log.Println("⚠️ Cannot clear second factor attempts:", err)
+			}
+		}
+		return
+	}
+	if !isFailedLogin(c.Writer.Status()) {
+		return
+	}
 
-		switch {
-		case attempt.Attempts >= 9:
-			t := time.Now().AddDate(100, 0, 0)
-			attempt.BannedUntil = &t
-		case attempt.Attempts >= 6:
-			t := time.Now().Add(10 * time.Minute)
-			attempt.BannedUntil = &t
-		case attempt.Attempts >= 3:
-			t := time.Now().Add(5 * time.Minute)
-			attempt.BannedUntil = &t
+	var failed []*entities2.LoginAttempt
+	var accountLocked bool
+	var lockedUntil *time.Time
+	if g.secondFactor {
+		failed = []*entities2.LoginAttempt{mfaAttempt}
+		accountLocked = registerFailure(mfaAttempt, g.policy, g.policy.AccountLockout)
+		lockedUntil = mfaAttempt.BannedUntil
+	} else {
+		failed = []*entities2.LoginAttempt{ipAttempt, accountAttempt}
+		registerFailure(ipAttempt, g.policy, g.policy.IPLockout)
+		accountLocked = registerFailure(accountAttempt, g.policy, g.policy.AccountLockout)
+		lockedUntil = accountAttempt.BannedUntil
+	}
+	for _, attempt := range failed {
+		if err := postgres.SaveLoginAttempt(attempt); err != nil {
+			log.Println("⚠️ Cannot save login attempt:", err)
 		}
+	}
 
-		if result.RowsAffected == 0 {
-			_ = db.Create(&attempt)
-		} else {
-			_ = db.Save(&attempt)
+	if accountLocked {
+		log.Printf("🔒 Account %s locked in %s until %s", email, g.label, lockedUntil.Format(time.RFC3339))
+		if g.onLockout != nil {
+			g.onLockout(email, ip, *lockedUntil)
 		}
 	}
 }
+
+// registerFailure рахує невдалу спробу і призначає блокування за політикою.
+// Повертає true, якщо саме ця спроба заблокувала вхід.
+func registerFailure(attempt *entities2.LoginAttempt, policy entities2.LoginPolicy, lockout func(int) time.Duration) bool {
+	now := time.Now()
+	if !attempt.LastAttempt.IsZero() && now.Sub(attempt.LastAttempt) > policy.Window() {
+		attempt.Attempts = 0
+	}
+	attempt.Attempts++
+	attempt.LastAttempt = now
+
+	duration := lockout(attempt.Attempts)
+	if duration == 0 {
+		return false
+	}
+	until := now.Add(duration)
+	attempt.BannedUntil = &until
+	return true
+}
+
+// notifyLockout надсилає лист лише існуючому користувачу тентанта
+func notifyLockout(c *gin.Context, settings *entities2.TenantSettings, email, ip string, until time.Time) {
+	db, ok := utils.GetDBFromContext(c)
+	if !ok {
+		return
+	}
+	user, err := repository.GetUserByEmail(db, email)
+	if err != nil || !user.IsActive {
+		return
+	}
+	go func() {
+		if err := utils.SendLockoutEmail(settings, user.Email, ip, until); err != nil {
+			log.Println("❌ Cannot send lockout email:", err)
+		}
+	}()
+}
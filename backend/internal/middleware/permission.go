@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"backend/internal/services/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// RequirePermission пропускає запит, лише якщо користувач має всі перелічені
+// дозволи. Ставиться після AuthMiddleware на групу або окремий маршрут.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db, ok := utils.GetDBFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Database not found in context"})
+			return
+		}
+		for _, perm := range permissions {
+			if !utils.HasPermission(c, db, perm) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Permission denied: " + perm})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireSession закриває маршрут для API-ключів: самообслуговування (профіль,
+// сесії) і розділи без окремого дозволу, який міг би звузити scope ключа,
+// доступні лише з інтерактивної сесії
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get("sid"); !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API keys cannot access this route"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -2,10 +2,13 @@ package middleware
 
 import (
 	"backend/internal/db/postgres"
+	"backend/internal/services/audit"
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // 🔹 Окрема утиліта
@@ -17,11 +20,20 @@ func isWebSocketRequest(c *gin.Context) bool {
 // 🔸 Основний middleware
 func TenantMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		host := c.Request.Host
-		host = strings.Split(host, ":")[0]
-		subdomain := strings.Split(host, ".")[0]
+		// Спершу точний збіг хоста (власний домен, аліас), потім субдомен
+		subdomain, err := postgres.Manager.ResolveHost(c.Request.Host)
+		if err != nil {
+			if isWebSocketRequest(c) {
+				c.AbortWithStatus(http.StatusInternalServerError)
+			} else {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Tenant lookup failed"})
+			}
+			return
+		}
 
-		tenantDB, err := postgres.Manager.GetConnectionByDomain(subdomain)
+		// Пул закріплений до кінця запиту: WebSocket і SSE живуть у хендлері,
+		// тому витіснення не закриє підключення посеред розмови
+		tenantDB, release, err := postgres.Manager.AcquireConnectionByDomain(subdomain)
 		if err != nil {
 			// якщо tenant.Status == false — повертаємо 403, інакше 404
 			statusCode := http.StatusNotFound
@@ -37,12 +49,26 @@ func TenantMiddleware() gin.HandlerFunc {
 			}
 			return
 		}
+		defer release()
 
 		// Дістаємо tenant із кешу після підключення
 		tenant := postgres.Manager.TenantFromCache(subdomain)
 
-		c.Set("DB", tenantDB)
+		// Зміни через це підключення потрапляють у журнал аудиту з даними запиту.
+		// Контекст не запитовий: горутини хендлерів можуть жити довше за запит.
+		meta := audit.NewRequestMeta(c, tenant.ID)
+		c.Set("DB", tenantDB.WithContext(audit.WithMeta(context.Background(), meta)))
 		c.Set("tenant", tenant)
+		c.Set("tenantRecord", tenant)
+		c.Set("settings", postgres.Manager.SettingsFromCache(subdomain))
+
+		// Підключення для читання вибирається лише тоді, коли хендлер його просить
+		// (utils.GetReadDBFromContext): перевірка реплік не потрібна кожному запиту
+		if postgres.Manager.HasReplicas(subdomain) {
+			c.Set("DBReadResolver", func() (*gorm.DB, error) {
+				return postgres.Manager.GetReadConnectionByDomain(subdomain)
+			})
+		}
 
 		c.Next()
 	}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"backend/internal/services/metering"
+	"backend/internal/services/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+)
+
+// UsageMiddleware рахує запити до API тентанта і відхиляє їх, коли місячну квоту вичерпано
+func UsageMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenant, ok := utils.GetTenantFromContext(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if err := metering.Check(tenant.ID, metering.APIRequests, 1); err != nil {
+			if errors.Is(err, metering.ErrQuotaExceeded) {
+				c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+				return
+			}
+			// Недоступність обліку не повинна блокувати роботу тентанта
+			log.Printf("❌ Usage check failed for %s: %v", tenant.Domain, err)
+		}
+
+		metering.Record(tenant.ID, metering.APIRequests, 1)
+		c.Next()
+	}
+}
@@ -0,0 +1,320 @@
+package audit
+
+import (
+	"backend/modules/audit/models"
+	"encoding/json"
+	"fmt"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// TrackedTables — таблиці, зміни яких потрапляють у журнал
+var TrackedTables = map[string]bool{
+	"users":      true,
+	"employees":  true,
+	"calendars":  true,
+	"blogs":      true,
+	"items":      true,
+	"properties": true,
+	"media":      true,
+	"chat_rooms": true,
+}
+
+// Службові колонки: якщо змінились лише вони, запис у журнал не робимо
+var ignoredColumns = map[string]bool{
+	"updated_at":   true,
+	"last_seen_at": true,
+}
+
+// Для масових змін зберігаємо знімки не більше ніж стількох рядків
+const maxSnapshotRows = 500
+
+const (
+	beforeKey = "audit:before"
+	redacted  = "[redacted]"
+)
+
+// Register підключає журнал до з'єднання тентанта. Записи пишуться в тій
+// самій транзакції, що й зміна: без запису в журнал зміна не зберігається.
+func Register(db *gorm.DB) error {
+	callback := db.Callback()
+	if err := callback.Create().After("gorm:create").Register("audit:after_create", afterCreate); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register("audit:before_update", beforeChange); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Register("audit:after_update", afterUpdate); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register("audit:before_delete", beforeChange); err != nil {
+		return err
+	}
+	return callback.Delete().After("gorm:delete").Register("audit:after_delete", afterDelete)
+}
+
+func tracked(db *gorm.DB) bool {
+	return db.Error == nil && !db.DryRun && TrackedTables[db.Statement.Table]
+}
+
+func afterCreate(db *gorm.DB) {
+	if !tracked(db) || db.RowsAffected == 0 {
+		return
+	}
+	var entries []models.AuditLog
+	for _, row := range createdRows(db) {
+		entries = append(entries, newEntry(db, models.ActionCreate, row, nil, row))
+	}
+	write(db, entries)
+}
+
+// beforeChange знімає стан рядків, які зачепить UPDATE чи DELETE
+func beforeChange(db *gorm.DB) {
+	if !tracked(db) || onlyIgnoredColumns(db.Statement.Dest) {
+		return
+	}
+	if rows, ok := snapshot(db); ok {
+		db.InstanceSet(beforeKey, rows)
+	}
+}
+
+func afterUpdate(db *gorm.DB) {
+	before, ok := beforeRows(db)
+	if !ok || !tracked(db) || db.RowsAffected == 0 {
+		return
+	}
+
+	ids := make([]interface{}, 0, len(before))
+	for _, row := range before {
+		ids = append(ids, row["id"])
+	}
+	var after []map[string]interface{}
+	if err := newSession(db).Table(db.Statement.Table).Where("id IN ?", ids).Find(&after).Error; err != nil {
+		db.AddError(fmt.Errorf("audit: %w", err))
+		return
+	}
+	afterByID := make(map[string]map[string]interface{}, len(after))
+	for _, row := range after {
+		afterByID[fmt.Sprint(row["id"])] = row
+	}
+
+	var entries []models.AuditLog
+	for _, old := range before {
+		current, found := afterByID[fmt.Sprint(old["id"])]
+		if !found {
+			continue
+		}
+		if len(changedColumns(old, current)) == 0 {
+			continue
+		}
+		entries = append(entries, newEntry(db, models.ActionUpdate, old, old, current))
+	}
+	write(db, entries)
+}
+
+func afterDelete(db *gorm.DB) {
+	before, ok := beforeRows(db)
+	if !ok || !tracked(db) || db.RowsAffected == 0 {
+		return
+	}
+	var entries []models.AuditLog
+	for _, old := range before {
+		entries = append(entries, newEntry(db, models.ActionDelete, old, old, nil))
+	}
+	write(db, entries)
+}
+
+// onlyIgnoredColumns — оновлення на кшталт Update("last_seen_at", ...) не варто навіть знімати
+func onlyIgnoredColumns(dest interface{}) bool {
+	values, ok := dest.(map[string]interface{})
+	if !ok || len(values) == 0 {
+		return false
+	}
+	for column := range values {
+		if !ignoredColumns[column] {
+			return false
+		}
+	}
+	return true
+}
+
+func beforeRows(db *gorm.DB) ([]map[string]interface{}, bool) {
+	raw, ok := db.InstanceGet(beforeKey)
+	if !ok {
+		return nil, false
+	}
+	rows, ok := raw.([]map[string]interface{})
+	return rows, ok && len(rows) > 0
+}
+
+// newSession — окремий запит у тій самій транзакції, без умов поточного
+func newSession(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true, SkipHooks: true})
+}
+
+// snapshot повторює умови поточного UPDATE/DELETE як SELECT. Первинний ключ
+// моделі (db.Save(&x), db.Delete(&x)) gorm додає пізніше, тому беремо його сами.
+func snapshot(db *gorm.DB) ([]map[string]interface{}, bool) {
+	stmt := db.Statement
+	query := newSession(db).Table(stmt.Table)
+	conditions := false
+	if where, ok := stmt.Clauses["WHERE"]; ok {
+		query = query.Clauses(where.Expression)
+		conditions = true
+	}
+	if id, ok := primaryKey(db); ok {
+		query = query.Where("id = ?", id)
+		conditions = true
+	}
+	if !conditions {
+		return nil, false
+	}
+
+	var rows []map[string]interface{}
+	if err := query.Limit(maxSnapshotRows).Find(&rows).Error; err != nil {
+		db.AddError(fmt.Errorf("audit: %w", err))
+		return nil, false
+	}
+	return rows, true
+}
+
+func primaryKey(db *gorm.DB) (interface{}, bool) {
+	stmt := db.Statement
+	if stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return nil, false
+	}
+	value := reflect.Indirect(stmt.ReflectValue)
+	if value.Kind() != reflect.Struct {
+		return nil, false
+	}
+	id, zero := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, value)
+	if zero {
+		return nil, false
+	}
+	return id, true
+}
+
+// createdRows перетворює створені моделі (або map для Table(...).Create) на рядки
+func createdRows(db *gorm.DB) []map[string]interface{} {
+	stmt := db.Statement
+	if values, ok := stmt.Dest.(map[string]interface{}); ok {
+		return []map[string]interface{}{values}
+	}
+	if stmt.Schema == nil {
+		return nil
+	}
+
+	var rows []map[string]interface{}
+	toRow := func(value reflect.Value) {
+		row := make(map[string]interface{})
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			fieldValue, _ := field.ValueOf(stmt.Context, value)
+			row[field.DBName] = fieldValue
+		}
+		rows = append(rows, row)
+	}
+
+	value := reflect.Indirect(stmt.ReflectValue)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			toRow(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		toRow(value)
+	}
+	return rows
+}
+
+func newEntry(db *gorm.DB, action string, row, before, after map[string]interface{}) models.AuditLog {
+	entry := models.AuditLog{
+		EntityType: db.Statement.Table,
+		EntityID:   fmt.Sprint(row["id"]),
+		Action:     action,
+	}
+	if before != nil {
+		entry.Before = encode(before)
+	}
+	if after != nil {
+		entry.After = encode(after)
+	}
+	if before != nil && after != nil {
+		entry.Changes = datatypes.JSONSlice[string](changedColumns(before, after))
+	}
+	if meta := MetaFrom(db.Statement.Context); meta != nil {
+		entry.TenantID = meta.TenantID
+		entry.ActorID = meta.ActorID
+		entry.ActorEmail = meta.ActorEmail
+		entry.APIKeyID = meta.APIKeyID
+		entry.IP = meta.IP
+		entry.UserAgent = meta.UserAgent
+		entry.Method = meta.Method
+		entry.Path = meta.Path
+		entry.RequestID = meta.RequestID
+	}
+	return entry
+}
+
+func write(db *gorm.DB, entries []models.AuditLog) {
+	if len(entries) == 0 {
+		return
+	}
+	if err := newSession(db).Create(&entries).Error; err != nil {
+		log.Println("❌ Cannot write audit log:", err)
+		db.AddError(fmt.Errorf("audit: %w", err))
+	}
+}
+
+// changedColumns — колонки, значення яких відрізняються; службові не враховуються
+func changedColumns(before, after map[string]interface{}) []string {
+	var changed []string
+	for column, value := range after {
+		if ignoredColumns[column] {
+			continue
+		}
+		if string(encodeValue(column, value)) != string(encodeValue(column, before[column])) {
+			changed = append(changed, column)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func sensitive(column string) bool {
+	return strings.Contains(column, "password") || strings.Contains(column, "secret") ||
+		strings.Contains(column, "token") || strings.HasSuffix(column, "_hash")
+}
+
+func encodeValue(column string, value interface{}) json.RawMessage {
+	if sensitive(column) && value != nil && value != "" {
+		value = redacted
+	}
+	// jsonb зі сканування приходить байтами — зберігаємо як JSON, а не base64
+	if raw, ok := value.([]byte); ok {
+		if json.Valid(raw) {
+			return raw
+		}
+		value = string(raw)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return json.RawMessage(`null`)
+	}
+	return data
+}
+
+func encode(row map[string]interface{}) datatypes.JSON {
+	encoded := make(map[string]json.RawMessage, len(row))
+	for column, value := range row {
+		encoded[column] = encodeValue(column, value)
+	}
+	data, _ := json.Marshal(encoded)
+	return datatypes.JSON(data)
+}
@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Meta — хто і яким запитом виконує зміни. TenantMiddleware кладе її в
+// контекст підключення до БД, AuthMiddleware доповнює автором.
+type Meta struct {
+	TenantID   uuid.UUID
+	ActorID    *uuid.UUID
+	ActorEmail string
+	APIKeyID   *uuid.UUID
+	IP         string
+	UserAgent  string
+	Method     string
+	Path       string
+	RequestID  string
+}
+
+type metaKey struct{}
+
+// ginMetaKey — під цим ключем Meta лежить у gin.Context
+const ginMetaKey = "auditMeta"
+
+func WithMeta(ctx context.Context, meta *Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, meta)
+}
+
+func MetaFrom(ctx context.Context) *Meta {
+	if ctx == nil {
+		return nil
+	}
+	meta, _ := ctx.Value(metaKey{}).(*Meta)
+	return meta
+}
+
+// NewRequestMeta збирає дані запиту і зберігає Meta в gin.Context
+func NewRequestMeta(c *gin.Context, tenantID uuid.UUID) *Meta {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	meta := &Meta{
+		TenantID:  tenantID,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Method:    c.Request.Method,
+		Path:      c.FullPath(),
+		RequestID: requestID,
+	}
+	c.Set(ginMetaKey, meta)
+	return meta
+}
+
+// SetActor записує автора змін для поточного запиту
+func SetActor(c *gin.Context, actorID uuid.UUID, email string, apiKeyID *uuid.UUID) {
+	raw, ok := c.Get(ginMetaKey)
+	if !ok {
+		return
+	}
+	if meta, valid := raw.(*Meta); valid {
+		meta.ActorID = &actorID
+		meta.ActorEmail = email
+		meta.APIKeyID = apiKeyID
+	}
+}
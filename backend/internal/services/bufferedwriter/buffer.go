@@ -10,7 +10,7 @@ import (
 type Writer[T any] struct {
 	buffer     []T
 	mutex      sync.Mutex
-	db         *gorm.DB
+	db         func() (*gorm.DB, error)
 	flushEvery time.Duration
 	maxSize    int
 	flusher    func(tx *gorm.DB, items []T) error
@@ -18,7 +18,9 @@ type Writer[T any] struct {
 	onError    func(err error)
 }
 
-func NewWriter[T any](db *gorm.DB,
+// NewWriter створює буфер із періодичним скиданням. db викликається на кожне
+// скидання: пул тентанта може бути витіснений і відкритий заново, поки буфер живе.
+func NewWriter[T any](db func() (*gorm.DB, error),
 	flushEvery time.Duration,
 	maxSize int,
 	flusher func(tx *gorm.DB, items []T) error,
@@ -53,19 +55,31 @@ func (w *Writer[T]) Flush() {
 		w.mutex.Unlock()
 		return
 	}
+	db, err := w.db()
+	if err != nil {
+		// Записи лишаються в буфері до наступного скидання
+		w.mutex.Unlock()
+		log.Printf("Error resolving buffer DB: %v", err)
+		if w.onError != nil {
+			w.onError(err)
+		}
+		return
+	}
 	items := make([]T, len(w.buffer))
 	copy(items, w.buffer)
 	w.buffer = nil
 	w.mutex.Unlock()
 
-	err := w.flusher(w.db, items)
+	err = w.flusher(db, items)
 	if err != nil {
 		log.Printf("Error flushing buffer: %v", err)
 		if w.onError != nil {
 			w.onError(err)
-		} else if w.onSuccess != nil {
-			w.onSuccess(len(items))
 		}
+		return
+	}
+	if w.onSuccess != nil {
+		w.onSuccess(len(items))
 	}
 }
 
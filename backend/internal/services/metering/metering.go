@@ -0,0 +1,145 @@
+package metering
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	Users        = "users"         // кількість користувачів (рахується з БД тентанта)
+	MediaBytes   = "media_bytes"   // байти, завантажені через media/service.UploadFile
+	ChatMessages = "chat_messages" // повідомлення, збережені буферизованим записувачем
+	APIRequests  = "api_requests"  // запити до API тентанта
+)
+
+// Metric описує, як рахувати метрику відносно квоти
+type Metric struct {
+	Name string `json:"name"`
+	// Monthly — квота діє в межах календарного місяця, інакше за весь час
+	Monthly bool `json:"monthly"`
+	// Gauge — значення не накопичується, а рахується наживо з БД тентанта
+	Gauge bool `json:"gauge"`
+}
+
+var Metrics = []Metric{
+	{Name: Users, Gauge: true},
+	{Name: MediaBytes},
+	{Name: ChatMessages, Monthly: true},
+	{Name: APIRequests, Monthly: true},
+}
+
+func lookupMetric(name string) (Metric, bool) {
+	for _, metric := range Metrics {
+		if metric.Name == name {
+			return metric, true
+		}
+	}
+	return Metric{}, false
+}
+
+const flushInterval = 30 * time.Second
+
+type counterKey struct {
+	TenantID uuid.UUID
+	Metric   string
+	Period   time.Time
+}
+
+var (
+	mu      sync.Mutex
+	pending = make(map[counterKey]int64)
+)
+
+// PeriodOf повертає початок місяця (UTC), до якого відноситься момент t
+func PeriodOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Record додає delta до лічильника метрики тентанта. Значення накопичуються
+// в пам'яті й періодично скидаються в адмінську БД.
+func Record(tenantID uuid.UUID, metric string, delta int64) {
+	if tenantID == uuid.Nil || delta == 0 {
+		return
+	}
+	key := counterKey{TenantID: tenantID, Metric: metric, Period: PeriodOf(time.Now())}
+
+	mu.Lock()
+	pending[key] += delta
+	mu.Unlock()
+}
+
+func pendingValue(tenantID uuid.UUID, metric string, since time.Time) int64 {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var total int64
+	for key, value := range pending {
+		if key.TenantID == tenantID && key.Metric == metric && !key.Period.Before(since) {
+			total += value
+		}
+	}
+	return total
+}
+
+// Start запускає фонове скидання лічильників
+func Start() {
+	go func() {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := Flush(); err != nil {
+				log.Printf("❌ Usage flush failed: %v", err)
+			}
+		}
+	}()
+}
+
+// Flush записує накопичені лічильники; при помилці вони повертаються в буфер
+func Flush() error {
+	mu.Lock()
+	batch := pending
+	pending = make(map[counterKey]int64)
+	mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	rows := make([]entities.TenantUsage, 0, len(batch))
+	now := time.Now()
+	for key, value := range batch {
+		rows = append(rows, entities.TenantUsage{
+			TenantID:  key.TenantID,
+			Metric:    key.Metric,
+			Period:    key.Period,
+			Value:     value,
+			UpdatedAt: now,
+		})
+	}
+
+	err := postgres.GetDB().Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}, {Name: "metric"}, {Name: "period"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"value":      gorm.Expr("tenant_usages.value + excluded.value"),
+			"updated_at": now,
+		}),
+	}).Create(&rows).Error
+	if err != nil {
+		mu.Lock()
+		for key, value := range batch {
+			pending[key] += value
+		}
+		mu.Unlock()
+		return err
+	}
+
+	invalidateUsage()
+	return nil
+}
@@ -0,0 +1,213 @@
+package metering
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sync"
+	"time"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// QuotaError пояснює, яку саме квоту перевищено
+type QuotaError struct {
+	Metric string
+	Limit  int64
+	Used   int64
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("quota exceeded: %s limit of %d reached (used %d)", e.Metric, e.Limit, e.Used)
+}
+
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+const quotaCacheTTL = 60 * time.Second
+
+type tenantState struct {
+	limits   map[string]int64
+	stored   map[string]int64
+	loadedAt time.Time
+}
+
+var (
+	stateMu sync.RWMutex
+	states  = make(map[uuid.UUID]*tenantState)
+)
+
+func invalidateUsage() {
+	stateMu.Lock()
+	states = make(map[uuid.UUID]*tenantState)
+	stateMu.Unlock()
+}
+
+func invalidateTenant(tenantID uuid.UUID) {
+	stateMu.Lock()
+	delete(states, tenantID)
+	stateMu.Unlock()
+}
+
+// state повертає ліміти та вже записане використання тентанта з кешу
+func state(tenantID uuid.UUID) (*tenantState, error) {
+	stateMu.RLock()
+	cached, ok := states[tenantID]
+	stateMu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < quotaCacheTTL {
+		return cached, nil
+	}
+
+	db := postgres.GetDB()
+	var quotas []entities.TenantQuota
+	if err := db.Where("tenant_id = ?", tenantID).Find(&quotas).Error; err != nil {
+		return nil, err
+	}
+
+	loaded := &tenantState{limits: make(map[string]int64), stored: make(map[string]int64), loadedAt: time.Now()}
+	for _, quota := range quotas {
+		loaded.limits[quota.Metric] = quota.MaxValue
+	}
+
+	period := PeriodOf(time.Now())
+	for _, metric := range Metrics {
+		if metric.Gauge {
+			continue
+		}
+		query := db.Model(&entities.TenantUsage{}).
+			Select("COALESCE(SUM(value), 0)").
+			Where("tenant_id = ? AND metric = ?", tenantID, metric.Name)
+		if metric.Monthly {
+			query = query.Where("period = ?", period)
+		}
+		var value int64
+		if err := query.Scan(&value).Error; err != nil {
+			return nil, err
+		}
+		loaded.stored[metric.Name] = value
+	}
+
+	stateMu.Lock()
+	states[tenantID] = loaded
+	stateMu.Unlock()
+	return loaded, nil
+}
+
+// Used повертає використання накопичувальної метрики у вікні її квоти
+func Used(tenantID uuid.UUID, metric string) (int64, error) {
+	def, ok := lookupMetric(metric)
+	if !ok || def.Gauge {
+		return 0, fmt.Errorf("unknown metric %s", metric)
+	}
+	current, err := state(tenantID)
+	if err != nil {
+		return 0, err
+	}
+
+	since := time.Time{}
+	if def.Monthly {
+		since = PeriodOf(time.Now())
+	}
+	return current.stored[metric] + pendingValue(tenantID, metric, since), nil
+}
+
+func Limit(tenantID uuid.UUID, metric string) (int64, bool, error) {
+	current, err := state(tenantID)
+	if err != nil {
+		return 0, false, err
+	}
+	limit, ok := current.limits[metric]
+	return limit, ok, nil
+}
+
+// Check перевіряє, чи вміститься ще delta одиниць метрики в квоту тентанта
+func Check(tenantID uuid.UUID, metric string, delta int64) error {
+	limit, limited, err := Limit(tenantID, metric)
+	if err != nil || !limited {
+		return err
+	}
+	used, err := Used(tenantID, metric)
+	if err != nil {
+		return err
+	}
+	if used+delta > limit {
+		return &QuotaError{Metric: metric, Limit: limit, Used: used}
+	}
+	return nil
+}
+
+// CountUsers рахує користувачів у БД тентанта; сервісні акаунти місць не займають
+func CountUsers(db *gorm.DB) (int64, error) {
+	var count int64
+	err := db.Table("users").Where("is_service_account = ?", false).Count(&count).Error
+	return count, err
+}
+
+// CheckSeats перевіряє, чи можна додати ще count користувачів
+func CheckSeats(tenantID uuid.UUID, db *gorm.DB, count int64) error {
+	limit, limited, err := Limit(tenantID, Users)
+	if err != nil || !limited {
+		return err
+	}
+	used, err := CountUsers(db)
+	if err != nil {
+		return err
+	}
+	if used+count > limit {
+		return &QuotaError{Metric: Users, Limit: limit, Used: used}
+	}
+	return nil
+}
+
+// ReserveSeats перевіряє місця всередині транзакції tx, що створює користувачів.
+// pg_advisory_xact_lock за тентантом тримається до кінця транзакції, тож паралельні
+// створення виконуються по черзі й кожне бачить користувачів, закомічених попереднім.
+func ReserveSeats(tx *gorm.DB, tenantID uuid.UUID, count int64) error {
+	if _, limited, err := Limit(tenantID, Users); err != nil || !limited {
+		return err
+	}
+	if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext(?))`, "seats:"+tenantID.String()).Error; err != nil {
+		return err
+	}
+	return CheckSeats(tenantID, tx, count)
+}
+
+// SetQuotas задає ліміти; від'ємне значення знімає обмеження для метрики
+func SetQuotas(tenantID uuid.UUID, limits map[string]int64) error {
+	for metric := range limits {
+		if _, ok := lookupMetric(metric); !ok {
+			return fmt.Errorf("unknown metric %s", metric)
+		}
+	}
+
+	err := postgres.GetDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for metric, limit := range limits {
+			if limit < 0 {
+				if err := tx.Where("tenant_id = ? AND metric = ?", tenantID, metric).Delete(&entities.TenantQuota{}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			quota := entities.TenantQuota{TenantID: tenantID, Metric: metric, MaxValue: limit, UpdatedAt: now}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "metric"}},
+				DoUpdates: clause.AssignmentColumns([]string{"max_value", "updated_at"}),
+			}).Create(&quota).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	invalidateTenant(tenantID)
+	return nil
+}
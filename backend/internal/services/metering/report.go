@@ -0,0 +1,69 @@
+package metering
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type MetricUsage struct {
+	Metric
+	Used  int64  `json:"used"`
+	Limit *int64 `json:"limit"`
+}
+
+type UsageReport struct {
+	TenantID uuid.UUID              `json:"tenant_id"`
+	Period   time.Time              `json:"period"`
+	Metrics  []MetricUsage          `json:"metrics"`
+	History  []entities.TenantUsage `json:"history"`
+}
+
+// Report збирає поточне використання та історію за останні months місяців.
+// db — підключення до БД тентанта для підрахунку користувачів (може бути nil).
+func Report(tenantID uuid.UUID, db *gorm.DB, months int) (*UsageReport, error) {
+	report := &UsageReport{TenantID: tenantID, Period: PeriodOf(time.Now())}
+
+	for _, metric := range Metrics {
+		usage := MetricUsage{Metric: metric}
+		if metric.Gauge {
+			if db != nil {
+				count, err := CountUsers(db)
+				if err != nil {
+					return nil, err
+				}
+				usage.Used = count
+			}
+		} else {
+			used, err := Used(tenantID, metric.Name)
+			if err != nil {
+				return nil, err
+			}
+			usage.Used = used
+		}
+
+		limit, limited, err := Limit(tenantID, metric.Name)
+		if err != nil {
+			return nil, err
+		}
+		if limited {
+			usage.Limit = &limit
+		}
+		report.Metrics = append(report.Metrics, usage)
+	}
+
+	if months <= 0 {
+		months = 12
+	}
+	since := report.Period.AddDate(0, -(months - 1), 0)
+	err := postgres.GetDB().
+		Where("tenant_id = ? AND period >= ?", tenantID, since).
+		Order("period DESC, metric ASC").
+		Find(&report.History).Error
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,97 @@
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Issuer задає адміністратор тентанта, а endpoints приходять з його discovery,
+// тому запити до IdP не повинні діставати внутрішню мережу платформи (SSRF):
+// лише https і лише публічні адреси, перевірені вже після DNS.
+
+var ErrForbiddenIssuer = errors.New("oidc issuer must be a public https url")
+
+// sharedAddressSpace — CGNAT (RFC 6598), netip не вважає її приватною
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// allowPrivateNetwork вмикається для локальної розробки і тестів з IdP на localhost
+func allowPrivateNetwork() bool {
+	return os.Getenv("OIDC_ALLOW_PRIVATE_NETWORK") == "true"
+}
+
+// ValidateURL перевіряє адресу IdP до запиту: https і не приватний хост
+func ValidateURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || parsed.User != nil {
+		return ErrForbiddenIssuer
+	}
+	if allowPrivateNetwork() {
+		if parsed.Scheme != "https" && parsed.Scheme != "http" {
+			return ErrForbiddenIssuer
+		}
+		return nil
+	}
+	if parsed.Scheme != "https" {
+		return ErrForbiddenIssuer
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenIssuer
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !isPublicAddr(ip) {
+		return ErrForbiddenIssuer
+	}
+	return nil
+}
+
+func isPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	return !sharedAddressSpace.Contains(ip)
+}
+
+// dialControl відкидає з'єднання з непублічними адресами: DNS імені IdP
+// (або перенаправлення) не може вивести запит у внутрішню мережу
+func dialControl(_, address string, _ syscall.RawConn) error {
+	if allowPrivateNetwork() {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || !isPublicAddr(ip) {
+		return fmt.Errorf("%w: %s is not a public address", ErrForbiddenIssuer, host)
+	}
+	return nil
+}
+
+var httpClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		// Без проксі з оточення: інакше перевірка адреси стосувалася б проксі
+		Proxy:               nil,
+		DialContext:         (&net.Dialer{Timeout: 5 * time.Second, Control: dialControl}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 5 {
+			return errors.New("oidc: too many redirects")
+		}
+		return ValidateURL(req.URL.String())
+	},
+}
@@ -0,0 +1,80 @@
+// Package spreadsheet читає табличні файли (CSV і XLSX) у вигляді рядків клітинок.
+// XLSX розбирається вручну (zip + XML): потрібен лише перший аркуш без формул і стилів.
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Обмеження розміру таблиці: рядки разом із заголовком і колонки
+const (
+	MaxRows    = 10000
+	MaxColumns = 256
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported file format, use .csv or .xlsx")
+	ErrTooManyRows       = errors.New("too many rows in file")
+	ErrTooManyColumns    = errors.New("too many columns in file")
+)
+
+// Read повертає рядки таблиці (перший — заголовок); формат визначається за розширенням
+func Read(filename string, r io.ReaderAt, size int64) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return ReadCSV(io.NewSectionReader(r, 0, size))
+	case ".xlsx":
+		return ReadXLSX(r, size)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ReadCSV приймає кому або крапку з комою (так зберігає Excel з європейською локаллю)
+func ReadCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) >= MaxRows {
+			return nil, ErrTooManyRows
+		}
+		if len(record) > MaxColumns {
+			return nil, ErrTooManyColumns
+		}
+		rows = append(rows, record)
+	}
+}
+
+// EscapeFormula захищає від CSV-ін'єкції: клітинку, що починається з = + - @
+// (або табуляції чи повернення каретки), Excel і LibreOffice виконали б як формулу
+func EscapeFormula(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
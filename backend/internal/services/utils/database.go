@@ -21,3 +21,24 @@ func GetDBFromContext(ctx *gin.Context) (*gorm.DB, bool) {
 
 	return db, true
 }
+
+// GetReadDBFromContext повертає підключення лише для читання (репліку), а якщо
+// тентант не має придатної репліки — основне підключення з контексту.
+// Використовувати тільки там, де допустимі дані з невеликим відставанням.
+// Репліка вибирається при першому виклику і запам'ятовується до кінця запиту.
+func GetReadDBFromContext(ctx *gin.Context) (*gorm.DB, bool) {
+	if dbRaw, exists := ctx.Get("DBRead"); exists {
+		if db, ok := dbRaw.(*gorm.DB); ok {
+			return db, true
+		}
+	}
+	if resolverRaw, exists := ctx.Get("DBReadResolver"); exists {
+		if resolve, ok := resolverRaw.(func() (*gorm.DB, error)); ok {
+			if db, err := resolve(); err == nil {
+				ctx.Set("DBRead", db)
+				return db, true
+			}
+		}
+	}
+	return GetDBFromContext(ctx)
+}
@@ -6,23 +6,75 @@ import (
 	"fmt"
 	"github.com/joho/godotenv"
 	"gopkg.in/gomail.v2"
+	"html"
 	"log"
 	"os"
+	"time"
 )
 
 func SendEmail(to string, subject string, body string, isHTML bool) error {
+	emailConfig, err := defaultEmailConfig()
+	if err != nil {
+		return err
+	}
+	return sendEmail(emailConfig, to, subject, body, isHTML)
+}
+
+// SendTenantEmail надсилає лист через SMTP тентанта, а якщо він не налаштований — через .env
+func SendTenantEmail(settings *entities.TenantSettings, to string, subject string, body string, isHTML bool) error {
+	emailConfig, err := TenantEmailConfig(settings)
+	if err != nil {
+		return err
+	}
+	return sendEmail(emailConfig, to, subject, body, isHTML)
+}
+
+func defaultEmailConfig() (entities.EmailConfig, error) {
 	err := godotenv.Load(".env")
 	if err != nil {
-		return fmt.Errorf("error loading .env file email: %v", err)
+		return entities.EmailConfig{}, fmt.Errorf("error loading .env file email: %v", err)
 	}
-	var emailConfig = entities.EmailConfig{
+	return entities.EmailConfig{
 		SMTPHost: os.Getenv("SMTP_HOST"),
 		SMTPPort: 465,
 		Username: os.Getenv("SMTP_USER"),
 		Password: os.Getenv("SMTP_PASSWORD"),
 		From:     os.Getenv("EMAILS_FROM_EMAIL"),
+	}, nil
+}
+
+func TenantEmailConfig(settings *entities.TenantSettings) (entities.EmailConfig, error) {
+	if settings == nil || settings.SMTPHost == "" {
+		return defaultEmailConfig()
 	}
 
+	password := ""
+	if settings.SMTPPassword != "" {
+		decrypted, err := Decrypt(settings.SMTPPassword)
+		if err != nil {
+			return entities.EmailConfig{}, fmt.Errorf("failed to decrypt SMTP password: %w", err)
+		}
+		password = decrypted
+	}
+
+	port := settings.SMTPPort
+	if port == 0 {
+		port = 465
+	}
+	from := settings.SMTPFrom
+	if settings.SMTPFromName != "" {
+		from = fmt.Sprintf("%s <%s>", settings.SMTPFromName, settings.SMTPFrom)
+	}
+	return entities.EmailConfig{
+		SMTPHost: settings.SMTPHost,
+		SMTPPort: port,
+		Username: settings.SMTPUser,
+		Password: password,
+		From:     from,
+	}, nil
+}
+
+func sendEmail(emailConfig entities.EmailConfig, to string, subject string, body string, isHTML bool) error {
 	mailer := gomail.NewMessage()
 	mailer.SetHeader("From", emailConfig.From)
 	mailer.SetHeader("To", to)
@@ -46,16 +98,58 @@ func SendEmail(to string, subject string, body string, isHTML bool) error {
 	return nil
 }
 
-func SendPasswordResetEmail(to string, resetToken string) error {
-	resetLink := fmt.Sprintf("http://localhost:5173/reset-password?token=%s", resetToken)
-
+// SendPasswordResetEmail надсилає посилання на скидання пароля через SMTP тентанта
+func SendPasswordResetEmail(settings *entities.TenantSettings, to string, resetLink string) error {
 	htmlBody := fmt.Sprintf(`
 		<h2>Password Reset Request</h2>
 		<p>We received a request to reset your password. Click the link below to set a new password:</p>
 		<a href="%s">Reset Password</a>
+		<p>The link can be used once and expires in one hour.</p>
 		<p>If you didn't request a password reset, you can ignore this email.</p>
-	`, resetLink)
+	`, html.EscapeString(resetLink))
 
 	subject := "Password Reset Request"
-	return SendEmail(to, subject, htmlBody, true)
+	return SendTenantEmail(settings, to, subject, htmlBody, true)
+}
+
+// SendInvitationEmail — запрошення з брендингом тентанта (назва, логотип, колір)
+func SendInvitationEmail(settings *entities.TenantSettings, to string, inviterName string, acceptLink string, expiresAt time.Time) error {
+	brand := settings.BrandName
+	if brand == "" {
+		brand = "Admin Panel"
+	}
+	color := settings.PrimaryColor
+	if color == "" {
+		color = "#2563eb"
+	}
+	logo := ""
+	if settings.LogoURL != "" {
+		logo = fmt.Sprintf(`<img src="%s" alt="%s" style="max-height:48px"><br>`,
+			html.EscapeString(settings.LogoURL), html.EscapeString(brand))
+	}
+
+	htmlBody := fmt.Sprintf(`
+		%s
+		<h2>You are invited to %s</h2>
+		<p>%s invited you to join %s. Click the button below to set your password and activate your account:</p>
+		<a href="%s" style="display:inline-block;padding:10px 18px;background:%s;color:#fff;text-decoration:none;border-radius:4px">Accept invitation</a>
+		<p>The invitation expires on %s.</p>
+	`, logo, html.EscapeString(brand), html.EscapeString(inviterName), html.EscapeString(brand),
+		html.EscapeString(acceptLink), html.EscapeString(color), expiresAt.In(settings.Location()).Format("02.01.2006 15:04 MST"))
+
+	subject := fmt.Sprintf("Invitation to %s", brand)
+	return SendTenantEmail(settings, to, subject, htmlBody, true)
+}
+
+// SendLockoutEmail повідомляє власника облікового запису про блокування входу
+func SendLockoutEmail(settings *entities.TenantSettings, to string, ip string, until time.Time) error {
+	htmlBody := fmt.Sprintf(`
+		<h2>Sign-in temporarily blocked</h2>
+		<p>There were too many failed sign-in attempts to your account (last one from %s).</p>
+		<p>Signing in is blocked until %s.</p>
+		<p>If it wasn't you, consider changing your password and contact your administrator.</p>
+	`, html.EscapeString(ip), until.In(settings.Location()).Format("02.01.2006 15:04 MST"))
+
+	subject := "Sign-in temporarily blocked"
+	return SendTenantEmail(settings, to, subject, htmlBody, true)
 }
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	roleModels "backend/modules/role/models"
+	roleRepository "backend/modules/role/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+)
+
+const (
+	permissionsKey = "permissions"
+	// scopesKey задає AuthMiddleware для запитів з API-ключем
+	scopesKey = "scopes"
+)
+
+// GetPermissionsFromContext повертає дозволи поточного користувача;
+// в межах запиту вони завантажуються з БД лише раз. Для API-ключа
+// дозволи ролей додатково обмежуються його scopes.
+func GetPermissionsFromContext(ctx *gin.Context, db *gorm.DB) ([]string, error) {
+	if cached, ok := ctx.Get(permissionsKey); ok {
+		if permissions, valid := cached.([]string); valid {
+			return permissions, nil
+		}
+	}
+
+	userIDRaw, exists := ctx.Get("id")
+	if !exists {
+		return nil, nil
+	}
+	userID, ok := userIDRaw.(uuid.UUID)
+	if !ok {
+		return nil, nil
+	}
+
+	permissions, err := roleRepository.GetUserPermissions(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if raw, ok := ctx.Get(scopesKey); ok {
+		if scopes, valid := raw.([]string); valid {
+			permissions = roleModels.RestrictPermissions(permissions, scopes)
+		}
+	}
+	ctx.Set(permissionsKey, permissions)
+	return permissions, nil
+}
+
+// HasPermission — чи має поточний користувач дозвіл. Помилка БД
+// трактується як відсутність дозволу.
+func HasPermission(ctx *gin.Context, db *gorm.DB, perm string) bool {
+	permissions, err := GetPermissionsFromContext(ctx, db)
+	if err != nil {
+		log.Println("❌ Cannot load permissions:", err)
+		return false
+	}
+	return roleModels.HasPermission(permissions, perm)
+}
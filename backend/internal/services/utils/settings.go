@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"backend/internal/entities"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetTenantSettingsFromContext повертає налаштування тентанта, які поклав TenantMiddleware
+func GetTenantSettingsFromContext(ctx *gin.Context) entities.TenantSettings {
+	if raw, exists := ctx.Get("settings"); exists {
+		if settings, ok := raw.(entities.TenantSettings); ok {
+			return settings
+		}
+	}
+	return entities.DefaultTenantSettings(uuid.Nil)
+}
+
+// GetTenantFromContext повертає тентанта, визначеного TenantMiddleware.
+// Ключ "tenant" після AuthMiddleware містить домен з токена, тому запис тентанта
+// зберігається окремо.
+func GetTenantFromContext(ctx *gin.Context) (*entities.Tenant, bool) {
+	raw, exists := ctx.Get("tenantRecord")
+	if !exists {
+		return nil, false
+	}
+	tenant, ok := raw.(entities.Tenant)
+	if !ok {
+		return nil, false
+	}
+	return &tenant, true
+}
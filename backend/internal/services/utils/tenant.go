@@ -6,29 +6,120 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"os"
+	"regexp"
+	"strings"
 )
 
-func getKey() ([]byte, error) {
-	key := os.Getenv("TENANT_ENCRYPTION_KEY")
-	if len(key) != 32 {
-		return nil, errors.New("TENANT_ENCRYPTION_KEY must be 32 bytes long")
+// Формат шифротексту: "v1:<keyID>:<base64(nonce|ciphertext)>".
+// Старі значення без префікса розшифровуються ключем LegacyKeyID.
+const (
+	ciphertextVersion = "v1"
+	LegacyKeyID       = "legacy"
+)
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// loadKeyring читає всі ключі шифрування з оточення:
+// TENANT_ENCRYPTION_KEYS="id1:key1,id2:key2" і TENANT_ENCRYPTION_KEY як ключ "legacy"
+func loadKeyring() (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+
+	if legacy := os.Getenv("TENANT_ENCRYPTION_KEY"); legacy != "" {
+		if len(legacy) != 32 {
+			return nil, errors.New("TENANT_ENCRYPTION_KEY must be 32 bytes long")
+		}
+		keys[LegacyKeyID] = []byte(legacy)
+	}
+
+	for _, pair := range strings.Split(os.Getenv("TENANT_ENCRYPTION_KEYS"), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, key, found := strings.Cut(pair, ":")
+		if !found || !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("invalid entry in TENANT_ENCRYPTION_KEYS: %q", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %s must be 32 bytes long", id)
+		}
+		keys[id] = []byte(key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no tenant encryption keys configured")
+	}
+	return keys, nil
+}
+
+// ActiveKeyID повертає ID ключа, яким шифруються нові значення
+func ActiveKeyID() (string, error) {
+	keys, err := loadKeyring()
+	if err != nil {
+		return "", err
+	}
+
+	id := os.Getenv("TENANT_ENCRYPTION_ACTIVE_KEY")
+	if id == "" {
+		id = LegacyKeyID
 	}
-	return []byte(key), nil
+	if _, ok := keys[id]; !ok {
+		return "", fmt.Errorf("active encryption key %s is not configured", id)
+	}
+	return id, nil
+}
+
+// ConfiguredKeyIDs повертає ID всіх ключів, доступних для розшифрування
+func ConfiguredKeyIDs() ([]string, error) {
+	keys, err := loadKeyring()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(keys))
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// CiphertextKeyID повертає ID ключа, яким зашифровано значення
+func CiphertextKeyID(encoded string) string {
+	if id, _, ok := splitCiphertext(encoded); ok {
+		return id
+	}
+	return LegacyKeyID
+}
+
+func splitCiphertext(encoded string) (string, string, bool) {
+	parts := strings.SplitN(encoded, ":", 3)
+	if len(parts) != 3 || parts[0] != ciphertextVersion || !keyIDPattern.MatchString(parts[1]) {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
 }
 
 func Encrypt(plaintext string) (string, error) {
-	key, err := getKey()
+	id, err := ActiveKeyID()
 	if err != nil {
 		return "", err
 	}
+	return EncryptWithKey(id, plaintext)
+}
 
-	block, err := aes.NewCipher(key)
+// EncryptWithKey шифрує значення конкретним ключем з keyring
+func EncryptWithKey(keyID, plaintext string) (string, error) {
+	keys, err := loadKeyring()
 	if err != nil {
 		return "", err
 	}
+	key, ok := keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("encryption key %s is not configured", keyID)
+	}
 
-	aesGCM, err := cipher.NewGCM(block)
+	aesGCM, err := newGCM(key)
 	if err != nil {
 		return "", err
 	}
@@ -40,26 +131,30 @@ func Encrypt(plaintext string) (string, error) {
 
 	ciphertext := aesGCM.Seal(nil, nonce, []byte(plaintext), nil)
 	final := append(nonce, ciphertext...)
-	return base64.StdEncoding.EncodeToString(final), nil
+	return ciphertextVersion + ":" + keyID + ":" + base64.StdEncoding.EncodeToString(final), nil
 }
 
 func Decrypt(encoded string) (string, error) {
-	key, err := getKey()
+	keys, err := loadKeyring()
 	if err != nil {
 		return "", err
 	}
 
-	data, err := base64.StdEncoding.DecodeString(encoded)
-	if err != nil {
-		return "", err
+	keyID, payload, ok := splitCiphertext(encoded)
+	if !ok {
+		keyID, payload = LegacyKeyID, encoded
+	}
+	key, found := keys[keyID]
+	if !found {
+		return "", fmt.Errorf("encryption key %s is not configured", keyID)
 	}
 
-	block, err := aes.NewCipher(key)
+	data, err := base64.StdEncoding.DecodeString(payload)
 	if err != nil {
 		return "", err
 	}
 
-	aesGCM, err := cipher.NewGCM(block)
+	aesGCM, err := newGCM(key)
 	if err != nil {
 		return "", err
 	}
@@ -78,3 +173,11 @@ func Decrypt(encoded string) (string, error) {
 
 	return string(plaintext), nil
 }
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -13,19 +13,24 @@ type DecryptedTenantCreds struct {
 }
 
 func DecryptTenantCreds(t *entities.Tenant) (*DecryptedTenantCreds, error) {
-	user, err := Decrypt(t.DBUser)
+	return DecryptCreds(t.DBUser, t.DBName, t.DBHost, t.DBPassword)
+}
+
+// DecryptCreds розшифровує набір облікових даних підключення (тентанта чи репліки)
+func DecryptCreds(encUser, encName, encHost, encPassword string) (*DecryptedTenantCreds, error) {
+	user, err := Decrypt(encUser)
 	if err != nil {
 		return nil, fmt.Errorf("decrypt user: %w", err)
 	}
-	name, err := Decrypt(t.DBName)
+	name, err := Decrypt(encName)
 	if err != nil {
 		return nil, fmt.Errorf("decrypt db name: %w", err)
 	}
-	host, err := Decrypt(t.DBHost)
+	host, err := Decrypt(encHost)
 	if err != nil {
 		return nil, fmt.Errorf("decrypt db host: %w", err)
 	}
-	pass, err := Decrypt(t.DBPassword)
+	pass, err := Decrypt(encPassword)
 	if err != nil {
 		return nil, fmt.Errorf("decrypt password: %w", err)
 	}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"os"
+	"strings"
+)
+
+// TenantFrontendURL — адреса фронтенду тентанта для посилань у листах.
+// Власний домен має пріоритет, інакше субдомен на APP_URL (https) або
+// APP_HOST (локальна розробка).
+func TenantFrontendURL(domain, customHost string) string {
+	if customHost != "" {
+		return "https://" + customHost
+	}
+	if appURL := strings.TrimSuffix(os.Getenv("APP_URL"), "/"); appURL != "" {
+		return "https://" + domain + "." + appURL
+	}
+	if appHost := os.Getenv("APP_HOST"); appHost != "" {
+		return "http://" + domain + "." + appHost
+	}
+	return "http://localhost:5173"
+}
@@ -16,17 +16,25 @@ type Claims struct {
 	FullName string    `json:"fullName"`
 	Tenant   string    `json:"tenant"`
 	TenantID uuid.UUID `json:"tenant_id"`
+	// SessionID — сесія, до якої прив'язаний токен; відкликання сесії
+	// робить токен недійсним ще до закінчення терміну
+	SessionID uuid.UUID `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWTToken(email, fullName string, id uuid.UUID, tenant string, tenantID uuid.UUID) (string, error) {
+// Access-токен живе недовго, довгу сесію тримає refresh-токен
+const AccessTokenTTL = 15 * time.Minute
+
+func GenerateJWTToken(email, fullName string, id uuid.UUID, tenant string, tenantID uuid.UUID, sessionID uuid.UUID) (string, error) {
 	claims := jwt.MapClaims{
 		"id":        id.String(),
 		"email":     email,
 		"fullName":  fullName,
 		"tenant":    tenant,
 		"tenant_id": tenantID.String(),
-		"exp":       time.Now().Add(time.Hour * 24 * 7).Unix(),
+		"sid":       sessionID.String(),
+		"iat":       time.Now().Unix(),
+		"exp":       time.Now().Add(AccessTokenTTL).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(jwtSecret)
@@ -42,53 +50,112 @@ func ParseJWTToken(tokenString string) (*Claims, error) {
 	if err != nil {
 		return nil, err
 	}
-	// Перевірка валідності токена
+	// Перевірка валідності токена. Проміжні токени (з audience) і токени
+	// без сесії доступу не дають
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		if len(claims.Audience) > 0 || claims.SessionID == uuid.Nil {
+			return nil, errors.New("invalid token")
+		}
 		return claims, nil
 	}
 	return nil, errors.New("invalid token")
 }
 
-type ResetClaims struct {
-	Email string `json:"email"`
+// Проміжний токен двоетапного входу: пароль перевірено, але доступу
+// до API він не дає — лише право ввести другий фактор
+const (
+	mfaAudience = "mfa"
+	MFATokenTTL = 5 * time.Minute
+)
+
+type MFAClaims struct {
+	ID       uuid.UUID `json:"id"`
+	Email    string    `json:"email"`
+	TenantID uuid.UUID `json:"tenant_id"`
 	jwt.RegisteredClaims
 }
 
-func GenerateResetToken(email string) (string, error) {
-	claims := &ResetClaims{
-		Email: email,
+func GenerateMFAToken(id uuid.UUID, email string, tenantID uuid.UUID) (string, error) {
+	claims := &MFAClaims{
+		ID:       id,
+		Email:    email,
+		TenantID: tenantID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(1 * time.Hour)), // Токен дійсний 1 годину
+			Audience:  jwt.ClaimStrings{mfaAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(MFATokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
 }
 
-func VerifyResetToken(tokenString string) (*ResetClaims, error) {
-
-	token, err := jwt.ParseWithClaims(tokenString, &ResetClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Перевірка методу підпису
+func ParseMFAToken(tokenString string) (*MFAClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &MFAClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
 		return jwtSecret, nil
-	})
-
+	}, jwt.WithAudience(mfaAudience))
 	if err != nil {
 		return nil, err
 	}
+	if claims, ok := token.Claims.(*MFAClaims); ok && token.Valid {
+		return claims, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+// Токени операторів платформи підписуються окремим ключем,
+// щоб токен тентанта ніколи не давав доступу до /platform
+const platformAudience = "platform"
+
+type PlatformClaims struct {
+	ID    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+	jwt.RegisteredClaims
+}
 
-	// Перевіряємо чи токен валідний і чи містить наші claims
-	if claims, ok := token.Claims.(*ResetClaims); ok && token.Valid {
-		// Перевіряємо чи токен не прострочений
-		if claims.ExpiresAt.Time.Before(time.Now()) {
-			return nil, errors.New("token has expired")
+func platformSecret() ([]byte, error) {
+	secret := os.Getenv("PLATFORM_JWT_SECRET")
+	if secret == "" {
+		return nil, errors.New("PLATFORM_JWT_SECRET is not set")
+	}
+	return []byte(secret), nil
+}
+
+func GeneratePlatformToken(id uuid.UUID, email string) (string, error) {
+	secret, err := platformSecret()
+	if err != nil {
+		return "", err
+	}
+	claims := &PlatformClaims{
+		ID:    id,
+		Email: email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{platformAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(8 * time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+}
+
+func ParsePlatformToken(tokenString string) (*PlatformClaims, error) {
+	secret, err := platformSecret()
+	if err != nil {
+		return nil, err
+	}
+	token, err := jwt.ParseWithClaims(tokenString, &PlatformClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
 		}
+		return secret, nil
+	}, jwt.WithAudience(platformAudience))
+	if err != nil {
+		return nil, err
+	}
+	if claims, ok := token.Claims.(*PlatformClaims); ok && token.Valid {
 		return claims, nil
 	}
-
 	return nil, errors.New("invalid token")
 }
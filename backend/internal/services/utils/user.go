@@ -11,6 +11,8 @@ import (
 
 const userKey = "currentUser"
 
+const sessionIssuedKey = "sessionIssued"
+
 // Отримати userID з контексту
 func GetUserIDFromContext(ctx *gin.Context) (uuid.UUID, bool) {
 	userIDRaw, exists := ctx.Get("id")
@@ -28,6 +30,33 @@ func GetUserIDFromContext(ctx *gin.Context) (uuid.UUID, bool) {
 	return userID, true
 }
 
+// Отримати ID сесії, до якої прив'язаний access-токен
+func GetSessionIDFromContext(ctx *gin.Context) (uuid.UUID, bool) {
+	sidRaw, exists := ctx.Get("sid")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Session not found in context"})
+		return uuid.UUID{}, false
+	}
+
+	sid, ok := sidRaw.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid session ID format"})
+		return uuid.UUID{}, false
+	}
+
+	return sid, true
+}
+
+// MarkSessionIssued позначає, що запит видав сесію: лише після цього обмежувач
+// входу скидає лічильники (відповідь 200 з викликом 2FA входом ще не є)
+func MarkSessionIssued(ctx *gin.Context) {
+	ctx.Set(sessionIssuedKey, true)
+}
+
+func SessionIssued(ctx *gin.Context) bool {
+	return ctx.GetBool(sessionIssuedKey)
+}
+
 // Отримати користувача з контексту або БД з кешуванням
 func GetCurrentUserFromContext(ctx *gin.Context, db *gorm.DB) (*models.User, bool) {
 	// 1. Шукаємо в контексті
@@ -55,12 +84,3 @@ func GetCurrentUserFromContext(ctx *gin.Context, db *gorm.DB) (*models.User, boo
 
 	return &user, true
 }
-func GetIsSuperUser(db *gorm.DB, id uuid.UUID) (bool, error) {
-	var user models.User
-	err := repository.GetByID(db, id, &user)
-	if err != nil {
-		return false, err
-	}
-
-	return user.IsSuperUser, nil
-}
@@ -3,6 +3,8 @@ package main
 import (
 	"backend/internal/db/postgres"
 	"backend/internal/middleware"
+	"backend/internal/services/metering"
+	"backend/modules/audit"
 	"backend/modules/blog"
 	"backend/modules/calendar"
 	"backend/modules/calendar/service/reminder"
@@ -11,13 +13,24 @@ import (
 	"backend/modules/direct"
 	directWS "backend/modules/direct/handlers"
 	"backend/modules/employees"
+	"backend/modules/invitation"
+	invitationHandlers "backend/modules/invitation/handlers"
 	"backend/modules/item"
 	"backend/modules/media"
+	"backend/modules/presence"
+	presenceService "backend/modules/presence/service"
 	"backend/modules/property"
 	reacrionsRepository "backend/modules/reaction/repository"
+	"backend/modules/role"
+	"backend/modules/settings"
 	sseHandlers "backend/modules/sse/handlers"
+	"backend/modules/tenant"
+	tenantService "backend/modules/tenant/service"
 	"backend/modules/user"
 	"backend/modules/user/handlers"
+	"backend/modules/userimport"
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -25,6 +38,7 @@ import (
 	"log"
 	"net/http"
 	_ "net/http/pprof"
+	"net/url"
 	"os"
 	"strings"
 )
@@ -37,6 +51,17 @@ func init() {
 }
 
 func main() {
+	// Адміністративні команди:
+	//   ./main rotate-tenant-keys [--dry-run]
+	//   ./main create-platform-admin <email> <full name> <password>
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "rotate-tenant-keys":
+			os.Exit(rotateTenantKeys(len(os.Args) > 2 && os.Args[2] == "--dry-run"))
+		case "create-platform-admin":
+			os.Exit(createPlatformAdmin(os.Args[2:]))
+		}
+	}
 
 	go func() {
 		log.Println("Starting profiling server on :6060...")
@@ -45,6 +70,10 @@ func main() {
 	}()
 
 	postgres.InitAdminDB()
+	postgres.Pool.StartMaintenance()
+	postgres.StartTenantChangeListener(context.Background())
+	metering.Start()
+	presenceService.Start()
 
 	port := os.Getenv("APP_RUN_PORT")
 	fmt.Println(port)
@@ -55,12 +84,19 @@ func main() {
 	r.Use(redirectFromWWW())
 	r.Use(CustomCors())
 
-	// Login limiter middleware
-	r.Use(middleware.LoginLimiterMiddleware())
+	// Platform administration (не прив'язане до субдомену тентанта)
+	platform := r.Group("/platform/v1")
+	tenant.RegisterRoutes(platform)
 
 	// Choose DB
 	r.Use(middleware.TenantMiddleware())
 
+	// Login limiter middleware (політика і лічильники — в межах тентанта)
+	r.Use(middleware.LoginLimiterMiddleware())
+
+	// API usage metering
+	r.Use(middleware.UsageMiddleware())
+
 	// Start reminder jobs
 	reminder.StartAllTenantReminderJobs()
 
@@ -77,14 +113,30 @@ func main() {
 
 	//Auth
 	r.POST("/v1/login/access-token", handlers.LoginHandler)
+	r.POST("/v1/login/refresh-token", handlers.RefreshTokenHandler)
+	r.POST("/v1/login/2fa", handlers.VerifyMFALoginHandler)
+	r.POST("/v1/login/2fa/enroll", handlers.BeginMFAEnrollmentHandler)
+	r.POST("/v1/login/2fa/enroll/confirm", handlers.ConfirmMFAEnrollmentHandler)
+	r.GET("/v1/login/methods", handlers.LoginMethodsHandler)
+	r.POST("/v1/login/sso/start", handlers.StartSSOLoginHandler)
+	r.POST("/v1/login/sso/callback", handlers.SSOCallbackHandler)
 
 	// Password recovery
 	r.POST("/v1/password-recovery/:email", handlers.RequestPasswordRecover)
 	r.POST("/v1/reset-password/", handlers.ResetPassword)
 
+	// Invitations
+	r.GET("/v1/accept-invitation", invitationHandlers.PreviewInvitationHandler)
+	r.POST("/v1/accept-invitation", invitationHandlers.AcceptInvitationHandler)
+
 	r.POST("/v1/init-tenant-migrations", func(c *gin.Context) {
-		postgres.InitDB(c)
-		c.JSON(http.StatusOK, gin.H{"message": "Tenant DB migrated"})
+		dryRun := c.Query("dry_run") == "true"
+		steps, err := postgres.InitDB(c, dryRun)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "steps": steps})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Tenant DB migrated", "dry_run": dryRun, "steps": steps})
 	})
 
 	//Users
@@ -136,6 +188,24 @@ func main() {
 	// Direct messages routes
 	direct.RegisterRoutes(version)
 
+	// Tenant settings
+	settings.RegisterRoutes(version)
+
+	// Roles and permissions
+	role.RegisterRoutes(version)
+
+	// User invitations
+	invitation.RegisterRoutes(version)
+
+	// Audit log
+	audit.RegisterRoutes(version)
+
+	// Online presence
+	presence.RegisterRoutes(version)
+
+	// Bulk user import
+	userimport.RegisterRoutes(version)
+
 	// Run the server
 	if err := r.Run(port); err != nil {
 		fmt.Println("Failed to run server", err)
@@ -145,9 +215,43 @@ func main() {
 
 }
 
+func rotateTenantKeys(dryRun bool) int {
+	postgres.InitAdminDB()
+
+	report, err := tenantService.RotateEncryptionKeys(dryRun)
+	if err != nil {
+		log.Printf("❌ Key rotation failed: %v", err)
+		return 1
+	}
+
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(out))
+	if report.Failed > 0 || report.Unreadable > 0 {
+		return 1
+	}
+	return 0
+}
+
+func createPlatformAdmin(args []string) int {
+	if len(args) != 3 {
+		fmt.Println("usage: create-platform-admin <email> <full name> <password>")
+		return 2
+	}
+	postgres.InitAdminDB()
+
+	admin, err := tenantService.CreatePlatformAdmin(args[0], args[1], args[2])
+	if err != nil {
+		log.Printf("❌ Cannot create platform admin: %v", err)
+		return 1
+	}
+	log.Printf("✅ Platform admin %s created", admin.Email)
+	return 0
+}
+
 func redirectFromWWW() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if strings.HasPrefix(c.Request.Host, "www.") {
+		// www-аліас, зареєстрований тентантом, обслуговуємо як є
+		if strings.HasPrefix(c.Request.Host, "www.") && !postgres.Manager.IsVerifiedHost(c.Request.Host) {
 			newHost := "https://" + c.Request.Host[len("www."):]
 			c.Redirect(http.StatusMovedPermanently, newHost+c.Request.URL.String())
 			return
@@ -176,6 +280,11 @@ func CustomCors() gin.HandlerFunc {
 				return true
 			}
 
+			// Підтверджені власні домени та аліаси тентантів
+			if u, err := url.Parse(origin); err == nil && u.Host != "" {
+				return postgres.Manager.IsVerifiedHost(u.Host)
+			}
+
 			return false
 		},
 	}
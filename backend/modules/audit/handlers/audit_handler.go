@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	utils2 "backend/internal/services/utils"
+	"backend/modules/audit/models"
+	"backend/modules/audit/service"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"time"
+)
+
+func bindFilter(ctx *gin.Context) (models.AuditLogFilter, bool) {
+	var filter models.AuditLogFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter: " + err.Error()})
+		return filter, false
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter: from must be before to"})
+		return filter, false
+	}
+	return filter, true
+}
+
+func ListAuditLogsHandler(ctx *gin.Context) {
+	filter, ok := bindFilter(ctx)
+	if !ok {
+		return
+	}
+	db, ok := utils2.GetReadDBFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection not found"})
+		return
+	}
+	logs, err := service.ListAuditLogs(db, filter)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, logs)
+}
+
+func ExportAuditLogsHandler(ctx *gin.Context) {
+	filter, ok := bindFilter(ctx)
+	if !ok {
+		return
+	}
+	db, ok := utils2.GetReadDBFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Database connection not found"})
+		return
+	}
+	if _, err := service.CountForExport(db, filter); err != nil {
+		if errors.Is(err, service.ErrExportTooLarge) {
+			ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("audit-log-%s.csv", time.Now().UTC().Format("20060102-150405"))
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	ctx.Status(http.StatusOK)
+
+	// CSV пишеться потоком, тому після початку відповіді помилку можна лише залогувати
+	if err := service.ExportCSV(db, filter, ctx.Writer); err != nil {
+		log.Printf("❌ Audit log export failed: %v", err)
+		_ = ctx.Error(err)
+	}
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "audit",
+		Version: 1,
+		Name:    "create_audit_logs",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&auditLogV1{}); err != nil {
+				return err
+			}
+			// Журнал лише доповнюється: змінити чи видалити запис не може навіть застосунок
+			if err := tx.Exec(`
+				CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+				BEGIN
+					RAISE EXCEPTION 'audit_logs is append-only';
+				END;
+				$$ LANGUAGE plpgsql`).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs`).Error; err != nil {
+				return err
+			}
+			return tx.Exec(`
+				CREATE TRIGGER audit_logs_append_only
+				BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_logs
+				FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only()`).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Exec(`DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs`).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`DROP FUNCTION IF EXISTS audit_logs_append_only()`).Error; err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&auditLogV1{})
+		},
+	},
+}
+
+// Знімок схеми на момент міграції: зміни моделі не змінюють уже застосовану міграцію
+type auditLogV1 struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	CreatedAt  time.Time  `gorm:"not null;index"`
+	TenantID   uuid.UUID  `gorm:"type:uuid"`
+	ActorID    *uuid.UUID `gorm:"type:uuid;index"`
+	ActorEmail string
+	APIKeyID   *uuid.UUID                  `gorm:"type:uuid"`
+	EntityType string                      `gorm:"not null;index:idx_audit_entity"`
+	EntityID   string                      `gorm:"not null;index:idx_audit_entity"`
+	Action     string                      `gorm:"not null;index"`
+	Before     datatypes.JSON              `gorm:"type:jsonb"`
+	After      datatypes.JSON              `gorm:"type:jsonb"`
+	Changes    datatypes.JSONSlice[string] `gorm:"type:jsonb"`
+	IP         string
+	UserAgent  string
+	Method     string
+	Path       string
+	RequestID  string `gorm:"index"`
+}
+
+func (auditLogV1) TableName() string {
+	return "audit_logs"
+}
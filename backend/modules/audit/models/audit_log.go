@@ -0,0 +1,47 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// AuditLog — незмінний запис журналу: хто, коли і що змінив. Таблиця
+// лише доповнюється — UPDATE і DELETE забороняє тригер у міграції.
+type AuditLog struct {
+	ID         uuid.UUID                   `gorm:"type:uuid;primaryKey" json:"id"`
+	CreatedAt  time.Time                   `gorm:"not null;index" json:"createdAt"`
+	TenantID   uuid.UUID                   `gorm:"type:uuid" json:"tenantId"`
+	ActorID    *uuid.UUID                  `gorm:"type:uuid;index" json:"actorId,omitempty"`
+	ActorEmail string                      `json:"actorEmail,omitempty"`
+	APIKeyID   *uuid.UUID                  `gorm:"type:uuid" json:"apiKeyId,omitempty"`
+	EntityType string                      `gorm:"not null;index:idx_audit_entity" json:"entityType"`
+	EntityID   string                      `gorm:"not null;index:idx_audit_entity" json:"entityId"`
+	Action     string                      `gorm:"not null;index" json:"action"`
+	Before     datatypes.JSON              `gorm:"type:jsonb" json:"before,omitempty"`
+	After      datatypes.JSON              `gorm:"type:jsonb" json:"after,omitempty"`
+	Changes    datatypes.JSONSlice[string] `gorm:"type:jsonb" json:"changes,omitempty"`
+	IP         string                      `json:"ip,omitempty"`
+	UserAgent  string                      `json:"userAgent,omitempty"`
+	Method     string                      `json:"method,omitempty"`
+	Path       string                      `json:"path,omitempty"`
+	RequestID  string                      `gorm:"index" json:"requestId,omitempty"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+func (log *AuditLog) BeforeCreate(*gorm.DB) error {
+	if log.ID == uuid.Nil {
+		log.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// AuditLogFilter — параметри пошуку в журналі (query string)
+type AuditLogFilter struct {
+	EntityType string     `form:"entity_type"`
+	EntityID   string     `form:"entity_id"`
+	Action     string     `form:"action" binding:"omitempty,oneof=create update delete"`
+	ActorID    *uuid.UUID `form:"actor_id"`
+	RequestID  string     `form:"request_id"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      int        `form:"limit"`
+	Skip       int        `form:"skip"`
+}
+
+type AllAuditLogs struct {
+	Data  []AuditLog `json:"data"`
+	Count int64      `json:"count"`
+}
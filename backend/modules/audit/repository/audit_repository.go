@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"backend/modules/audit/models"
+	"gorm.io/gorm"
+)
+
+func applyFilter(db *gorm.DB, filter *models.AuditLogFilter) *gorm.DB {
+	query := db.Model(&models.AuditLog{})
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
+
+func CountAuditLogs(db *gorm.DB, filter *models.AuditLogFilter) (int64, error) {
+	var count int64
+	err := applyFilter(db, filter).Count(&count).Error
+	return count, err
+}
+
+func GetAuditLogs(db *gorm.DB, filter *models.AuditLogFilter) ([]models.AuditLog, int64, error) {
+	count, err := CountAuditLogs(db, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	var logs []models.AuditLog
+	err = applyFilter(db, filter).
+		Order("created_at DESC, id").
+		Limit(filter.Limit).
+		Offset(filter.Skip).
+		Find(&logs).Error
+	return logs, count, err
+}
+
+// EachAuditLog віддає записи пачками у хронологічному порядку — для експорту
+func EachAuditLog(db *gorm.DB, filter *models.AuditLogFilter, batchSize int, fn func([]models.AuditLog) error) error {
+	var batch []models.AuditLog
+	return applyFilter(db, filter).
+		Order("created_at, id").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
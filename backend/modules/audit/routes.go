@@ -0,0 +1,16 @@
+package audit
+
+import (
+	"backend/internal/middleware"
+	"backend/modules/audit/handlers"
+	roleModels "backend/modules/role/models"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(r *gin.RouterGroup) {
+	auditGroup := r.Group("/audit-logs", middleware.RequirePermission(roleModels.PermAuditRead))
+	{
+		auditGroup.GET("/", handlers.ListAuditLogsHandler)
+		auditGroup.GET("/export", handlers.ExportAuditLogsHandler)
+	}
+}
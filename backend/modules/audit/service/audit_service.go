@@ -0,0 +1,130 @@
+package service
+
+import (
+	"backend/internal/services/spreadsheet"
+	"backend/modules/audit/models"
+	"backend/modules/audit/repository"
+	"encoding/csv"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 500
+	exportBatchSize = 1000
+	// MaxExportRows обмежує один CSV-експорт; більші вибірки звужують фільтром дат
+	MaxExportRows = 100000
+)
+
+var ErrExportTooLarge = errors.New("too many audit entries for one export, narrow the filter")
+
+func normalizeFilter(filter *models.AuditLogFilter) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	if filter.Skip < 0 {
+		filter.Skip = 0
+	}
+}
+
+func ListAuditLogs(db *gorm.DB, filter models.AuditLogFilter) (*models.AllAuditLogs, error) {
+	normalizeFilter(&filter)
+	logs, count, err := repository.GetAuditLogs(db, &filter)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AllAuditLogs{Data: logs, Count: count}, nil
+}
+
+// CountForExport перевіряє розмір вибірки до того, як почнеться відповідь
+func CountForExport(db *gorm.DB, filter models.AuditLogFilter) (int64, error) {
+	count, err := repository.CountAuditLogs(db, &filter)
+	if err != nil {
+		return 0, err
+	}
+	if count > MaxExportRows {
+		return count, ErrExportTooLarge
+	}
+	return count, nil
+}
+
+var csvHeader = []string{
+	"created_at", "action", "entity_type", "entity_id", "actor_id", "actor_email", "api_key_id",
+	"changes", "before", "after", "ip", "user_agent", "method", "path", "request_id",
+}
+
+// ExportCSV пише журнал у CSV потоком, пачками в хронологічному порядку
+func ExportCSV(db *gorm.DB, filter models.AuditLogFilter, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+
+	written := 0
+	err := repository.EachAuditLog(db, &filter, exportBatchSize, func(batch []models.AuditLog) error {
+		for _, entry := range batch {
+			if written >= MaxExportRows {
+				return ErrExportTooLarge
+			}
+			if err := writer.Write(csvRow(entry)); err != nil {
+				return err
+			}
+			written++
+		}
+		writer.Flush()
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvRow — рядок експорту; значення приходять від користувачів (email, шлях,
+// User-Agent, зміни), тому кожна клітинка екранується від формул
+func csvRow(entry models.AuditLog) []string {
+	row := []string{
+		entry.CreatedAt.UTC().Format(time.RFC3339),
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		uuidString(entry.ActorID),
+		entry.ActorEmail,
+		uuidString(entry.APIKeyID),
+		strings.Join(entry.Changes, ";"),
+		jsonString(entry.Before),
+		jsonString(entry.After),
+		entry.IP,
+		entry.UserAgent,
+		entry.Method,
+		entry.Path,
+		entry.RequestID,
+	}
+	for i, cell := range row {
+		row[i] = spreadsheet.EscapeFormula(cell)
+	}
+	return row
+}
+
+func jsonString(raw []byte) string {
+	if s := string(raw); s != "null" {
+		return s
+	}
+	return ""
+}
+
+func uuidString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
@@ -4,6 +4,7 @@ import (
 	utils2 "backend/internal/services/utils"
 	"backend/modules/blog/models"
 	"backend/modules/blog/repository"
+	roleModels "backend/modules/role/models"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
@@ -29,6 +30,11 @@ func CreateBlogHandler(ctx *gin.Context) {
 
 	blog.OwnerID = userID
 
+	if blog.Status && !utils2.HasPermission(ctx, db, roleModels.PermBlogPublish) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Permission denied: " + roleModels.PermBlogPublish})
+		return
+	}
+
 	newBlog, err := repository.CreateBlog(db, &blog)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -50,9 +56,11 @@ func GetAllBlogsHandler(ctx *gin.Context) {
 		return
 	}
 
-	isSuperUser, _ := utils2.GetIsSuperUser(db, user.ID)
+	readAll := utils2.HasPermission(ctx, db, roleModels.PermBlogReadAll)
 
-	blogs, err := repository.GetAllBlogs(db, user.ID, isSuperUser)
+	// Список читаємо з репліки, якщо вона є
+	readDB, _ := utils2.GetReadDBFromContext(ctx)
+	blogs, err := repository.GetAllBlogs(readDB, user.ID, readAll)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -83,7 +91,7 @@ func GetBlogByIdHandler(ctx *gin.Context) {
 		return
 	}
 
-	if blog.OwnerID != userID {
+	if blog.OwnerID != userID && !utils2.HasPermission(ctx, db, roleModels.PermBlogReadAll) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Access denied"})
 		return
 	}
@@ -114,16 +122,26 @@ func UpdateBlogByIdHandler(ctx *gin.Context) {
 		return
 	}
 
-	blog, err := repository.UpdateBlogById(db, id, &update)
+	// Права перевіряємо до оновлення
+	existing, err := repository.GetBlogById(db, id)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-
-	if blog.OwnerID != user.ID {
+	if existing.OwnerID != user.ID && !utils2.HasPermission(ctx, db, roleModels.PermBlogManage) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Access denied"})
 		return
 	}
+	if existing.Status != update.Status && !utils2.HasPermission(ctx, db, roleModels.PermBlogPublish) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Permission denied: " + roleModels.PermBlogPublish})
+		return
+	}
+
+	blog, err := repository.UpdateBlogById(db, id, &update)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	ctx.JSON(http.StatusOK, blog)
 
@@ -152,7 +170,7 @@ func DeleteBlogByIdHandler(ctx *gin.Context) {
 		return
 	}
 
-	if blog.OwnerID != user.ID || !user.IsSuperUser {
+	if blog.OwnerID != user.ID && !utils2.HasPermission(ctx, db, roleModels.PermBlogManage) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Access denied"})
 		return
 	}
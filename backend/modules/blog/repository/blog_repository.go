@@ -42,14 +42,14 @@ func CreateBlog(db *gorm.DB, b *models.Blog) (*models.BlogPost, error) {
 	}, nil
 }
 
-func GetAllBlogs(db *gorm.DB, userId uuid.UUID, isSuperUser bool) (*models.BlogGetAll, error) {
+func GetAllBlogs(db *gorm.DB, userId uuid.UUID, readAll bool) (*models.BlogGetAll, error) {
 	var blogs []*models.Blog
 	var media []*mediaModel.Media
 	response := &models.BlogGetAll{}
 
 	// Формуємо базовий запит
 	query := db.Model(&models.Blog{}).Order("position ASC")
-	if !isSuperUser {
+	if !readAll {
 		query = query.Where("owner_id = ?", userId)
 	}
 
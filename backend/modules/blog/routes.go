@@ -1,17 +1,19 @@
 package blog
 
 import (
+	"backend/internal/middleware"
 	"backend/modules/blog/handlers"
+	roleModels "backend/modules/role/models"
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterRoutes(r *gin.RouterGroup) {
 	blogGroup := r.Group("/blog")
 	{
-		blogGroup.POST("/", handlers.CreateBlogHandler)
-		blogGroup.GET("/", handlers.GetAllBlogsHandler)
-		blogGroup.GET("/:id", handlers.GetBlogByIdHandler)
-		blogGroup.PATCH("/:id", handlers.UpdateBlogByIdHandler)
-		blogGroup.DELETE("/:id", handlers.DeleteBlogByIdHandler)
+		blogGroup.POST("/", middleware.RequirePermission(roleModels.PermBlogWrite), handlers.CreateBlogHandler)
+		blogGroup.GET("/", middleware.RequirePermission(roleModels.PermBlogRead), handlers.GetAllBlogsHandler)
+		blogGroup.GET("/:id", middleware.RequirePermission(roleModels.PermBlogRead), handlers.GetBlogByIdHandler)
+		blogGroup.PATCH("/:id", middleware.RequirePermission(roleModels.PermBlogWrite), handlers.UpdateBlogByIdHandler)
+		blogGroup.DELETE("/:id", middleware.RequirePermission(roleModels.PermBlogWrite), handlers.DeleteBlogByIdHandler)
 	}
 }
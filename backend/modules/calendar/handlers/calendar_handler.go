@@ -29,7 +29,7 @@ func CreateEventHandler(ctx *gin.Context) {
 
 	event.UserID = userID
 
-	newEvent, err := repository.CreateEvent(db, &event)
+	newEvent, err := repository.CreateEvent(db, &event, utils2.GetTenantSettingsFromContext(ctx).Location())
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -75,7 +75,7 @@ func UpdateCalendarEventHandler(ctx *gin.Context) {
 		return
 	}
 
-	updatedEvent, err := repository.CalendarUpdateEvent(db, eventId, &updateEvent)
+	updatedEvent, err := repository.CalendarUpdateEvent(db, eventId, &updateEvent, utils2.GetTenantSettingsFromContext(ctx).Location())
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -94,7 +94,7 @@ func GetAllEventsHandler(ctx *gin.Context) {
 		return
 	}
 
-	events, err := repository.GetAllEvents(db, userID)
+	events, err := repository.GetAllEvents(db, userID, utils2.GetTenantSettingsFromContext(ctx).Location())
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "calendar",
+		Version: 1,
+		Name:    "create_calendars",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&calendarV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&calendarV1{})
+		},
+	},
+}
+
+// Знімки схеми на момент міграції: зміни моделей не змінюють уже застосовані міграції
+
+type userRefV1 struct {
+	ID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (userRefV1) TableName() string {
+	return "users"
+}
+
+type calendarV1 struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Title          string    `gorm:"not null"`
+	Description    string    `gorm:"default:null"`
+	StartDate      time.Time `gorm:"not null"`
+	EndDate        time.Time `gorm:"not null"`
+	ReminderOffset int       `gorm:"default:0"`
+	AllDay         bool      `gorm:"not null"`
+	Color          string    `gorm:"not null"`
+	WorkingDay     bool      `gorm:"default false"`
+	SickDay        bool      `gorm:"default false"`
+	Vacation       bool      `gorm:"default false"`
+	Weekend        bool      `gorm:"default false"`
+	SendEmail      bool      `gorm:"default false"`
+	ReminderSent   bool      `gorm:"default false"`
+	UserID         uuid.UUID `gorm:"not null;index"`
+	User           userRefV1 `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (calendarV1) TableName() string {
+	return "calendars"
+}
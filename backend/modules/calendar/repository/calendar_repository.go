@@ -10,25 +10,19 @@ import (
 	"time"
 )
 
-func CreateEvent(db *gorm.DB, c *models.Calendar) (*models.CalendarEvent, error) {
+func CreateEvent(db *gorm.DB, c *models.Calendar, loc *time.Location) (*models.CalendarEvent, error) {
 	if c.Title == "" {
 		return nil, errors.New("the event name cannot be empty")
 	}
 	if c.StartDate.After(c.EndDate) {
 		return nil, errors.New("the start date cannot be after the end date")
 	}
-	// Завантажуємо часовий пояс Варшави
-	warsawLoc, err := time.LoadLocation("Europe/Warsaw")
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	// Гарантуємо, що час завжди в UTC
 	c.ID = uuid.New()
-	c.StartDate = c.StartDate.In(warsawLoc)
-	c.EndDate = c.EndDate.In(warsawLoc)
+	c.StartDate = c.StartDate.In(loc)
+	c.EndDate = c.EndDate.In(loc)
 
-	reminderTime := c.StartDate.Add(-time.Duration(c.ReminderOffset) * time.Minute).In(warsawLoc)
+	reminderTime := c.StartDate.Add(-time.Duration(c.ReminderOffset) * time.Minute).In(loc)
 
 	log.Printf("📌 The event '%s' reminds us of %s ", c.Title, reminderTime)
 
@@ -40,8 +34,8 @@ func CreateEvent(db *gorm.DB, c *models.Calendar) (*models.CalendarEvent, error)
 		ID:             c.ID,
 		Title:          c.Title,
 		Description:    c.Description,
-		StartDate:      c.StartDate.In(warsawLoc),
-		EndDate:        c.EndDate.In(warsawLoc),
+		StartDate:      c.StartDate.In(loc),
+		EndDate:        c.EndDate.In(loc),
 		AllDay:         c.AllDay,
 		ReminderOffset: c.ReminderOffset,
 		Color:          c.Color,
@@ -55,7 +49,7 @@ func CreateEvent(db *gorm.DB, c *models.Calendar) (*models.CalendarEvent, error)
 	}, nil
 }
 
-func GetAllEvents(db *gorm.DB, userId uuid.UUID) ([]models.CalendarEvent, error) {
+func GetAllEvents(db *gorm.DB, userId uuid.UUID, loc *time.Location) ([]models.CalendarEvent, error) {
 	var events []models.Calendar
 	var response []models.CalendarEvent
 
@@ -67,18 +61,14 @@ func GetAllEvents(db *gorm.DB, userId uuid.UUID) ([]models.CalendarEvent, error)
 	if len(events) == 0 {
 		return []models.CalendarEvent{}, nil
 	}
-	warsawLoc, err := time.LoadLocation("Europe/Warsaw")
-	if err != nil {
-		log.Fatal(err)
-	}
 
 	for _, event := range events {
 		response = append(response, models.CalendarEvent{
 			ID:             event.ID,
 			Title:          event.Title,
 			Description:    event.Description,
-			StartDate:      event.StartDate.In(warsawLoc),
-			EndDate:        event.EndDate.In(warsawLoc),
+			StartDate:      event.StartDate.In(loc),
+			EndDate:        event.EndDate.In(loc),
 			ReminderOffset: event.ReminderOffset,
 			AllDay:         event.AllDay,
 			Color:          event.Color,
@@ -123,7 +113,7 @@ func GetEventById(db *gorm.DB, eventId uuid.UUID) (*models.CalendarEvent, error)
 	}, nil
 }
 
-func CalendarUpdateEvent(db *gorm.DB, eventId uuid.UUID, eventUpdate *models.CalendarEventUpdate) (*models.CalendarEvent, error) {
+func CalendarUpdateEvent(db *gorm.DB, eventId uuid.UUID, eventUpdate *models.CalendarEventUpdate, loc *time.Location) (*models.CalendarEvent, error) {
 	var event models.Calendar
 
 	err := repository.GetByID(db, eventId, &event)
@@ -172,16 +162,12 @@ func CalendarUpdateEvent(db *gorm.DB, eventId uuid.UUID, eventUpdate *models.Cal
 	if err != nil {
 		return nil, err
 	}
-	warsawLoc, err := time.LoadLocation("Europe/Warsaw")
-	if err != nil {
-		log.Fatal(err)
-	}
 	return &models.CalendarEvent{
 		ID:             event.ID,
 		Title:          event.Title,
 		Description:    event.Description,
-		StartDate:      event.StartDate.In(warsawLoc),
-		EndDate:        event.EndDate.In(warsawLoc),
+		StartDate:      event.StartDate.In(loc),
+		EndDate:        event.EndDate.In(loc),
 		ReminderOffset: event.ReminderOffset,
 		AllDay:         event.AllDay,
 		Color:          event.Color,
@@ -1,16 +1,18 @@
 package calendar
 
 import (
+	"backend/internal/middleware"
 	"backend/modules/calendar/handlers"
+	roleModels "backend/modules/role/models"
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterRoutes(r *gin.RouterGroup) {
 	calendarGroup := r.Group("/calendar")
 	{
-		calendarGroup.POST("/events", handlers.CreateEventHandler)
-		calendarGroup.GET("/events", handlers.GetAllEventsHandler)
-		calendarGroup.PATCH("/events/:id", handlers.UpdateCalendarEventHandler)
-		calendarGroup.DELETE("/events/:id", handlers.DeleteCalendarEventHandler)
+		calendarGroup.POST("/events", middleware.RequirePermission(roleModels.PermCalendarWrite), handlers.CreateEventHandler)
+		calendarGroup.GET("/events", middleware.RequireSession(), handlers.GetAllEventsHandler)
+		calendarGroup.PATCH("/events/:id", middleware.RequirePermission(roleModels.PermCalendarWrite), handlers.UpdateCalendarEventHandler)
+		calendarGroup.DELETE("/events/:id", middleware.RequirePermission(roleModels.PermCalendarWrite), handlers.DeleteCalendarEventHandler)
 	}
 }
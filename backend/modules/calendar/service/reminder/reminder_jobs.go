@@ -1,29 +1,41 @@
 package reminder
 
 import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
 	"backend/modules/calendar/models"
 	"backend/modules/calendar/service"
-	"gorm.io/gorm"
 	"log"
 	"time"
 )
 
-func StartReminderJobs(db *gorm.DB, tenantDomain string) {
-	go scheduleReminders(db, tenantDomain)
+// StartReminderJobs запускає перевірку нагадувань тентанта. Підключення
+// береться на кожен запуск: пул тентанта може бути витіснений і закритий.
+func StartReminderJobs(tenantDomain string) {
+	go scheduleReminders(tenantDomain)
 	log.Printf("✅ Reminder launched for %s!", tenantDomain)
 }
 
-func scheduleReminders(db *gorm.DB, tenantDomain string) {
-	// Завантажуємо часовий пояс Варшави
-	warsawLoc, err := time.LoadLocation("Europe/Warsaw")
-	if err != nil {
-		log.Fatal(err)
-	}
+func scheduleReminders(tenantDomain string) {
 	for {
 		log.Printf("[🔁 %s] Checking events...", tenantDomain)
 
 		time.Sleep(1 * time.Minute) // Перевіряємо кожну хвилину
 
+		// Налаштування читаємо щоразу, щоб підхопити зміну часового поясу чи SMTP
+		settings, err := postgres.Manager.TenantSettings(tenantDomain)
+		if err != nil {
+			log.Printf("[❌ %s] Error loading settings: %v", tenantDomain, err)
+			continue
+		}
+		loc := settings.Location()
+
+		db, err := postgres.Manager.GetConnectionByDomain(tenantDomain)
+		if err != nil {
+			log.Printf("[❌ %s] DB error: %v", tenantDomain, err)
+			continue
+		}
+
 		events, err := service.GetUpcomingReminders(db)
 		if err != nil {
 			log.Printf("[❌ %s] Error receiving events: %v", tenantDomain, err)
@@ -36,34 +48,38 @@ func scheduleReminders(db *gorm.DB, tenantDomain string) {
 		}
 
 		for _, event := range events {
-			reminderTime := event.StartDate.Add(-time.Duration(event.ReminderOffset) * time.Minute).In(warsawLoc)
+			reminderTime := event.StartDate.Add(-time.Duration(event.ReminderOffset) * time.Minute).In(loc)
 			timeUntilReminder := time.Until(reminderTime)
 
 			log.Printf("[📌 %s] Event '%s' should be reminded at %s (via %v)", tenantDomain, event.Title, reminderTime, timeUntilReminder)
 
-			scheduleReminder(db, event, reminderTime)
+			scheduleReminder(tenantDomain, event, reminderTime, settings)
 		}
 	}
 }
 
-func scheduleReminder(db *gorm.DB, event models.Calendar, reminderTime time.Time) {
-	// Завантажуємо часовий пояс Варшави
-	warsawLoc, err := time.LoadLocation("Europe/Warsaw")
-	if err != nil {
-		log.Fatal(err)
-	}
-	reminderTime = reminderTime.In(warsawLoc)
+func scheduleReminder(tenantDomain string, event models.Calendar, reminderTime time.Time, settings entities.TenantSettings) {
+	reminderTime = reminderTime.In(settings.Location())
 	timeUntilReminder := time.Until(reminderTime)
 
 	log.Printf("🕒 The event '%s' is reminded by: %v (UTC)", event.Title, timeUntilReminder)
 
 	if timeUntilReminder <= 0 {
 		log.Printf("⚠️ Reminder time for event '%s' has expired! Execute immediately.", event.Title)
-		go SendReminder(db, event)
+		go sendTenantReminder(tenantDomain, event, settings)
 		return
 	}
 
 	time.AfterFunc(timeUntilReminder, func() {
-		SendReminder(db, event)
+		sendTenantReminder(tenantDomain, event, settings)
 	})
 }
+
+func sendTenantReminder(tenantDomain string, event models.Calendar, settings entities.TenantSettings) {
+	db, err := postgres.Manager.GetConnectionByDomain(tenantDomain)
+	if err != nil {
+		log.Printf("[❌ %s] DB error, reminder '%s' skipped: %v", tenantDomain, event.Title, err)
+		return
+	}
+	SendReminder(db, event, settings)
+}
@@ -1,6 +1,7 @@
 package reminder
 
 import (
+	"backend/internal/entities"
 	"backend/internal/services/utils"
 	"backend/modules/calendar/models"
 	"backend/modules/calendar/service"
@@ -8,10 +9,9 @@ import (
 	"fmt"
 	"gorm.io/gorm"
 	"log"
-	"time"
 )
 
-func SendReminder(db *gorm.DB, event models.Calendar) {
+func SendReminder(db *gorm.DB, event models.Calendar, settings entities.TenantSettings) {
 	user, err := repository.GetUserById(db, event.UserID)
 	if err != nil {
 		log.Printf("⚠️ Event '%s' has no user email, skipped.\n", event.Title)
@@ -20,11 +20,6 @@ func SendReminder(db *gorm.DB, event models.Calendar) {
 
 	log.Printf("👤 Found user: %s (%s)", user.FullName, user.Email)
 
-	warsawLoc, err := time.LoadLocation("Europe/Warsaw")
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	subject := fmt.Sprintf("🔔 Reminder.: %s", event.Title)
 	message := fmt.Sprintf(`
 		<h3>Hello, %s!</h3>
@@ -32,10 +27,10 @@ func SendReminder(db *gorm.DB, event models.Calendar) {
 		<p>Details: %s</p>
 		<hr>
 		<p><em>This is an automated message. Do not reply to it.</em></p>`,
-		user.FullName, event.Title, event.StartDate.In(warsawLoc).Format("02.01.2006 15:04"), event.Description,
+		user.FullName, event.Title, event.StartDate.In(settings.Location()).Format("02.01.2006 15:04"), event.Description,
 	)
 
-	err = utils.SendEmail(user.Email, subject, message, true)
+	err = utils.SendTenantEmail(&settings, user.Email, subject, message, true)
 	if err != nil {
 		log.Printf("❌ Error sending email for an event '%s' (%s): %v\n", event.Title, user.Email, err)
 		return
@@ -16,16 +16,7 @@ func StartAllTenantReminderJobs() {
 	}
 
 	for _, tenant := range tenants {
-		t := tenant
-		go func(t entities.Tenant) {
-			db, err := postgres2.Manager.GetConnectionByDomain(t.Domain)
-			if err != nil {
-				log.Printf("❌ DB error for tenant %s: %v", t.Domain, err)
-				return
-			}
-
-			StartReminderJobs(db, t.Domain)
-			log.Printf("✅ Reminder started for tenant: %s", t.Domain)
-		}(t)
+		StartReminderJobs(tenant.Domain)
+		log.Printf("✅ Reminder started for tenant: %s", tenant.Domain)
 	}
 }
@@ -1,7 +1,9 @@
 package buffer
 
 import (
+	"backend/internal/db/postgres"
 	"backend/internal/services/bufferedwriter"
+	"backend/internal/services/metering"
 	"backend/modules/chat/messages/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -15,7 +17,9 @@ var (
 	poolMutex sync.Mutex
 )
 
-func GetOrCreateWriter(tenantID uuid.UUID, db *gorm.DB) *bufferedwriter.Writer[models.Messages] {
+// GetOrCreateWriter повертає буфер тентанта. Підключення береться заново
+// на кожне скидання, бо пул тентанта може бути витіснений, поки буфер живе.
+func GetOrCreateWriter(tenantID uuid.UUID, domain string) *bufferedwriter.Writer[models.Messages] {
 	poolMutex.Lock()
 	defer poolMutex.Unlock()
 
@@ -24,7 +28,9 @@ func GetOrCreateWriter(tenantID uuid.UUID, db *gorm.DB) *bufferedwriter.Writer[m
 	}
 
 	writer := bufferedwriter.NewWriter[models.Messages](
-		db,
+		func() (*gorm.DB, error) {
+			return postgres.Manager.GetConnectionByDomain(domain)
+		},
 		5*time.Second,
 		30,
 		func(tx *gorm.DB, items []models.Messages) error {
@@ -32,6 +38,7 @@ func GetOrCreateWriter(tenantID uuid.UUID, db *gorm.DB) *bufferedwriter.Writer[m
 		},
 		func(count int) {
 			log.Printf("✅ [%s] збережено %d повідомлень", tenantID, count)
+			metering.Record(tenantID, metering.ChatMessages, int64(count))
 		},
 		func(err error) {
 			log.Printf("❌ [%s] помилка збереження повідомлень: %v", tenantID, err)
@@ -5,8 +5,11 @@ import (
 	"backend/modules/chat/messages/buffer"
 	messageDTO "backend/modules/chat/messages/models"
 	messageRepository "backend/modules/chat/messages/repository"
+	presence "backend/modules/presence/service"
 	reactionDTO "backend/modules/reaction/models"
 	"backend/modules/reaction/repository"
+	userService "backend/modules/user/service"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
@@ -46,7 +49,14 @@ func HandleWebSocket(ctx *gin.Context) {
 	token := ctx.Query("token")
 	roomIDStr := ctx.Query("room_id")
 
-	user, err := utils2.ParseJWTToken(token)
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		log.Println("❌ DB context відсутній")
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	user, err := userService.AuthenticateStream(db, token)
 	if err != nil {
 		log.Println("❌ Невалідний токен:", err)
 		ctx.AbortWithStatus(http.StatusUnauthorized)
@@ -60,16 +70,12 @@ func HandleWebSocket(ctx *gin.Context) {
 		return
 	}
 
-	db, ok := utils2.GetDBFromContext(ctx)
-	if !ok {
-		log.Println("❌ DB context відсутній")
-		ctx.AbortWithStatus(http.StatusInternalServerError)
-		return
-	}
+	// Історію повідомлень можна читати з репліки; оновлення одного повідомлення — лише з основної БД
+	readDB, _ := utils2.GetReadDBFromContext(ctx)
 
 	tenantID := user.TenantID
 	// 🔁 Отримати або створити буфер для цього тенанта
-	writer := buffer.GetOrCreateWriter(tenantID, db)
+	writer := buffer.GetOrCreateWriter(tenantID, user.Tenant)
 
 	// ✅ Тепер апгрейдимо WebSocket
 	conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
@@ -84,6 +90,16 @@ func HandleWebSocket(ctx *gin.Context) {
 		}
 	}(conn)
 
+	// Відкликана сесія закриває сокет: цикл читання нижче завершиться.
+	// Спостереження зупиняється з хендлером, ще до звільнення пулу тентанта.
+	watchCtx, stopWatch := context.WithCancel(ctx.Request.Context())
+	defer stopWatch()
+	userService.WatchSession(watchCtx, db, user, func() {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "session revoked"), time.Now().Add(time.Second))
+		_ = conn.Close()
+	})
+
 	fmt.Printf("🔌 Користувач %s приєднався до кімнати %s\n", user.ID, roomID)
 
 	// 🔐 Реєструємо клієнта
@@ -94,8 +110,14 @@ func HandleWebSocket(ctx *gin.Context) {
 	clients[roomID][conn] = true
 	mutex.Unlock()
 
+	// Поки сокет відкритий, користувач "онлайн"; кожне повідомлення — активність
+	tenant, hasTenant := utils2.GetTenantFromContext(ctx)
+	if hasTenant {
+		defer presence.Manager.Connect(tenant.ID, user.ID, db)()
+	}
+
 	// 📜 Надсилаємо історію
-	if history, err := messageRepository.GetMessagesPaginated(db, roomID, 30, nil); err == nil {
+	if history, err := messageRepository.GetMessagesPaginated(readDB, roomID, 30, nil); err == nil {
 		if historyData, err := json.Marshal(history); err == nil {
 			err := conn.WriteMessage(websocket.TextMessage, historyData)
 			if err != nil {
@@ -114,6 +136,9 @@ func HandleWebSocket(ctx *gin.Context) {
 			mutex.Unlock()
 			break
 		}
+		if hasTenant {
+			presence.Manager.Touch(tenant.ID, user.ID)
+		}
 
 		var raw map[string]interface{}
 		if err := json.Unmarshal(msg, &raw); err != nil {
@@ -152,7 +177,7 @@ func HandleWebSocket(ctx *gin.Context) {
 			limit := int(raw["limit"].(float64))
 			beforeID, _ := uuid.Parse(raw["before"].(string))
 
-			msgs, err := messageRepository.GetMessagesPaginated(db, roomID, limit, &beforeID)
+			msgs, err := messageRepository.GetMessagesPaginated(readDB, roomID, limit, &beforeID)
 			if err != nil {
 				log.Println("❌ Error loading messages:", err)
 				continue
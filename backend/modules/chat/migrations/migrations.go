@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "chat",
+		Version: 1,
+		Name:    "create_rooms_and_messages",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&chatRoomV1{}, &messageV1{}, &messageReadV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&messageReadV1{}, &messageV1{}, &chatRoomV1{})
+		},
+	},
+}
+
+// Знімки схеми на момент міграції: зміни моделей не змінюють уже застосовані міграції
+
+type userRefV1 struct {
+	ID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (userRefV1) TableName() string {
+	return "users"
+}
+
+type messageV1 struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserId    uuid.UUID `gorm:"type:uuid;"`
+	RoomId    uuid.UUID `gorm:"type:uuid;"`
+	Message   string    `gorm:"type:string"`
+	CreatedAt time.Time `gorm:"type:time"`
+	UpdatedAt time.Time
+	EditedAt  *time.Time `gorm:"type:timestamp"`
+	User      userRefV1  `gorm:"foreignKey:UserId;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (messageV1) TableName() string {
+	return "messages"
+}
+
+type messageReadV1 struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"not null;index:idx_user_msg,unique"`
+	MessageID uuid.UUID `gorm:"not null;index:idx_user_msg,unique"`
+	ReadAt    time.Time `gorm:"not null"`
+
+	CreatedAt time.Time
+}
+
+func (messageReadV1) TableName() string {
+	return "message_reads"
+}
+
+type chatRoomV1 struct {
+	ID          uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	NameRoom    string      `gorm:"not null"`
+	Description string      `gorm:"type:string"`
+	Image       string      `gorm:"not null"`
+	Status      bool        `gorm:"default:false"`
+	IsChannel   bool        `gorm:"default:false"`
+	OwnerId     uuid.UUID   `gorm:"type:uuid;"`
+	CreatedAt   time.Time   `gorm:"type:time"`
+	Messages    []messageV1 `gorm:"foreignKey:RoomId;constraint:OnDelete:CASCADE"`
+}
+
+func (chatRoomV1) TableName() string {
+	return "chat_rooms"
+}
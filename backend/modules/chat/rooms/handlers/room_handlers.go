@@ -4,6 +4,7 @@ import (
 	utils2 "backend/internal/services/utils"
 	models2 "backend/modules/chat/rooms/models"
 	"backend/modules/chat/rooms/repository"
+	roleModels "backend/modules/role/models"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
@@ -102,17 +103,23 @@ func UpdateRoomByIdHandler(ctx *gin.Context) {
 		return
 	}
 
-	room, err := repository.UpdateRoomById(db, id, &update)
+	// Права перевіряємо до оновлення
+	existing, err := repository.GetRoomById(db, id)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-
-	if room.OwnerId != userID {
+	if existing.OwnerId != userID && !utils2.HasPermission(ctx, db, roleModels.PermChatRoomsManage) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Access denied"})
 		return
 	}
 
+	room, err := repository.UpdateRoomById(db, id, &update)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, room)
 
 }
@@ -139,13 +146,7 @@ func DeleteRoomByIdHandler(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	isSuperUser, err := utils2.GetIsSuperUser(db, userID)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if room.OwnerId != userID || !isSuperUser {
+	if room.OwnerId != userID && !utils2.HasPermission(ctx, db, roleModels.PermChatRoomsManage) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Access denied"})
 		return
 	}
@@ -1,12 +1,13 @@
 package rooms
 
 import (
+	"backend/internal/middleware"
 	"backend/modules/chat/rooms/handlers"
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterRoutes(r *gin.RouterGroup) {
-	roomGroup := r.Group("/rooms")
+	roomGroup := r.Group("/rooms", middleware.RequireSession())
 	{
 		roomGroup.POST("/", handlers.CreateRoomHandler)
 		roomGroup.GET("/", handlers.GetAllRoomsHandler)
@@ -5,7 +5,10 @@ import (
 	direct "backend/modules/direct/client"
 	"backend/modules/direct/models"
 	directRepoository "backend/modules/direct/repository"
+	presence "backend/modules/presence/service"
 	"backend/modules/sse"
+	userService "backend/modules/user/service"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
@@ -31,7 +34,7 @@ func DirectChatWebSocket(ctx *gin.Context) {
 	}
 
 	token := ctx.Query("token")
-	user, err := internal.ParseJWTToken(token)
+	user, err := userService.AuthenticateStream(db, token)
 	if err != nil {
 		log.Println("❌ Невалідний токен:", err)
 		ctx.AbortWithStatus(http.StatusUnauthorized)
@@ -60,6 +63,16 @@ func DirectChatWebSocket(ctx *gin.Context) {
 		}
 	}(conn)
 
+	// Відкликана сесія закриває сокет: цикл читання нижче завершиться.
+	// Спостереження зупиняється з хендлером, ще до звільнення пулу тентанта.
+	watchCtx, stopWatch := context.WithCancel(ctx.Request.Context())
+	defer stopWatch()
+	userService.WatchSession(watchCtx, db, user, func() {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "session revoked"), time.Now().Add(time.Second))
+		_ = conn.Close()
+	})
+
 	client := &direct.Client{
 		UserID: userID,
 		ChatID: chatID,
@@ -69,6 +82,11 @@ func DirectChatWebSocket(ctx *gin.Context) {
 	direct.Manager.AddClient(chatID, client)
 	defer direct.Manager.RemoveClient(chatID, client)
 
+	tenant, hasTenant := internal.GetTenantFromContext(ctx)
+	if hasTenant {
+		defer presence.Manager.Connect(tenant.ID, userID, db)()
+	}
+
 	log.Printf("✅ WebSocket connected user %s chat %s\n", userID, chatID)
 
 	if history, err := directRepoository.GetDirectMessagesPaginated(db, chatID, userID, 30, nil); err == nil {
@@ -89,6 +107,9 @@ func DirectChatWebSocket(ctx *gin.Context) {
 		if err := conn.ReadJSON(&raw); err != nil {
 			break
 		}
+		if hasTenant {
+			presence.Manager.Touch(tenant.ID, userID)
+		}
 
 		processDirectEvent(raw, userID, chatID, userID, user.FullName, conn, db, client)
 	}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"backend/internal/services/utils"
 	"backend/modules/direct/repository"
+	presence "backend/modules/presence/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
@@ -28,5 +29,17 @@ func GetDirectChatUsers(ctx *gin.Context) {
 		return
 	}
 
+	// Статус присутності підтягуємо одним запитом до трекера
+	if tenant, ok := utils.GetTenantFromContext(ctx); ok {
+		ids := make([]uuid.UUID, 0, len(users))
+		for _, user := range users {
+			ids = append(ids, user.ID)
+		}
+		statuses := presence.Manager.Statuses(tenant.ID, ids)
+		for i := range users {
+			users[i].Presence = statuses[users[i].ID]
+		}
+	}
+
 	ctx.JSON(http.StatusOK, users)
 }
@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "direct",
+		Version: 1,
+		Name:    "create_direct_messages",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&directMessageV1{}, &directChatV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&directChatV1{}, &directMessageV1{})
+		},
+	},
+}
+
+// Знімки схеми на момент міграції: зміни моделей не змінюють уже застосовані міграції
+
+type directChatV1 struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserAID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	UserBID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (directChatV1) TableName() string {
+	return "direct_chats"
+}
+
+type directMessageV1 struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ChatID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	SenderID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	Message   string    `gorm:"type:text"`
+	Reaction  string    `gorm:"type:text"`
+	IsRead    bool      `gorm:"default:false"`
+	CreatedAt time.Time
+	EditedAt  *time.Time
+}
+
+func (directMessageV1) TableName() string {
+	return "direct_messages"
+}
@@ -2,13 +2,14 @@ package direct
 
 //
 import (
+	"backend/internal/middleware"
 	"backend/modules/direct/handlers"
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterRoutes(r *gin.RouterGroup) {
 
-	directGroup := r.Group("/direct")
+	directGroup := r.Group("/direct", middleware.RequireSession())
 	{
 		directGroup.GET("/users", handlers.GetDirectChatUsers)
 		directGroup.POST("/chats", handlers.GetOrCreateDirectChat)
@@ -4,6 +4,7 @@ import (
 	utils2 "backend/internal/services/utils"
 	"backend/modules/employees/models"
 	"backend/modules/employees/repository"
+	roleModels "backend/modules/role/models"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
@@ -31,6 +32,12 @@ func ReadUserEmployeesById(ctx *gin.Context) {
 		return
 	}
 
+	// Зарплату бачить сам працівник або користувач з employees.salary.read
+	currentUserID, _ := utils2.GetUserIDFromContext(ctx)
+	if id != currentUserID && !utils2.HasPermission(ctx, db, roleModels.PermEmployeesSalaryRead) {
+		user.Salary = ""
+	}
+
 	ctx.JSON(http.StatusOK, user)
 }
 
@@ -47,14 +54,11 @@ func UpdateUserEmployeesByIdHandler(ctx *gin.Context) {
 		return
 	}
 
+	// Дозвіл employees.write перевіряє RequirePermission на маршруті
 	user, ok := utils2.GetCurrentUserFromContext(ctx, db)
 	if !ok {
 		return
 	}
-	if !user.IsSuperUser {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
-		return
-	}
 
 	var updateEmployee models.UpdateUserEmployees
 	if err := ctx.ShouldBindJSON(&updateEmployee); err != nil {
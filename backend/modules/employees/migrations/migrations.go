@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "employees",
+		Version: 1,
+		Name:    "create_employees",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&employeeV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&employeeV1{})
+		},
+	},
+}
+
+// Знімок схеми на момент міграції: зміни моделі не змінюють уже застосовану міграцію
+type employeeV1 struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID            uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	PhoneNumber1      string    `gorm:"type:varchar(255);default:null"`
+	PhoneNumber2      string    `gorm:"type:varchar(255);default:null"`
+	Company           string    `gorm:"type:varchar(255);default:null"`
+	Position          string    `gorm:"type:varchar(255);default:null"`
+	ConditionType     string    `gorm:"type:varchar(255);default:null"`
+	Salary            string    `gorm:"type:varchar(255);default:null"`
+	Address           string    `gorm:"type:varchar(255);default:null"`
+	DateStart         *time.Time
+	DateEnd           *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	ExtraData         datatypes.JSON `gorm:"type:jsonb"`
+	WhuCreatedByID    uuid.UUID      `gorm:"type:uuid;"`
+	WhuCreatedByAcron string         `gorm:"type:varchar(255)"`
+	WhuUpdatedByID    *uuid.UUID     `gorm:"type:uuid;"`
+	WhuUpdatedByAcron *string        `gorm:"type:varchar(255)"`
+}
+
+func (employeeV1) TableName() string {
+	return "employees"
+}
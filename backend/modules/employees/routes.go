@@ -1,7 +1,9 @@
 package employees
 
 import (
+	"backend/internal/middleware"
 	"backend/modules/employees/handlers"
+	roleModels "backend/modules/role/models"
 	"github.com/gin-gonic/gin"
 )
 
@@ -9,7 +11,7 @@ func RegisterRoutes(r *gin.RouterGroup) {
 
 	userGroup := r.Group("/employees")
 	{
-		userGroup.GET("/:id", handlers.ReadUserEmployeesById)
-		userGroup.PATCH("/:id", handlers.UpdateUserEmployeesByIdHandler)
+		userGroup.GET("/:id", middleware.RequirePermission(roleModels.PermEmployeesRead), handlers.ReadUserEmployeesById)
+		userGroup.PATCH("/:id", middleware.RequirePermission(roleModels.PermEmployeesWrite), handlers.UpdateUserEmployeesByIdHandler)
 	}
 }
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"backend/internal/services/metering"
+	utils2 "backend/internal/services/utils"
+	"backend/modules/invitation/models"
+	"backend/modules/invitation/service"
+	roleModels "backend/modules/role/models"
+	userService "backend/modules/user/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"log"
+	"net/http"
+	"strings"
+)
+
+func respondInvitationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, metering.ErrQuotaExceeded):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidInvitation):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err.Error() == "invitation not found" || err.Error() == "role not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err.Error() == "user already exists" || err.Error() == "user is already invited" ||
+		strings.HasPrefix(err.Error(), "invitation is already"):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err.Error() == "invitation was sent recently":
+		ctx.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "permission denied"):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func ListInvitationsHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	invitations, err := service.ListInvitations(db, ctx.Query("status"))
+	if err != nil {
+		respondInvitationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, invitations)
+}
+
+func CreateInvitationHandler(ctx *gin.Context) {
+	var req models.CreateInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	inviter, ok := utils2.GetCurrentUserFromContext(ctx, db)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+	settings := utils2.GetTenantSettingsFromContext(ctx)
+	canAssignRoles := utils2.HasPermission(ctx, db, roleModels.PermRolesManage)
+
+	invitation, err := service.Invite(db, tenant, &settings, inviter, &req, canAssignRoles)
+	if err != nil {
+		if invitation != nil {
+			// Запрошення створене, але лист не пішов — його можна надіслати повторно
+			log.Println("❌ Invitation email failed:", err)
+			ctx.JSON(http.StatusCreated, gin.H{"invitation": invitation, "warning": err.Error()})
+			return
+		}
+		respondInvitationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, invitation)
+}
+
+func ResendInvitationHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	inviter, ok := utils2.GetCurrentUserFromContext(ctx, db)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+	settings := utils2.GetTenantSettingsFromContext(ctx)
+
+	invitation, err := service.Resend(db, tenant, &settings, inviter, id)
+	if err != nil {
+		if invitation != nil {
+			log.Println("❌ Invitation email failed:", err)
+			ctx.JSON(http.StatusBadGateway, gin.H{"invitation": invitation, "error": err.Error()})
+			return
+		}
+		respondInvitationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, invitation)
+}
+
+func RevokeInvitationHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := service.Revoke(db, id); err != nil {
+		respondInvitationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Invitation revoked"})
+}
+
+// PreviewInvitationHandler — публічний: сторінка прийняття показує, куди запрошують
+func PreviewInvitationHandler(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Token is required"})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	settings := utils2.GetTenantSettingsFromContext(ctx)
+	preview, err := service.Preview(db, &settings, token)
+	if err != nil {
+		respondInvitationError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, preview)
+}
+
+// AcceptInvitationHandler — публічний: встановлює пароль і одразу відкриває сесію
+func AcceptInvitationHandler(ctx *gin.Context) {
+	var req models.AcceptInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+
+	user, err := service.Accept(db, &req)
+	if err != nil {
+		respondInvitationError(ctx, err)
+		return
+	}
+
+	// Якщо тентант вимагає 2FA — спершу звичайний вхід і налаштування другого фактора
+	if utils2.GetTenantSettingsFromContext(ctx).Require2FA {
+		ctx.JSON(http.StatusOK, gin.H{"message": "Invitation accepted, please log in"})
+		return
+	}
+
+	tokens, err := userService.StartSession(db, user, tenant, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+	ctx.JSON(http.StatusOK, tokens)
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "invitation",
+		Version: 1,
+		Name:    "create_invitations",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&invitationV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&invitationV1{})
+		},
+	},
+}
+
+// Знімок схеми на момент міграції: зміни моделі не змінюють уже застосовану міграцію
+type invitationV1 struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	Email       string    `gorm:"not null;index"`
+	RoleID      uuid.UUID `gorm:"type:uuid;not null"`
+	InvitedByID uuid.UUID `gorm:"type:uuid;not null"`
+	TokenHash   string    `gorm:"not null;uniqueIndex"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	LastSentAt  time.Time
+	SendCount   int        `gorm:"default:1"`
+	AcceptedAt  *time.Time `gorm:"default:null"`
+	RevokedAt   *time.Time `gorm:"default:null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (invitationV1) TableName() string {
+	return "invitations"
+}
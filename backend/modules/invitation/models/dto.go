@@ -0,0 +1,47 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type CreateInvitationRequest struct {
+	Email    string     `json:"email" binding:"required,email"`
+	FullName string     `json:"fullName"`
+	RoleID   *uuid.UUID `json:"roleId"`
+}
+
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	FullName string `json:"fullName"`
+}
+
+type InvitationResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"userId"`
+	Email       string     `json:"email"`
+	RoleID      uuid.UUID  `json:"roleId"`
+	RoleName    string     `json:"roleName"`
+	InvitedByID uuid.UUID  `json:"invitedById"`
+	Status      string     `json:"status"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	LastSentAt  time.Time  `json:"lastSentAt"`
+	SendCount   int        `json:"sendCount"`
+	AcceptedAt  *time.Time `json:"acceptedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+type AllInvitations struct {
+	Data  []InvitationResponse `json:"data"`
+	Count int                  `json:"count"`
+}
+
+// InvitationPreview — що бачить запрошений до встановлення пароля
+type InvitationPreview struct {
+	Email     string    `json:"email"`
+	FullName  string    `json:"fullName"`
+	BrandName string    `json:"brandName"`
+	RoleName  string    `json:"roleName"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
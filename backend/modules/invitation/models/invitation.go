@@ -0,0 +1,53 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusAccepted = "accepted"
+	StatusRevoked  = "revoked"
+	StatusExpired  = "expired"
+)
+
+// Invitation — запрошення до тентанта. Користувач створюється одразу
+// (неактивним, зі статусом "invited"), а токен з листа зберігається лише як хеш.
+type Invitation struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	Email       string     `gorm:"not null;index" json:"email"`
+	RoleID      uuid.UUID  `gorm:"type:uuid;not null" json:"roleId"`
+	InvitedByID uuid.UUID  `gorm:"type:uuid;not null" json:"invitedById"`
+	TokenHash   string     `gorm:"not null;uniqueIndex" json:"-"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expiresAt"`
+	LastSentAt  time.Time  `json:"lastSentAt"`
+	SendCount   int        `gorm:"default:1" json:"sendCount"`
+	AcceptedAt  *time.Time `gorm:"default:null" json:"acceptedAt,omitempty"`
+	RevokedAt   *time.Time `gorm:"default:null" json:"revokedAt,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (i *Invitation) BeforeCreate(*gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+func (i *Invitation) Status() string {
+	switch {
+	case i.AcceptedAt != nil:
+		return StatusAccepted
+	case i.RevokedAt != nil:
+		return StatusRevoked
+	case time.Now().After(i.ExpiresAt):
+		return StatusExpired
+	default:
+		return StatusPending
+	}
+}
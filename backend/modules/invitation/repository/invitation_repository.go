@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"backend/modules/invitation/models"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+func CreateInvitation(db *gorm.DB, invitation *models.Invitation) error {
+	return db.Create(invitation).Error
+}
+
+func SaveInvitation(db *gorm.DB, invitation *models.Invitation) error {
+	return db.Save(invitation).Error
+}
+
+func GetInvitationByID(db *gorm.DB, id uuid.UUID) (*models.Invitation, error) {
+	var invitation models.Invitation
+	if err := db.Where("id = ?", id).First(&invitation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invitation not found")
+		}
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func GetInvitationByTokenHash(db *gorm.DB, tokenHash string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	if err := db.Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// GetPendingInvitationForUpdate блокує активне запрошення до кінця транзакції
+func GetPendingInvitationForUpdate(tx *gorm.DB, tokenHash string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// GetAllInvitations повертає запрошення, найновіші першими. status фільтрує
+// за станом: pending, expired, accepted, revoked; порожній — усі.
+func GetAllInvitations(db *gorm.DB, status string) ([]models.Invitation, error) {
+	query := db.Order("created_at DESC")
+	now := time.Now()
+	switch status {
+	case models.StatusPending:
+		query = query.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", now)
+	case models.StatusExpired:
+		query = query.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at <= ?", now)
+	case models.StatusAccepted:
+		query = query.Where("accepted_at IS NOT NULL")
+	case models.StatusRevoked:
+		query = query.Where("revoked_at IS NOT NULL")
+	}
+
+	var invitations []models.Invitation
+	if err := query.Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
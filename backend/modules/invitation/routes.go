@@ -0,0 +1,18 @@
+package invitation
+
+import (
+	"backend/internal/middleware"
+	"backend/modules/invitation/handlers"
+	roleModels "backend/modules/role/models"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(r *gin.RouterGroup) {
+	invitationGroup := r.Group("/invitations", middleware.RequirePermission(roleModels.PermUsersManage))
+	{
+		invitationGroup.GET("/", handlers.ListInvitationsHandler)
+		invitationGroup.POST("/", handlers.CreateInvitationHandler)
+		invitationGroup.POST("/:id/resend", handlers.ResendInvitationHandler)
+		invitationGroup.DELETE("/:id", handlers.RevokeInvitationHandler)
+	}
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"backend/internal/entities"
+	"backend/internal/services/metering"
+	utils2 "backend/internal/services/utils"
+	"backend/modules/invitation/models"
+	"backend/modules/invitation/repository"
+	roleModels "backend/modules/role/models"
+	roleRepository "backend/modules/role/repository"
+	userModels "backend/modules/user/models"
+	userRepository "backend/modules/user/repository"
+	userService "backend/modules/user/service"
+	userUtils "backend/modules/user/utils"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	InvitationTTL = 7 * 24 * time.Hour
+	// Не частіше одного листа на хвилину для одного запрошення
+	resendInterval = time.Minute
+)
+
+var ErrInvalidInvitation = errors.New("invalid or expired invitation")
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newInvitationToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func ToResponse(invitation *models.Invitation, roleName string) models.InvitationResponse {
+	return models.InvitationResponse{
+		ID:          invitation.ID,
+		UserID:      invitation.UserID,
+		Email:       invitation.Email,
+		RoleID:      invitation.RoleID,
+		RoleName:    roleName,
+		InvitedByID: invitation.InvitedByID,
+		Status:      invitation.Status(),
+		ExpiresAt:   invitation.ExpiresAt,
+		LastSentAt:  invitation.LastSentAt,
+		SendCount:   invitation.SendCount,
+		AcceptedAt:  invitation.AcceptedAt,
+		CreatedAt:   invitation.CreatedAt,
+	}
+}
+
+func roleNames(db *gorm.DB) map[uuid.UUID]string {
+	names := make(map[uuid.UUID]string)
+	roles, err := roleRepository.GetAllRoles(db)
+	if err != nil {
+		return names
+	}
+	for _, role := range roles {
+		names[role.ID] = role.Name
+	}
+	return names
+}
+
+func sendInvitation(tenant *entities.Tenant, settings *entities.TenantSettings, inviterName string, invitation *models.Invitation, token string) error {
+	link := userService.FrontendBaseURL(tenant) + "/accept-invitation?token=" + url.QueryEscape(token)
+	if err := utils2.SendInvitationEmail(settings, invitation.Email, inviterName, link, invitation.ExpiresAt); err != nil {
+		return errors.New("failed to send invitation email")
+	}
+	return nil
+}
+
+// Invite створює неактивного користувача з вибраною роллю і надсилає лист.
+// Якщо лист не пішов, запрошення лишається — його можна надіслати повторно.
+func Invite(db *gorm.DB, tenant *entities.Tenant, settings *entities.TenantSettings, inviter *userModels.User,
+	req *models.CreateInvitationRequest, canAssignRoles bool) (*models.InvitationResponse, error) {
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	existing, err := userRepository.GetUserByEmail(db, email)
+	if err == nil {
+		if existing.InvitedAt != nil {
+			return nil, errors.New("user is already invited")
+		}
+		return nil, errors.New("user already exists")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var role *roleModels.Role
+	if req.RoleID != nil {
+		role, err = roleRepository.GetRoleByID(db, *req.RoleID)
+	} else {
+		role, err = roleRepository.GetRoleByName(db, roleModels.RoleMember)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if role.Name != roleModels.RoleMember && !canAssignRoles {
+		return nil, errors.New("permission denied: " + roleModels.PermRolesManage)
+	}
+
+	fullName := strings.TrimSpace(req.FullName)
+	if fullName == "" {
+		fullName = strings.Split(email, "@")[0]
+	}
+
+	var pending *PendingInvitation
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := metering.ReserveSeats(tx, tenant.ID, 1); err != nil {
+			return err
+		}
+		pending, err = CreateInvitedUser(tx, &userModels.User{FullName: fullName, Email: email}, role.ID, inviter)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToResponse(pending.Invitation, role.Name)
+	if err := pending.Send(tenant, settings, inviter.FullName); err != nil {
+		return &response, err
+	}
+	return &response, nil
+}
+
+// PendingInvitation — запрошення, створене в транзакції; лист надсилається вже після коміту
+type PendingInvitation struct {
+	Invitation *models.Invitation
+	User       *userModels.User
+	token      string
+}
+
+func (pending *PendingInvitation) Send(tenant *entities.Tenant, settings *entities.TenantSettings, inviterName string) error {
+	return sendInvitation(tenant, settings, inviterName, pending.Invitation, pending.token)
+}
+
+// CreateInvitedUser створює в транзакції tx неактивного користувача з роллю і
+// запрошення до нього. Email, роль і ліміт місць перевіряє той, хто викликає.
+func CreateInvitedUser(tx *gorm.DB, user *userModels.User, roleID uuid.UUID, inviter *userModels.User) (*PendingInvitation, error) {
+	token, err := newInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+	// Пароль, який ніхто не знає: увійти можна лише після прийняття запрошення
+	placeholder, err := newInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+	user.Password = placeholder
+
+	if _, err := userRepository.CreateUser(tx, user, inviter.ID, inviter.Acronym); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	// is_active має default:true, тому false задаємо окремим оновленням
+	err = tx.Model(&userModels.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+		"is_active":  false,
+		"invited_at": now,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	user.IsActive = false
+	user.InvitedAt = &now
+	if err := roleRepository.SetUserRoles(tx, user.ID, []uuid.UUID{roleID}); err != nil {
+		return nil, err
+	}
+
+	invitation := &models.Invitation{
+		UserID:      user.ID,
+		Email:       user.Email,
+		RoleID:      roleID,
+		InvitedByID: inviter.ID,
+		TokenHash:   hashInvitationToken(token),
+		ExpiresAt:   now.Add(InvitationTTL),
+		LastSentAt:  now,
+		SendCount:   1,
+	}
+	if err := repository.CreateInvitation(tx, invitation); err != nil {
+		return nil, err
+	}
+	return &PendingInvitation{Invitation: invitation, User: user, token: token}, nil
+}
+
+func ListInvitations(db *gorm.DB, status string) (*models.AllInvitations, error) {
+	invitations, err := repository.GetAllInvitations(db, status)
+	if err != nil {
+		return nil, err
+	}
+	names := roleNames(db)
+	data := make([]models.InvitationResponse, 0, len(invitations))
+	for i := range invitations {
+		data = append(data, ToResponse(&invitations[i], names[invitations[i].RoleID]))
+	}
+	return &models.AllInvitations{Data: data, Count: len(data)}, nil
+}
+
+// Resend видає нове посилання (старе перестає діяти) і продовжує термін
+func Resend(db *gorm.DB, tenant *entities.Tenant, settings *entities.TenantSettings, inviter *userModels.User, id uuid.UUID) (*models.InvitationResponse, error) {
+	invitation, err := repository.GetInvitationByID(db, id)
+	if err != nil {
+		return nil, err
+	}
+	status := invitation.Status()
+	if status != models.StatusPending && status != models.StatusExpired {
+		return nil, errors.New("invitation is already " + status)
+	}
+	if time.Since(invitation.LastSentAt) < resendInterval {
+		return nil, errors.New("invitation was sent recently")
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	invitation.TokenHash = hashInvitationToken(token)
+	invitation.ExpiresAt = now.Add(InvitationTTL)
+	invitation.LastSentAt = now
+	invitation.SendCount++
+	if err := repository.SaveInvitation(db, invitation); err != nil {
+		return nil, err
+	}
+
+	response := ToResponse(invitation, roleNames(db)[invitation.RoleID])
+	if err := sendInvitation(tenant, settings, inviter.FullName, invitation, token); err != nil {
+		return &response, err
+	}
+	return &response, nil
+}
+
+// Revoke скасовує запрошення і видаляє ще не активованого користувача
+func Revoke(db *gorm.DB, id uuid.UUID) error {
+	invitation, err := repository.GetInvitationByID(db, id)
+	if err != nil {
+		return err
+	}
+	status := invitation.Status()
+	if status != models.StatusPending && status != models.StatusExpired {
+		return errors.New("invitation is already " + status)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		invitation.RevokedAt = &now
+		if err := repository.SaveInvitation(tx, invitation); err != nil {
+			return err
+		}
+		user, err := userRepository.GetUserByIdFull(tx, invitation.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if user.InvitedAt == nil {
+			return nil
+		}
+		return userRepository.DeleteUserById(tx, user.ID)
+	})
+}
+
+func Preview(db *gorm.DB, settings *entities.TenantSettings, token string) (*models.InvitationPreview, error) {
+	invitation, err := repository.GetInvitationByTokenHash(db, hashInvitationToken(token))
+	if err != nil || invitation.Status() != models.StatusPending {
+		return nil, ErrInvalidInvitation
+	}
+	user, err := userRepository.GetUserByIdFull(db, invitation.UserID)
+	if err != nil {
+		return nil, ErrInvalidInvitation
+	}
+	return &models.InvitationPreview{
+		Email:     invitation.Email,
+		FullName:  user.FullName,
+		BrandName: settings.BrandName,
+		RoleName:  roleNames(db)[invitation.RoleID],
+		ExpiresAt: invitation.ExpiresAt,
+	}, nil
+}
+
+// Accept встановлює пароль, активує користувача і закриває запрошення
+func Accept(db *gorm.DB, req *models.AcceptInvitationRequest) (*userModels.User, error) {
+	var user *userModels.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		invitation, err := repository.GetPendingInvitationForUpdate(tx, hashInvitationToken(req.Token))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidInvitation
+			}
+			return err
+		}
+
+		user, err = userRepository.GetUserByIdFull(tx, invitation.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidInvitation
+			}
+			return err
+		}
+
+		hashedPassword, err := userUtils.HashPassword(req.Password)
+		if err != nil {
+			return err
+		}
+		user.Password = hashedPassword
+		user.IsActive = true
+		user.InvitedAt = nil
+		if name := strings.TrimSpace(req.FullName); name != "" {
+			user.FullName = name
+		}
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		invitation.AcceptedAt = &now
+		return repository.SaveInvitation(tx, invitation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
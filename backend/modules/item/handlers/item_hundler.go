@@ -5,6 +5,7 @@ import (
 	utils2 "backend/internal/services/utils"
 	"backend/modules/item/models"
 	"backend/modules/item/repository"
+	roleModels "backend/modules/role/models"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
@@ -63,7 +64,7 @@ func GetItemByID(ctx *gin.Context) {
 		return
 	}
 
-	if item.OwnerID != user.ID && !user.IsSuperUser {
+	if item.OwnerID != user.ID && !utils2.HasPermission(ctx, db, roleModels.PermItemsReadAll) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Access denied"})
 		return
 	}
@@ -127,17 +128,23 @@ func UpdateItemByIdHandler(ctx *gin.Context) {
 		return
 	}
 
-	item, err := repository.UpdateItemById(db, id, &update)
+	// Права перевіряємо до оновлення
+	existing, err := repository.GetItemById(db, id)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-
-	if item.OwnerID != user.ID && !user.IsSuperUser {
+	if existing.OwnerID != user.ID && !utils2.HasPermission(ctx, db, roleModels.PermItemsManage) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Access denied"})
 		return
 	}
 
+	item, err := repository.UpdateItemById(db, id, &update)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, item)
 
 }
@@ -155,9 +162,9 @@ func GetAllItemsHandler(ctx *gin.Context) {
 		return
 	}
 
-	isSuperUser, _ := utils2.GetIsSuperUser(db, user.ID)
+	readAll := utils2.HasPermission(ctx, db, roleModels.PermItemsReadAll)
 
-	language := ctx.DefaultQuery("language", "pl")
+	language := utils2.GetTenantSettingsFromContext(ctx).Language(ctx.Query("language"))
 	skip, _ := strconv.Atoi(ctx.DefaultQuery("skip", "0"))
 	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "100"))
 
@@ -167,7 +174,9 @@ func GetAllItemsHandler(ctx *gin.Context) {
 		Limit:    limit,
 	}
 
-	items, err := repository.GetAllItems(db, user.ID, isSuperUser, params)
+	// Список читаємо з репліки, якщо вона є
+	readDB, _ := utils2.GetReadDBFromContext(ctx)
+	items, err := repository.GetAllItems(readDB, user.ID, readAll, params)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -200,7 +209,7 @@ func DeleteItemByIdHandler(ctx *gin.Context) {
 		return
 	}
 
-	if item.OwnerID != user.ID || !user.IsSuperUser {
+	if item.OwnerID != user.ID && !utils2.HasPermission(ctx, db, roleModels.PermItemsManage) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Access denied"})
 		return
 	}
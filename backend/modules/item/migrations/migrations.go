@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "item",
+		Version: 1,
+		Name:    "create_items",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&itemV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&itemV1{})
+		},
+	},
+}
+
+// Знімки схеми на момент міграції: зміни моделей не змінюють уже застосовані міграції
+
+type userRefV1 struct {
+	ID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (userRefV1) TableName() string {
+	return "users"
+}
+
+type itemV1 struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Title     string    `gorm:"not null"`
+	Content   string    `gorm:"not null"`
+	Price     float64   `gorm:"not null"`
+	Quantity  int       `gorm:"not null"`
+	Position  int       `gorm:"not null"`
+	Language  string    `gorm:"not null"`
+	ItemUrl   string    `gorm:"default:null"`
+	Category  string    `gorm:"default:null"`
+	Status    bool      `gorm:"default:false"`
+	OwnerID   uuid.UUID `gorm:"not null;index"`
+	User      userRefV1 `gorm:"foreignKey:OwnerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (itemV1) TableName() string {
+	return "items"
+}
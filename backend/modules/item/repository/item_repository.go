@@ -188,14 +188,14 @@ func DeleteItemById(db *gorm.DB, id uuid.UUID) error {
 	return nil
 }
 
-func GetAllItems(db *gorm.DB, userId uuid.UUID, isSuperUser bool, parameters *entities.Parameters) (*models.ItemGetAll, error) {
+func GetAllItems(db *gorm.DB, userId uuid.UUID, readAll bool, parameters *entities.Parameters) (*models.ItemGetAll, error) {
 	if parameters == nil {
 		parameters = &entities.Parameters{}
 	}
 
 	// Значення за замовчуванням
 	if parameters.Language == "" {
-		parameters.Language = "pl"
+		parameters.Language = entities.DefaultLanguage
 	}
 	if parameters.Skip < 0 {
 		parameters.Skip = 0
@@ -212,8 +212,8 @@ func GetAllItems(db *gorm.DB, userId uuid.UUID, isSuperUser bool, parameters *en
 	// Формуємо базовий запит
 	query := db
 
-	// Якщо не суперюзер, додаємо фільтр за власником
-	if !isSuperUser {
+	// Без дозволу items.read.all показуємо лише власні
+	if !readAll {
 		query = query.Where("owner_id = ?", userId)
 	}
 
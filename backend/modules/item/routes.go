@@ -1,19 +1,21 @@
 package item
 
 import (
+	"backend/internal/middleware"
 	"backend/modules/item/handlers"
+	roleModels "backend/modules/role/models"
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterRoutes(r *gin.RouterGroup) {
 	itemGroup := r.Group("/items")
 	{
-		itemGroup.POST("/", handlers.CreateItemHandler)
-		itemGroup.GET("/", handlers.GetAllItemsHandler)
-		itemGroup.GET("/:id", handlers.GetItemByID)
-		itemGroup.PATCH("/:id", handlers.UpdateItemByIdHandler)
-		itemGroup.GET("/languages", handlers.GetAvailableLanguages)
-		itemGroup.GET("/categories", handlers.GetAvailableCategories)
-		itemGroup.DELETE("/:id", handlers.DeleteItemByIdHandler)
+		itemGroup.POST("/", middleware.RequirePermission(roleModels.PermItemsWrite), handlers.CreateItemHandler)
+		itemGroup.GET("/", middleware.RequirePermission(roleModels.PermItemsRead), handlers.GetAllItemsHandler)
+		itemGroup.GET("/:id", middleware.RequirePermission(roleModels.PermItemsRead), handlers.GetItemByID)
+		itemGroup.PATCH("/:id", middleware.RequirePermission(roleModels.PermItemsWrite), handlers.UpdateItemByIdHandler)
+		itemGroup.GET("/languages", middleware.RequirePermission(roleModels.PermItemsRead), handlers.GetAvailableLanguages)
+		itemGroup.GET("/categories", middleware.RequirePermission(roleModels.PermItemsRead), handlers.GetAvailableCategories)
+		itemGroup.DELETE("/:id", middleware.RequirePermission(roleModels.PermItemsWrite), handlers.DeleteItemByIdHandler)
 	}
 }
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"backend/internal/services/metering"
 	"backend/internal/services/utils"
 	"backend/modules/media/models"
 	"backend/modules/media/repository"
 	"backend/modules/media/service"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"log"
@@ -49,7 +51,7 @@ func DownloadMediaHandler(ctx *gin.Context) {
 		// Завантажуємо файл у Backblaze B2
 		fileUrl, err := service.UploadFile(ctx, fileHeader)
 		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondUploadError(ctx, err)
 			return
 		}
 		fileUrls = append(fileUrls, fileUrl)
@@ -83,7 +85,7 @@ func DownloadMediaOneImageHandler(ctx *gin.Context) {
 
 	fileUrl, err := service.UploadFile(ctx, file)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondUploadError(ctx, err)
 		return
 	}
 
@@ -208,3 +210,11 @@ func DeleteImageFromUrl(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "Image deleted successfully"})
 }
+
+func respondUploadError(ctx *gin.Context, err error) {
+	if errors.Is(err, metering.ErrQuotaExceeded) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "media",
+		Version: 1,
+		Name:    "create_media",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&mediaV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&mediaV1{})
+		},
+	},
+}
+
+// Знімок схеми на момент міграції: зміни моделі не змінюють уже застосовану міграцію
+type mediaV1 struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ContentId uuid.UUID `gorm:"type:uuid;"`
+	Url       string    `gorm:"type:string"`
+	Type      string    `gorm:"type:string"`
+	CreatedAt time.Time `gorm:"type:time"`
+}
+
+func (mediaV1) TableName() string {
+	return "media"
+}
@@ -1,17 +1,19 @@
 package media
 
 import (
+	"backend/internal/middleware"
 	"backend/modules/media/handlers"
+	roleModels "backend/modules/role/models"
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterRoutes(r *gin.RouterGroup) {
 	mediaGroup := r.Group("/media")
 	{
-		mediaGroup.POST("/:postId/images", handlers.DownloadMediaHandler)
-		mediaGroup.POST("/images", handlers.DownloadMediaOneImageHandler)
-		mediaGroup.GET("/images/:postId", handlers.GetAllMediaByBlogIdHandler)
-		mediaGroup.DELETE("/images/:postId", handlers.DeleteMediaHandler)
-		mediaGroup.DELETE("/images/url", handlers.DeleteImageFromUrl)
+		mediaGroup.POST("/:postId/images", middleware.RequirePermission(roleModels.PermMediaUpload), handlers.DownloadMediaHandler)
+		mediaGroup.POST("/images", middleware.RequirePermission(roleModels.PermMediaUpload), handlers.DownloadMediaOneImageHandler)
+		mediaGroup.GET("/images/:postId", middleware.RequireSession(), handlers.GetAllMediaByBlogIdHandler)
+		mediaGroup.DELETE("/images/:postId", middleware.RequirePermission(roleModels.PermMediaDelete), handlers.DeleteMediaHandler)
+		mediaGroup.DELETE("/images/url", middleware.RequirePermission(roleModels.PermMediaDelete), handlers.DeleteImageFromUrl)
 	}
 }
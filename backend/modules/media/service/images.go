@@ -1,6 +1,8 @@
 package service
 
 import (
+	"backend/internal/services/metering"
+	"backend/internal/services/utils"
 	"context"
 	"fmt"
 	"github.com/Backblaze/blazer/b2"
@@ -18,6 +20,15 @@ func UploadFile(ctx *gin.Context, fileHeader *multipart.FileHeader) (string, err
 	if accountID == "" || applicationKey == "" || bucketName == "" {
 		return "", fmt.Errorf("backblaze credentials are not set")
 	}
+
+	// Перевіряємо квоту сховища тентанта до завантаження
+	tenant, metered := utils.GetTenantFromContext(ctx)
+	if metered {
+		if err := metering.Check(tenant.ID, metering.MediaBytes, fileHeader.Size); err != nil {
+			return "", err
+		}
+	}
+
 	// Відкриваємо файл
 	file, err := fileHeader.Open()
 	if err != nil {
@@ -55,6 +66,10 @@ func UploadFile(ctx *gin.Context, fileHeader *multipart.FileHeader) (string, err
 		return "", fmt.Errorf("failed to close writer: %v", err)
 	}
 
+	if metered {
+		metering.Record(tenant.ID, metering.MediaBytes, fileHeader.Size)
+	}
+
 	// Формуємо публічний URL
 	publicURL := fmt.Sprintf("https://f003.backblazeb2.com/file/%s/%s", bucketName, uniqueFileName)
 
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"backend/internal/services/utils"
+	userService "backend/modules/user/service"
+	"context"
 	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -9,6 +11,7 @@ import (
 	"log"
 	"net/http"
 	"sync"
+	"time"
 )
 
 var upgrader = websocket.Upgrader{
@@ -65,9 +68,14 @@ func (m *NotificationManager) SendToUser(userID uuid.UUID, msg NotificationMessa
 }
 
 func NotificationWebSocketHandler(ctx *gin.Context) {
+	db, ok := utils.GetDBFromContext(ctx)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
 
 	token := ctx.Query("token")
-	user, err := utils.ParseJWTToken(token)
+	user, err := userService.AuthenticateStream(db, token)
 	if err != nil {
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
@@ -79,6 +87,16 @@ func NotificationWebSocketHandler(ctx *gin.Context) {
 		return
 	}
 
+	// Відкликана сесія закриває сокет: цикл читання нижче завершиться.
+	// Спостереження зупиняється з хендлером, ще до звільнення пулу тентанта.
+	watchCtx, stopWatch := context.WithCancel(ctx.Request.Context())
+	defer stopWatch()
+	userService.WatchSession(watchCtx, db, user, func() {
+		_ = conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "session revoked"), time.Now().Add(time.Second))
+		_ = conn.Close()
+	})
+
 	client := &NotificationClient{
 		UserID: user.ID,
 		Conn:   conn,
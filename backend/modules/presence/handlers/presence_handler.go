@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	utils2 "backend/internal/services/utils"
+	"backend/modules/presence/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+)
+
+// Скільки користувачів можна запитати за раз
+const maxPresenceQuery = 500
+
+type setPresenceRequest struct {
+	Status string `json:"status" binding:"required,oneof=online away"`
+}
+
+// GetPresenceHandler — статуси кількох користувачів: ?userIds=id1,id2,...
+func GetPresenceHandler(ctx *gin.Context) {
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+
+	var ids []uuid.UUID
+	for _, raw := range strings.Split(ctx.Query("userIds"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID: " + raw})
+			return
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "userIds is required"})
+		return
+	}
+	if len(ids) > maxPresenceQuery {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Too many user IDs"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, service.Manager.Statuses(tenant.ID, ids))
+}
+
+// SetPresenceHandler — клієнт повідомляє, що вкладка неактивна або знову активна
+func SetPresenceHandler(ctx *gin.Context) {
+	var req setPresenceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	userID, ok := utils2.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+
+	if !service.Manager.SetAway(tenant.ID, userID, req.Status == service.StatusAway) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "No live connection for this user"})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": service.Manager.Status(tenant.ID, userID)})
+}
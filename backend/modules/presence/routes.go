@@ -0,0 +1,15 @@
+package presence
+
+import (
+	"backend/internal/middleware"
+	"backend/modules/presence/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(r *gin.RouterGroup) {
+	presenceGroup := r.Group("/presence", middleware.RequireSession())
+	{
+		presenceGroup.GET("/", handlers.GetPresenceHandler)
+		presenceGroup.POST("/", handlers.SetPresenceHandler)
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"backend/modules/sse"
+	userModels "backend/modules/user/models"
+	"encoding/json"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOnline  = "online"
+	StatusAway    = "away"
+	StatusOffline = "offline"
+)
+
+// Без активності протягом AwayAfter користувач з живим підключенням стає "away"
+const (
+	AwayAfter     = 5 * time.Minute
+	sweepInterval = 30 * time.Second
+)
+
+// Change — подія зміни статусу, яку отримують клієнти тентанта через SSE
+type Change struct {
+	UserID     uuid.UUID  `json:"userId"`
+	Status     string     `json:"status"`
+	LastSeenAt *time.Time `json:"lastSeenAt,omitempty"`
+}
+
+type entry struct {
+	connections int
+	lastActive  time.Time
+	away        bool // клієнт сам повідомив, що вкладка неактивна
+	status      string
+}
+
+func (e *entry) compute(now time.Time) string {
+	if e.away || now.Sub(e.lastActive) >= AwayAfter {
+		return StatusAway
+	}
+	return StatusOnline
+}
+
+// Tracker рахує живі підключення (чат, директ, SSE) кожного користувача в межах тентанта
+type Tracker struct {
+	mu       sync.Mutex
+	tenants  map[uuid.UUID]map[uuid.UUID]*entry
+	onChange func(tenantID uuid.UUID, recipients []uuid.UUID, change Change)
+}
+
+func NewTracker(onChange func(tenantID uuid.UUID, recipients []uuid.UUID, change Change)) *Tracker {
+	return &Tracker{
+		tenants:  make(map[uuid.UUID]map[uuid.UUID]*entry),
+		onChange: onChange,
+	}
+}
+
+var Manager = NewTracker(broadcast)
+
+// Start запускає фонову перевірку неактивних користувачів
+func Start() {
+	go func() {
+		ticker := time.NewTicker(sweepInterval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			Manager.Sweep(now)
+		}
+	}()
+}
+
+// Connect реєструє підключення; повернена функція викликається при відключенні.
+// Коли закривається останнє підключення, last_seen_at користувача оновлюється в БД.
+func (t *Tracker) Connect(tenantID, userID uuid.UUID, db *gorm.DB) func() {
+	now := time.Now()
+	t.mu.Lock()
+	users := t.tenants[tenantID]
+	if users == nil {
+		users = make(map[uuid.UUID]*entry)
+		t.tenants[tenantID] = users
+	}
+	e := users[userID]
+	if e == nil {
+		e = &entry{status: StatusOffline}
+		users[userID] = e
+	}
+	e.connections++
+	e.lastActive = now
+	e.away = false
+	t.update(tenantID, userID, e, now)
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { t.disconnect(tenantID, userID, db) })
+	}
+}
+
+func (t *Tracker) disconnect(tenantID, userID uuid.UUID, db *gorm.DB) {
+	now := time.Now()
+	t.mu.Lock()
+	e := t.tenants[tenantID][userID]
+	if e == nil {
+		t.mu.Unlock()
+		return
+	}
+	e.connections--
+	if e.connections > 0 {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.tenants[tenantID], userID)
+	if len(t.tenants[tenantID]) == 0 {
+		delete(t.tenants, tenantID)
+	}
+	recipients := t.recipients(tenantID)
+	t.mu.Unlock()
+
+	if db != nil {
+		if err := db.Model(&userModels.User{}).Where("id = ?", userID).Update("last_seen_at", now).Error; err != nil {
+			log.Printf("❌ Cannot update last_seen_at for %s: %v", userID, err)
+		}
+	}
+	t.notify(tenantID, recipients, Change{UserID: userID, Status: StatusOffline, LastSeenAt: &now})
+}
+
+// Touch відмічає активність користувача (будь-яке повідомлення з підключення)
+func (t *Tracker) Touch(tenantID, userID uuid.UUID) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e := t.tenants[tenantID][userID]; e != nil {
+		e.lastActive = now
+		e.away = false
+		t.update(tenantID, userID, e, now)
+	}
+}
+
+// SetAway — явний статус від клієнта (наприклад, вкладку сховано).
+// Повертає false, якщо в користувача немає живих підключень.
+func (t *Tracker) SetAway(tenantID, userID uuid.UUID, away bool) bool {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.tenants[tenantID][userID]
+	if e == nil {
+		return false
+	}
+	e.away = away
+	if !away {
+		e.lastActive = now
+	}
+	t.update(tenantID, userID, e, now)
+	return true
+}
+
+// Sweep переводить у "away" тих, хто довго нічого не робив
+func (t *Tracker) Sweep(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for tenantID, users := range t.tenants {
+		for userID, e := range users {
+			t.update(tenantID, userID, e, now)
+		}
+	}
+}
+
+func (t *Tracker) Status(tenantID, userID uuid.UUID) string {
+	return t.Statuses(tenantID, []uuid.UUID{userID})[userID]
+}
+
+// Statuses — масовий запит статусів; відсутні в трекері користувачі "offline"
+func (t *Tracker) Statuses(tenantID uuid.UUID, userIDs []uuid.UUID) map[uuid.UUID]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	statuses := make(map[uuid.UUID]string, len(userIDs))
+	for _, userID := range userIDs {
+		if e := t.tenants[tenantID][userID]; e != nil {
+			statuses[userID] = e.status
+		} else {
+			statuses[userID] = StatusOffline
+		}
+	}
+	return statuses
+}
+
+// update перераховує статус і розсилає подію, якщо він змінився. Викликається під t.mu;
+// розсилка неблокуюча (SSE-канали з буфером), тож тримати замок під час неї безпечно.
+func (t *Tracker) update(tenantID, userID uuid.UUID, e *entry, now time.Time) {
+	status := e.compute(now)
+	if status == e.status {
+		return
+	}
+	e.status = status
+	t.notify(tenantID, t.recipients(tenantID), Change{UserID: userID, Status: status})
+}
+
+// recipients — усі підключені користувачі тентанта
+func (t *Tracker) recipients(tenantID uuid.UUID) []uuid.UUID {
+	users := t.tenants[tenantID]
+	ids := make([]uuid.UUID, 0, len(users))
+	for id := range users {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (t *Tracker) notify(tenantID uuid.UUID, recipients []uuid.UUID, change Change) {
+	if t.onChange != nil && len(recipients) > 0 {
+		t.onChange(tenantID, recipients, change)
+	}
+}
+
+func broadcast(_ uuid.UUID, recipients []uuid.UUID, change Change) {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return
+	}
+	msg := sse.SSEMessage{Event: "presence", Data: string(data)}
+	for _, userID := range recipients {
+		sse.Manager.SendToUser(userID, msg)
+	}
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "property",
+		Version: 1,
+		Name:    "create_properties",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&propertyV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&propertyV1{})
+		},
+	},
+}
+
+// Знімок схеми на момент міграції: зміни моделі не змінюють уже застосовану міграцію
+type propertyV1 struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Height    string    `gorm:"default:null"`
+	Width     string    `gorm:"default:null"`
+	Weight    string    `gorm:"default:null"`
+	Color     string    `gorm:"default:null"`
+	Material  string    `gorm:"default:null"`
+	Brand     string    `gorm:"default:null"`
+	Size      string    `gorm:"default:null"`
+	Motif     string    `gorm:"default:null"`
+	Style     string    `gorm:"default:null"`
+	ContentId uuid.UUID `gorm:"type:uuid;"`
+}
+
+func (propertyV1) TableName() string {
+	return "properties"
+}
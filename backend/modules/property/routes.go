@@ -1,17 +1,19 @@
 package property
 
 import (
+	"backend/internal/middleware"
 	"backend/modules/property/handlers"
+	roleModels "backend/modules/role/models"
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterRoutes(r *gin.RouterGroup) {
 	propertyGroup := r.Group("/properties")
 	{
-		propertyGroup.POST("/", handlers.CreatePropertiesHandler)
-		propertyGroup.GET("/:id", handlers.GetPropertyByIDHandler)
-		propertyGroup.PATCH("/:id", handlers.UpdatePropertyHandler)
+		propertyGroup.POST("/", middleware.RequirePermission(roleModels.PermPropertiesWrite), handlers.CreatePropertiesHandler)
+		propertyGroup.GET("/:id", middleware.RequirePermission(roleModels.PermItemsRead), handlers.GetPropertyByIDHandler)
+		propertyGroup.PATCH("/:id", middleware.RequirePermission(roleModels.PermPropertiesWrite), handlers.UpdatePropertyHandler)
 		//propertyGroup.GET("/", handlers.GetAllPropertiesHandler)
-		propertyGroup.DELETE("/:id", handlers.DeletePropertyHandler)
+		propertyGroup.DELETE("/:id", middleware.RequirePermission(roleModels.PermPropertiesWrite), handlers.DeletePropertyHandler)
 	}
 }
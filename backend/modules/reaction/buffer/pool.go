@@ -1,6 +1,7 @@
 package buffer
 
 import (
+	"backend/internal/db/postgres"
 	"backend/internal/services/bufferedwriter"
 	reactionDTO "backend/modules/reaction/models"
 	"errors"
@@ -23,7 +24,9 @@ var (
 	flushImmediately  = true
 )
 
-func GetOrCreateWriter(tenantID uuid.UUID, db *gorm.DB) *bufferedwriter.Writer[BufferedReactionPayload] {
+// GetOrCreateWriter повертає буфер тентанта. Підключення береться заново
+// на кожне скидання, бо пул тентанта може бути витіснений, поки буфер живе.
+func GetOrCreateWriter(tenantID uuid.UUID, domain string) *bufferedwriter.Writer[BufferedReactionPayload] {
 	reactionPoolMutex.Lock()
 	defer reactionPoolMutex.Unlock()
 
@@ -32,7 +35,9 @@ func GetOrCreateWriter(tenantID uuid.UUID, db *gorm.DB) *bufferedwriter.Writer[B
 	}
 
 	writer := bufferedwriter.NewWriter[BufferedReactionPayload](
-		db,
+		func() (*gorm.DB, error) {
+			return postgres.Manager.GetConnectionByDomain(domain)
+		},
 		5*time.Second,
 		50,
 		FlushBufferedReactions,
@@ -48,8 +53,8 @@ func GetOrCreateWriter(tenantID uuid.UUID, db *gorm.DB) *bufferedwriter.Writer[B
 	return writer
 }
 
-func AddReactionBuffered(tenantID uuid.UUID, db *gorm.DB, payload BufferedReactionPayload) {
-	writer := GetOrCreateWriter(tenantID, db)
+func AddReactionBuffered(tenantID uuid.UUID, domain string, payload BufferedReactionPayload) {
+	writer := GetOrCreateWriter(tenantID, domain)
 	writer.Add(payload)
 	if flushImmediately {
 		go writer.Flush()
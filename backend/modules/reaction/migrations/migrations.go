@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "reaction",
+		Version: 1,
+		Name:    "create_reactions",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&reactionV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&reactionV1{})
+		},
+	},
+}
+
+// Знімок схеми на момент міграції: зміни моделі не змінюють уже застосовану міграцію
+type reactionV1 struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserId    uuid.UUID `gorm:"type:uuid;not null"`
+	MessageID uuid.UUID `gorm:"type:uuid;not null"`
+	Emoji     string    `gorm:"type:text;not null"`
+	CreatedAt time.Time
+}
+
+func (reactionV1) TableName() string {
+	return "reactions"
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	utils2 "backend/internal/services/utils"
+	"backend/modules/role/models"
+	"backend/modules/role/repository"
+	"backend/modules/role/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+)
+
+func respondRoleError(ctx *gin.Context, err error) {
+	switch {
+	case err.Error() == "role not found" || err.Error() == "user not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case err.Error() == "system roles cannot be changed" || err.Error() == "system roles cannot be deleted":
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case err.Error() == "role already exists" || err.Error() == "at least one active user must keep full access":
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err.Error() == "role name is required" || strings.HasPrefix(err.Error(), "unknown permission"):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func ListPermissionsHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, models.Catalogue)
+}
+
+func ListRolesHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	roles, err := repository.GetAllRoles(db)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, roles)
+}
+
+func CreateRoleHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	var req models.CreateRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	role, err := service.CreateRole(db, &req)
+	if err != nil {
+		respondRoleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, role)
+}
+
+func UpdateRoleHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	var req models.UpdateRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	role, err := service.UpdateRole(db, id, &req)
+	if err != nil {
+		respondRoleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, role)
+}
+
+func DeleteRoleHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := service.DeleteRole(db, id); err != nil {
+		respondRoleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Role deleted"})
+}
+
+func GetUserRolesHandler(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	response, err := service.GetUserRoles(db, userID)
+	if err != nil {
+		respondRoleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+func SetUserRolesHandler(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	var req models.SetUserRolesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	response, err := service.SetUserRoles(db, userID, req.RoleIDs)
+	if err != nil {
+		respondRoleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetMyPermissionsHandler — дозволи поточного користувача, щоб фронтенд
+// міг ховати недоступні дії
+func GetMyPermissionsHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	permissions, err := utils2.GetPermissionsFromContext(ctx, db)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"permissions": permissions})
+}
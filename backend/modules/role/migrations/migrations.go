@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "role",
+		Version: 1,
+		Name:    "create_roles",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&roleV1{}, &userRoleV1{}); err != nil {
+				return err
+			}
+			for _, role := range systemRolesV1() {
+				role := role
+				if err := tx.Where("name = ?", role.Name).FirstOrCreate(&role).Error; err != nil {
+					return err
+				}
+			}
+			// Переносимо наявні прапорці IsSuperUser/IsAdmin у системні ролі
+			return tx.Exec(`
+				INSERT INTO user_roles (user_id, role_id, created_at)
+				SELECT u.id, r.id, NOW()
+				FROM users u
+				JOIN roles r ON r.name = CASE
+					WHEN u.is_super_user THEN ?
+					WHEN u.is_admin THEN ?
+					ELSE ?
+				END
+				ON CONFLICT DO NOTHING`,
+				"admin", "manager", "member").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&userRoleV1{}, &roleV1{})
+		},
+	},
+}
+
+// Знімки схеми й початкових даних на момент міграції: зміни моделей
+// і набору дозволів не змінюють уже застосовану міграцію
+
+type roleV1 struct {
+	ID          uuid.UUID                   `gorm:"type:uuid;primaryKey"`
+	Name        string                      `gorm:"type:varchar(100);uniqueIndex;not null"`
+	Description string                      `gorm:"default:null"`
+	Permissions datatypes.JSONSlice[string] `gorm:"type:jsonb;not null"`
+	IsSystem    bool                        `gorm:"default:false"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (roleV1) TableName() string {
+	return "roles"
+}
+
+func (r *roleV1) BeforeCreate(*gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+type userRoleV1 struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RoleID    uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	CreatedAt time.Time
+}
+
+func (userRoleV1) TableName() string {
+	return "user_roles"
+}
+
+func systemRolesV1() []roleV1 {
+	member := []string{
+		"users.read", "employees.read", "items.read", "items.write",
+		"blog.read", "blog.write", "media.upload", "calendar.write",
+	}
+	manager := append(append([]string{}, member...),
+		"employees.write", "employees.salary.read", "items.read.all", "items.manage",
+		"blog.publish", "blog.read.all", "blog.manage", "properties.write",
+		"media.delete", "chat.rooms.manage",
+	)
+	return []roleV1{
+		{Name: "admin", Description: "Full access", Permissions: []string{"*"}, IsSystem: true},
+		{Name: "manager", Description: "Manages content and employees", Permissions: manager, IsSystem: true},
+		{Name: "member", Description: "Default role for new users", Permissions: member, IsSystem: true},
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import "github.com/google/uuid"
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type UpdateRoleRequest struct {
+	Name        *string  `json:"name"`
+	Description *string  `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type SetUserRolesRequest struct {
+	RoleIDs []uuid.UUID `json:"roleIds"`
+}
+
+type UserRolesResponse struct {
+	UserID      uuid.UUID `json:"userId"`
+	Roles       []Role    `json:"roles"`
+	Permissions []string  `json:"permissions"`
+}
@@ -0,0 +1,146 @@
+package models
+
+// Дозволи у форматі "<модуль>.<дія>". Роль із "*" має всі дозволи.
+const (
+	PermAll = "*"
+
+	PermUsersRead   = "users.read"
+	PermUsersManage = "users.manage"
+	PermRolesManage = "roles.manage"
+
+	PermSettingsManage = "settings.manage"
+
+	PermEmployeesRead       = "employees.read"
+	PermEmployeesWrite      = "employees.write"
+	PermEmployeesSalaryRead = "employees.salary.read"
+
+	PermItemsRead    = "items.read"
+	PermItemsWrite   = "items.write"
+	PermItemsReadAll = "items.read.all"
+	PermItemsManage  = "items.manage"
+
+	PermBlogRead    = "blog.read"
+	PermBlogWrite   = "blog.write"
+	PermBlogPublish = "blog.publish"
+	PermBlogReadAll = "blog.read.all"
+	PermBlogManage  = "blog.manage"
+
+	PermPropertiesWrite = "properties.write"
+
+	PermMediaUpload = "media.upload"
+	PermMediaDelete = "media.delete"
+
+	PermCalendarWrite = "calendar.write"
+
+	PermChatRoomsManage = "chat.rooms.manage"
+
+	PermAuditRead = "audit.read"
+)
+
+// Permission — опис дозволу для UI редагування ролей
+type Permission struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+}
+
+var Catalogue = []Permission{
+	{PermUsersRead, "View users"},
+	{PermUsersManage, "Create and delete users"},
+	{PermRolesManage, "Manage roles and assign them to users"},
+	{PermSettingsManage, "Change tenant settings and view usage"},
+	{PermEmployeesRead, "View employee profiles"},
+	{PermEmployeesWrite, "Edit employee profiles"},
+	{PermEmployeesSalaryRead, "View employee salaries"},
+	{PermItemsRead, "View own items, their properties and item lookups"},
+	{PermItemsWrite, "Create and edit own items"},
+	{PermItemsReadAll, "View items of all users"},
+	{PermItemsManage, "Edit and delete items of all users"},
+	{PermBlogRead, "View own posts"},
+	{PermBlogWrite, "Create and edit own posts"},
+	{PermBlogPublish, "Publish posts"},
+	{PermBlogReadAll, "View posts of all users"},
+	{PermBlogManage, "Edit and delete posts of all users"},
+	{PermPropertiesWrite, "Create, edit and delete item properties"},
+	{PermMediaUpload, "Upload media"},
+	{PermMediaDelete, "Delete media"},
+	{PermCalendarWrite, "Manage calendar events"},
+	{PermChatRoomsManage, "Create and delete chat rooms of other users"},
+	{PermAuditRead, "View and export the audit log"},
+}
+
+func IsKnownPermission(key string) bool {
+	if key == PermAll {
+		return true
+	}
+	for _, p := range Catalogue {
+		if p.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Системні ролі створюються міграцією і не можуть бути видалені.
+// admin відповідає колишньому IsSuperUser, manager — IsAdmin.
+const (
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleMember  = "member"
+)
+
+var memberPermissions = []string{
+	PermUsersRead,
+	PermEmployeesRead,
+	PermItemsRead,
+	PermItemsWrite,
+	PermBlogRead,
+	PermBlogWrite,
+	PermMediaUpload,
+	PermCalendarWrite,
+}
+
+var managerPermissions = append(append([]string{}, memberPermissions...),
+	PermEmployeesWrite,
+	PermEmployeesSalaryRead,
+	PermItemsReadAll,
+	PermItemsManage,
+	PermBlogPublish,
+	PermBlogReadAll,
+	PermBlogManage,
+	PermPropertiesWrite,
+	PermMediaDelete,
+	PermChatRoomsManage,
+)
+
+func SystemRoles() []Role {
+	return []Role{
+		{Name: RoleAdmin, Description: "Full access", Permissions: []string{PermAll}, IsSystem: true},
+		{Name: RoleManager, Description: "Manages content and employees", Permissions: managerPermissions, IsSystem: true},
+		{Name: RoleMember, Description: "Default role for new users", Permissions: memberPermissions, IsSystem: true},
+	}
+}
+
+// HasPermission перевіряє набір дозволів з урахуванням "*"
+func HasPermission(granted []string, perm string) bool {
+	for _, g := range granted {
+		if g == PermAll || g == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictPermissions звужує дозволи до scopes (наприклад, API-ключа):
+// залишаються лише ті, що є і серед granted, і серед scopes
+func RestrictPermissions(granted, scopes []string) []string {
+	if HasPermission(scopes, PermAll) {
+		return granted
+	}
+	restricted := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if HasPermission(granted, scope) {
+			restricted = append(restricted, scope)
+		}
+	}
+	return restricted
+}
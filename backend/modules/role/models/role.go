@@ -0,0 +1,32 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+type Role struct {
+	ID          uuid.UUID                   `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string                      `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"`
+	Description string                      `gorm:"default:null" json:"description"`
+	Permissions datatypes.JSONSlice[string] `gorm:"type:jsonb;not null" json:"permissions"`
+	IsSystem    bool                        `gorm:"default:false" json:"isSystem"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (r *Role) BeforeCreate(*gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+type UserRole struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"userId"`
+	RoleID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"roleId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"backend/modules/role/models"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func GetAllRoles(db *gorm.DB) ([]models.Role, error) {
+	var roles []models.Role
+	if err := db.Order("is_system DESC, name ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func GetRoleByID(db *gorm.DB, id uuid.UUID) (*models.Role, error) {
+	var role models.Role
+	if err := db.Where("id = ?", id).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("role not found")
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func GetRoleByName(db *gorm.DB, name string) (*models.Role, error) {
+	var role models.Role
+	if err := db.Where("name = ?", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("role not found")
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func CreateRole(db *gorm.DB, role *models.Role) error {
+	return db.Create(role).Error
+}
+
+func SaveRole(db *gorm.DB, role *models.Role) error {
+	return db.Save(role).Error
+}
+
+func DeleteRole(db *gorm.DB, id uuid.UUID) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.Role{}).Error
+	})
+}
+
+func GetUserRoles(db *gorm.DB, userID uuid.UUID) ([]models.Role, error) {
+	var roles []models.Role
+	err := db.Joins("JOIN user_roles ur ON ur.role_id = roles.id").
+		Where("ur.user_id = ?", userID).
+		Order("roles.name ASC").
+		Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GetUserPermissions — об'єднання дозволів усіх ролей користувача
+func GetUserPermissions(db *gorm.DB, userID uuid.UUID) ([]string, error) {
+	roles, err := GetUserRoles(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	permissions := make([]string, 0)
+	for _, role := range roles {
+		for _, p := range role.Permissions {
+			if !seen[p] {
+				seen[p] = true
+				permissions = append(permissions, p)
+			}
+		}
+	}
+	return permissions, nil
+}
+
+// SetUserRoles повністю замінює ролі користувача
+func SetUserRoles(tx *gorm.DB, userID uuid.UUID, roleIDs []uuid.UUID) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.UserRole{}).Error; err != nil {
+		return err
+	}
+	for _, roleID := range roleIDs {
+		link := models.UserRole{UserID: userID, RoleID: roleID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+			return err
+		}
+	}
+	return SyncLegacyFlags(tx, userID)
+}
+
+// AssignFlagRoles переводить прапорці IsSuperUser/IsAdmin у системні ролі,
+// не чіпаючи призначених користувачу власних ролей тентанта
+func AssignFlagRoles(tx *gorm.DB, userID uuid.UUID, isSuperUser, isAdmin bool) error {
+	name := models.RoleMember
+	switch {
+	case isSuperUser:
+		name = models.RoleAdmin
+	case isAdmin:
+		name = models.RoleManager
+	}
+	role, err := GetRoleByName(tx, name)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Where("user_id = ? AND role_id IN (SELECT id FROM roles WHERE is_system)", userID).
+		Delete(&models.UserRole{}).Error
+	if err != nil {
+		return err
+	}
+	link := models.UserRole{UserID: userID, RoleID: role.ID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&link).Error; err != nil {
+		return err
+	}
+	return SyncLegacyFlags(tx, userID)
+}
+
+// SyncLegacyFlags тримає колонки is_super_user/is_admin у відповідності до ролей,
+// бо фронтенд і старі клієнти досі їх читають. Для авторизації вони не використовуються.
+func SyncLegacyFlags(tx *gorm.DB, userID uuid.UUID) error {
+	permissions, err := GetUserPermissions(tx, userID)
+	if err != nil {
+		return err
+	}
+	roles, err := GetUserRoles(tx, userID)
+	if err != nil {
+		return err
+	}
+	isSuperUser := models.HasPermission(permissions, models.PermAll)
+	isAdmin := isSuperUser
+	for _, role := range roles {
+		if role.Name == models.RoleManager {
+			isAdmin = true
+		}
+	}
+	return tx.Table("users").Where("id = ?", userID).Updates(map[string]interface{}{
+		"is_super_user": isSuperUser,
+		"is_admin":      isAdmin,
+	}).Error
+}
+
+// CountFullAccessUsers — кількість активних користувачів (не сервісних акаунтів) з роллю, що має "*"
+func CountFullAccessUsers(db *gorm.DB) (int64, error) {
+	var count int64
+	err := db.Table("user_roles ur").
+		Joins("JOIN roles r ON r.id = ur.role_id").
+		Joins("JOIN users u ON u.id = ur.user_id").
+		Where("u.is_active AND NOT u.is_service_account AND r.permissions @> ?", `["*"]`).
+		Distinct("ur.user_id").
+		Count(&count).Error
+	return count, err
+}
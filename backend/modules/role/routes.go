@@ -0,0 +1,23 @@
+package role
+
+import (
+	"backend/internal/middleware"
+	"backend/modules/role/handlers"
+	"backend/modules/role/models"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/permissions/me", handlers.GetMyPermissionsHandler)
+
+	roleGroup := r.Group("/roles", middleware.RequirePermission(models.PermRolesManage))
+	{
+		roleGroup.GET("/", handlers.ListRolesHandler)
+		roleGroup.GET("/permissions", handlers.ListPermissionsHandler)
+		roleGroup.POST("/", handlers.CreateRoleHandler)
+		roleGroup.PATCH("/:id", handlers.UpdateRoleHandler)
+		roleGroup.DELETE("/:id", handlers.DeleteRoleHandler)
+		roleGroup.GET("/users/:userId", handlers.GetUserRolesHandler)
+		roleGroup.PUT("/users/:userId", handlers.SetUserRolesHandler)
+	}
+}
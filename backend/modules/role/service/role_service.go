@@ -0,0 +1,154 @@
+package service
+
+import (
+	"backend/modules/role/models"
+	"backend/modules/role/repository"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strings"
+)
+
+func validatePermissions(permissions []string) error {
+	for _, p := range permissions {
+		if !models.IsKnownPermission(p) {
+			return fmt.Errorf("unknown permission: %s", p)
+		}
+	}
+	return nil
+}
+
+// ensureFullAccessLeft не дає залишити тентант без жодного користувача з повним доступом
+func ensureFullAccessLeft(tx *gorm.DB) error {
+	count, err := repository.CountFullAccessUsers(tx)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("at least one active user must keep full access")
+	}
+	return nil
+}
+
+func CreateRole(db *gorm.DB, req *models.CreateRoleRequest) (*models.Role, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("role name is required")
+	}
+	if err := validatePermissions(req.Permissions); err != nil {
+		return nil, err
+	}
+	if _, err := repository.GetRoleByName(db, name); err == nil {
+		return nil, errors.New("role already exists")
+	}
+
+	role := &models.Role{
+		Name:        name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+	if err := repository.CreateRole(db, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func UpdateRole(db *gorm.DB, id uuid.UUID, req *models.UpdateRoleRequest) (*models.Role, error) {
+	var role *models.Role
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		role, err = repository.GetRoleByID(tx, id)
+		if err != nil {
+			return err
+		}
+		if role.IsSystem {
+			return errors.New("system roles cannot be changed")
+		}
+
+		if req.Name != nil {
+			name := strings.TrimSpace(*req.Name)
+			if name == "" {
+				return errors.New("role name is required")
+			}
+			if existing, err := repository.GetRoleByName(tx, name); err == nil && existing.ID != role.ID {
+				return errors.New("role already exists")
+			}
+			role.Name = name
+		}
+		if req.Description != nil {
+			role.Description = *req.Description
+		}
+		if req.Permissions != nil {
+			if err := validatePermissions(req.Permissions); err != nil {
+				return err
+			}
+			role.Permissions = req.Permissions
+		}
+
+		if err := repository.SaveRole(tx, role); err != nil {
+			return err
+		}
+		return ensureFullAccessLeft(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func DeleteRole(db *gorm.DB, id uuid.UUID) error {
+	role, err := repository.GetRoleByID(db, id)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return errors.New("system roles cannot be deleted")
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := repository.DeleteRole(tx, id); err != nil {
+			return err
+		}
+		return ensureFullAccessLeft(tx)
+	})
+}
+
+func GetUserRoles(db *gorm.DB, userID uuid.UUID) (*models.UserRolesResponse, error) {
+	roles, err := repository.GetUserRoles(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := repository.GetUserPermissions(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UserRolesResponse{UserID: userID, Roles: roles, Permissions: permissions}, nil
+}
+
+func SetUserRoles(db *gorm.DB, userID uuid.UUID, roleIDs []uuid.UUID) (*models.UserRolesResponse, error) {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var exists int64
+		if err := tx.Table("users").Where("id = ?", userID).Count(&exists).Error; err != nil {
+			return err
+		}
+		if exists == 0 {
+			return errors.New("user not found")
+		}
+		for _, roleID := range roleIDs {
+			if _, err := repository.GetRoleByID(tx, roleID); err != nil {
+				return err
+			}
+		}
+		if err := repository.SetUserRoles(tx, userID, roleIDs); err != nil {
+			return err
+		}
+		return ensureFullAccessLeft(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return GetUserRoles(db, userID)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"backend/internal/entities"
+	"backend/internal/services/metering"
+	utils2 "backend/internal/services/utils"
+	roleModels "backend/modules/role/models"
+	"backend/modules/settings/models"
+	"backend/modules/settings/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+func currentTenant(ctx *gin.Context) (*entities.Tenant, bool) {
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant not found in context"})
+		return nil, false
+	}
+	return tenant, true
+}
+
+func GetSettingsHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	settings := utils2.GetTenantSettingsFromContext(ctx)
+	if !utils2.HasPermission(ctx, db, roleModels.PermSettingsManage) {
+		ctx.JSON(http.StatusOK, models.ToPublicSettings(&settings))
+		return
+	}
+	ctx.JSON(http.StatusOK, models.ToSettingsResponse(&settings))
+}
+
+func UpdateSettingsHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	if !utils2.HasPermission(ctx, db, roleModels.PermSettingsManage) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		return
+	}
+
+	tenant, ok := currentTenant(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	settings, err := service.UpdateSettings(tenant, &req)
+	if err != nil {
+		RespondSettingsError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, models.ToSettingsResponse(settings))
+}
+
+func RespondSettingsError(ctx *gin.Context, err error) {
+	if strings.HasPrefix(err.Error(), "login policy") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	switch err.Error() {
+	case "invalid timezone", "invalid language code", "default language must be one of available languages",
+		"invalid SMTP port", "SMTP sender address is required", "invalid primary color",
+		"invalid OIDC issuer", "invalid OIDC domain", "OIDC issuer, client ID and secret are required",
+		"password login can only be disabled when SSO is enabled":
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetUsageHandler — використання ресурсів і ліміти плану для адміністратора тентанта
+func GetUsageHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	if !utils2.HasPermission(ctx, db, roleModels.PermSettingsManage) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
+		return
+	}
+
+	tenant, ok := currentTenant(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := metering.Report(tenant.ID, db, 12)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"backend/internal/entities"
+	"github.com/google/uuid"
+	"time"
+)
+
+type SettingsResponse struct {
+	TenantID              uuid.UUID            `json:"tenant_id"`
+	Timezone              string               `json:"timezone"`
+	DefaultLanguage       string               `json:"default_language"`
+	AvailableLanguages    []string             `json:"available_languages"`
+	SMTPHost              string               `json:"smtp_host"`
+	SMTPPort              int                  `json:"smtp_port"`
+	SMTPUser              string               `json:"smtp_user"`
+	SMTPPasswordSet       bool                 `json:"smtp_password_set"`
+	SMTPFrom              string               `json:"smtp_from"`
+	SMTPFromName          string               `json:"smtp_from_name"`
+	BrandName             string               `json:"brand_name"`
+	LogoURL               string               `json:"logo_url"`
+	PrimaryColor          string               `json:"primary_color"`
+	Features              map[string]bool      `json:"features"`
+	Require2FA            bool                 `json:"require_2fa"`
+	OIDCEnabled           bool                 `json:"oidc_enabled"`
+	OIDCIssuer            string               `json:"oidc_issuer"`
+	OIDCClientID          string               `json:"oidc_client_id"`
+	OIDCClientSecretSet   bool                 `json:"oidc_client_secret_set"`
+	OIDCAutoProvision     bool                 `json:"oidc_auto_provision"`
+	OIDCAllowedDomains    []string             `json:"oidc_allowed_domains"`
+	PasswordLoginDisabled bool                 `json:"password_login_disabled"`
+	LoginPolicy           entities.LoginPolicy `json:"login_policy"`
+	UpdatedAt             time.Time            `json:"updated_at"`
+}
+
+// PublicSettings — те, що можна показати будь-якому користувачу тентанта
+type PublicSettings struct {
+	Timezone           string          `json:"timezone"`
+	DefaultLanguage    string          `json:"default_language"`
+	AvailableLanguages []string        `json:"available_languages"`
+	BrandName          string          `json:"brand_name"`
+	LogoURL            string          `json:"logo_url"`
+	PrimaryColor       string          `json:"primary_color"`
+	Features           map[string]bool `json:"features"`
+}
+
+type UpdateSettingsRequest struct {
+	Timezone              *string               `json:"timezone"`
+	DefaultLanguage       *string               `json:"default_language"`
+	AvailableLanguages    *[]string             `json:"available_languages"`
+	SMTPHost              *string               `json:"smtp_host"`
+	SMTPPort              *int                  `json:"smtp_port"`
+	SMTPUser              *string               `json:"smtp_user"`
+	SMTPPassword          *string               `json:"smtp_password"`
+	SMTPFrom              *string               `json:"smtp_from" binding:"omitempty,email"`
+	SMTPFromName          *string               `json:"smtp_from_name"`
+	BrandName             *string               `json:"brand_name"`
+	LogoURL               *string               `json:"logo_url" binding:"omitempty,url"`
+	PrimaryColor          *string               `json:"primary_color"`
+	Features              *map[string]bool      `json:"features"`
+	Require2FA            *bool                 `json:"require_2fa"`
+	OIDCEnabled           *bool                 `json:"oidc_enabled"`
+	OIDCIssuer            *string               `json:"oidc_issuer"`
+	OIDCClientID          *string               `json:"oidc_client_id"`
+	OIDCClientSecret      *string               `json:"oidc_client_secret"`
+	OIDCAutoProvision     *bool                 `json:"oidc_auto_provision"`
+	OIDCAllowedDomains    *[]string             `json:"oidc_allowed_domains"`
+	PasswordLoginDisabled *bool                 `json:"password_login_disabled"`
+	LoginPolicy           *entities.LoginPolicy `json:"login_policy"`
+}
+
+func ToSettingsResponse(settings *entities.TenantSettings) SettingsResponse {
+	return SettingsResponse{
+		TenantID:              settings.TenantID,
+		Timezone:              settings.Timezone,
+		DefaultLanguage:       settings.DefaultLanguage,
+		AvailableLanguages:    settings.AvailableLanguages,
+		SMTPHost:              settings.SMTPHost,
+		SMTPPort:              settings.SMTPPort,
+		SMTPUser:              settings.SMTPUser,
+		SMTPPasswordSet:       settings.SMTPPassword != "",
+		SMTPFrom:              settings.SMTPFrom,
+		SMTPFromName:          settings.SMTPFromName,
+		BrandName:             settings.BrandName,
+		LogoURL:               settings.LogoURL,
+		PrimaryColor:          settings.PrimaryColor,
+		Features:              settings.Features.Data(),
+		Require2FA:            settings.Require2FA,
+		OIDCEnabled:           settings.OIDCEnabled,
+		OIDCIssuer:            settings.OIDCIssuer,
+		OIDCClientID:          settings.OIDCClientID,
+		OIDCClientSecretSet:   settings.OIDCClientSecret != "",
+		OIDCAutoProvision:     settings.OIDCAutoProvision,
+		OIDCAllowedDomains:    settings.OIDCAllowedDomains,
+		PasswordLoginDisabled: settings.PasswordLoginDisabled,
+		LoginPolicy:           settings.EffectiveLoginPolicy(),
+		UpdatedAt:             settings.UpdatedAt,
+	}
+}
+
+func ToPublicSettings(settings *entities.TenantSettings) PublicSettings {
+	return PublicSettings{
+		Timezone:           settings.Timezone,
+		DefaultLanguage:    settings.DefaultLanguage,
+		AvailableLanguages: settings.AvailableLanguages,
+		BrandName:          settings.BrandName,
+		LogoURL:            settings.LogoURL,
+		PrimaryColor:       settings.PrimaryColor,
+		Features:           settings.Features.Data(),
+	}
+}
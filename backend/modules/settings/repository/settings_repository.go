@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"github.com/google/uuid"
+)
+
+func GetSettings(tenantID uuid.UUID) (*entities.TenantSettings, error) {
+	settings, err := postgres.LoadTenantSettings(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SaveSettings створює запис налаштувань або оновлює наявний
+func SaveSettings(settings *entities.TenantSettings) error {
+	db := postgres.GetDB()
+	if settings.ID == uuid.Nil {
+		return db.Create(settings).Error
+	}
+	return db.Save(settings).Error
+}
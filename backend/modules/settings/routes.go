@@ -0,0 +1,18 @@
+package settings
+
+import (
+	"backend/internal/middleware"
+	roleModels "backend/modules/role/models"
+	"backend/modules/settings/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(r *gin.RouterGroup) {
+
+	settingsGroup := r.Group("/settings")
+	{
+		settingsGroup.GET("/", handlers.GetSettingsHandler)
+		settingsGroup.PATCH("/", middleware.RequirePermission(roleModels.PermSettingsManage), handlers.UpdateSettingsHandler)
+		settingsGroup.GET("/usage", middleware.RequirePermission(roleModels.PermSettingsManage), handlers.GetUsageHandler)
+	}
+}
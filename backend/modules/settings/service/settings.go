@@ -0,0 +1,185 @@
+package service
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"backend/internal/services/oidc"
+	"backend/internal/services/utils"
+	"backend/modules/settings/models"
+	"backend/modules/settings/repository"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	languagePattern = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+	colorPattern    = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+	domainPattern   = regexp.MustCompile(`^([a-z0-9-]+\.)+[a-z]{2,}$`)
+)
+
+func GetSettings(tenantID uuid.UUID) (*entities.TenantSettings, error) {
+	return repository.GetSettings(tenantID)
+}
+
+// UpdateSettings застосовує часткове оновлення і скидає кеш тентанта.
+// Зміна часового поясу перевідкриває пул, бо TimeZone задається в DSN.
+func UpdateSettings(tenant *entities.Tenant, req *models.UpdateSettingsRequest) (*entities.TenantSettings, error) {
+	settings, err := repository.GetSettings(tenant.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" {
+			return nil, errors.New("invalid timezone")
+		}
+		settings.Timezone = *req.Timezone
+	}
+	if req.AvailableLanguages != nil {
+		for _, lang := range *req.AvailableLanguages {
+			if !languagePattern.MatchString(lang) {
+				return nil, errors.New("invalid language code")
+			}
+		}
+		settings.AvailableLanguages = datatypes.JSONSlice[string](*req.AvailableLanguages)
+	}
+	if req.DefaultLanguage != nil {
+		if !languagePattern.MatchString(*req.DefaultLanguage) {
+			return nil, errors.New("invalid language code")
+		}
+		settings.DefaultLanguage = *req.DefaultLanguage
+	}
+	if len(settings.AvailableLanguages) > 0 && settings.Language(settings.DefaultLanguage) != settings.DefaultLanguage {
+		return nil, errors.New("default language must be one of available languages")
+	}
+
+	if req.SMTPHost != nil {
+		settings.SMTPHost = strings.TrimSpace(*req.SMTPHost)
+	}
+	if req.SMTPPort != nil {
+		if *req.SMTPPort < 0 || *req.SMTPPort > 65535 {
+			return nil, errors.New("invalid SMTP port")
+		}
+		settings.SMTPPort = *req.SMTPPort
+	}
+	if req.SMTPUser != nil {
+		settings.SMTPUser = *req.SMTPUser
+	}
+	if req.SMTPPassword != nil {
+		settings.SMTPPassword = ""
+		if *req.SMTPPassword != "" {
+			encrypted, err := utils.Encrypt(*req.SMTPPassword)
+			if err != nil {
+				return nil, err
+			}
+			settings.SMTPPassword = encrypted
+		}
+	}
+	if req.SMTPFrom != nil {
+		settings.SMTPFrom = *req.SMTPFrom
+	}
+	if req.SMTPFromName != nil {
+		settings.SMTPFromName = *req.SMTPFromName
+	}
+	if settings.SMTPHost != "" && settings.SMTPFrom == "" {
+		return nil, errors.New("SMTP sender address is required")
+	}
+
+	if req.BrandName != nil {
+		settings.BrandName = *req.BrandName
+	}
+	if req.LogoURL != nil {
+		settings.LogoURL = *req.LogoURL
+	}
+	if req.PrimaryColor != nil {
+		if *req.PrimaryColor != "" && !colorPattern.MatchString(*req.PrimaryColor) {
+			return nil, errors.New("invalid primary color")
+		}
+		settings.PrimaryColor = *req.PrimaryColor
+	}
+	if req.Features != nil {
+		settings.Features = datatypes.NewJSONType(*req.Features)
+	}
+	if req.Require2FA != nil {
+		settings.Require2FA = *req.Require2FA
+	}
+	if err := applySSOSettings(settings, req); err != nil {
+		return nil, err
+	}
+	if req.LoginPolicy != nil {
+		if err := req.LoginPolicy.Validate(); err != nil {
+			return nil, err
+		}
+		settings.LoginPolicy = datatypes.NewJSONType(*req.LoginPolicy)
+	}
+
+	if err := repository.SaveSettings(settings); err != nil {
+		return nil, err
+	}
+
+	// Пул з'єднань не чіпаємо: час переводиться в код через settings.Location(),
+	// а сесії БД отримають новий пояс, коли пул відкриється наступного разу
+	postgres.Manager.InvalidateTenant(tenant.Domain)
+	return settings, nil
+}
+
+// applySSOSettings застосовує налаштування OIDC. Issuer має бути публічною
+// https-адресою (oidc.ValidateURL); для локального IdP є OIDC_ALLOW_PRIVATE_NETWORK.
+func applySSOSettings(settings *entities.TenantSettings, req *models.UpdateSettingsRequest) error {
+	if req.OIDCIssuer != nil {
+		issuer := strings.TrimSuffix(strings.TrimSpace(*req.OIDCIssuer), "/")
+		if issuer != "" {
+			parsed, err := url.Parse(issuer)
+			if err != nil || parsed.RawQuery != "" || parsed.Fragment != "" || oidc.ValidateURL(issuer) != nil {
+				return errors.New("invalid OIDC issuer")
+			}
+		}
+		settings.OIDCIssuer = issuer
+	}
+	if req.OIDCClientID != nil {
+		settings.OIDCClientID = strings.TrimSpace(*req.OIDCClientID)
+	}
+	if req.OIDCClientSecret != nil {
+		settings.OIDCClientSecret = ""
+		if *req.OIDCClientSecret != "" {
+			encrypted, err := utils.Encrypt(*req.OIDCClientSecret)
+			if err != nil {
+				return err
+			}
+			settings.OIDCClientSecret = encrypted
+		}
+	}
+	if req.OIDCAutoProvision != nil {
+		settings.OIDCAutoProvision = *req.OIDCAutoProvision
+	}
+	if req.OIDCAllowedDomains != nil {
+		domains := make([]string, 0, len(*req.OIDCAllowedDomains))
+		for _, domain := range *req.OIDCAllowedDomains {
+			domain = strings.ToLower(strings.TrimSpace(domain))
+			if !domainPattern.MatchString(domain) {
+				return errors.New("invalid OIDC domain")
+			}
+			domains = append(domains, domain)
+		}
+		settings.OIDCAllowedDomains = datatypes.JSONSlice[string](domains)
+	}
+	if req.OIDCEnabled != nil {
+		settings.OIDCEnabled = *req.OIDCEnabled
+	}
+	if req.PasswordLoginDisabled != nil {
+		settings.PasswordLoginDisabled = *req.PasswordLoginDisabled
+	}
+
+	if settings.OIDCEnabled && (settings.OIDCIssuer == "" || settings.OIDCClientID == "" || settings.OIDCClientSecret == "") {
+		return errors.New("OIDC issuer, client ID and secret are required")
+	}
+	if settings.PasswordLoginDisabled && !settings.SSOConfigured() {
+		return errors.New("password login can only be disabled when SSO is enabled")
+	}
+	return nil
+}
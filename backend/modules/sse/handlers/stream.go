@@ -2,7 +2,10 @@ package handlers
 
 import (
 	utils2 "backend/internal/services/utils"
+	presence "backend/modules/presence/service"
 	"backend/modules/sse"
+	userService "backend/modules/user/service"
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"log"
@@ -11,17 +14,26 @@ import (
 )
 
 func SSEStreamHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
 
 	token := ctx.Query("token")
-	user, err := utils2.ParseJWTToken(token)
+	user, err := userService.AuthenticateStream(db, token)
 	if err != nil {
 		log.Println("❌ Невалідний токен:", err)
 		ctx.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
 
+	// Відкликана сесія завершує потік
+	streamCtx, cancel := context.WithCancel(ctx.Request.Context())
+	defer cancel()
+	userService.WatchSession(streamCtx, db, user, cancel)
+
 	writer := ctx.Writer
-	request := ctx.Request
 
 	writer.Header().Set("Content-Type", "text/event-stream")
 	writer.Header().Set("Cache-Control", "no-cache")
@@ -36,7 +48,12 @@ func SSEStreamHandler(ctx *gin.Context) {
 	userID := user.ID
 	clientChan := make(chan sse.SSEMessage, 10)
 	sse.Manager.AddClient(userID, clientChan)
-	defer sse.Manager.RemoveClient(userID)
+	defer sse.Manager.RemoveClient(userID, clientChan)
+
+	// SSE-потік тримає користувача "онлайн", поки вкладка відкрита
+	if tenant, ok := utils2.GetTenantFromContext(ctx); ok {
+		defer presence.Manager.Connect(tenant.ID, userID, db)()
+	}
 
 	log.Println("✅ SSE підключено для користувача:", userID)
 
@@ -50,12 +67,15 @@ func SSEStreamHandler(ctx *gin.Context) {
 	// Основний цикл: слухаємо повідомлення
 	for {
 		select {
-		case msg := <-clientChan:
+		case msg, ok := <-clientChan:
+			if !ok {
+				return
+			}
 			fmt.Fprintf(writer, "event: %s\n", msg.Event)
 			fmt.Fprintf(writer, "data: %s\n\n", msg.Data)
 
 			flusher.Flush()
-		case <-request.Context().Done():
+		case <-streamCtx.Done():
 			return
 		}
 	}
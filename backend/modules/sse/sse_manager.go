@@ -11,29 +11,35 @@ type SSEMessage struct {
 	Data  string
 }
 
-// Менеджер для підключень
+// Менеджер для підключень; в одного користувача може бути кілька вкладок
 type SSEManager struct {
-	clients map[uuid.UUID]chan SSEMessage
+	clients map[uuid.UUID]map[chan SSEMessage]bool
 	mutex   sync.RWMutex
 }
 
 var Manager = &SSEManager{
-	clients: make(map[uuid.UUID]chan SSEMessage),
+	clients: make(map[uuid.UUID]map[chan SSEMessage]bool),
 }
 
 // Додати клієнта
 func (m *SSEManager) AddClient(userID uuid.UUID, ch chan SSEMessage) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	m.clients[userID] = ch
+	if m.clients[userID] == nil {
+		m.clients[userID] = make(map[chan SSEMessage]bool)
+	}
+	m.clients[userID][ch] = true
 }
 
-// Видалити клієнта
-func (m *SSEManager) RemoveClient(userID uuid.UUID) {
+// Видалити клієнта (лише це підключення, інші вкладки лишаються)
+func (m *SSEManager) RemoveClient(userID uuid.UUID, ch chan SSEMessage) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	if ch, ok := m.clients[userID]; ok {
+	if m.clients[userID][ch] {
 		close(ch)
+		delete(m.clients[userID], ch)
+	}
+	if len(m.clients[userID]) == 0 {
 		delete(m.clients, userID)
 	}
 }
@@ -42,7 +48,7 @@ func (m *SSEManager) RemoveClient(userID uuid.UUID) {
 func (m *SSEManager) SendToUser(userID uuid.UUID, msg SSEMessage) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
-	if ch, ok := m.clients[userID]; ok {
+	for ch := range m.clients[userID] {
 		select {
 		case ch <- msg:
 		default:
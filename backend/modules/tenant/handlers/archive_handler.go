@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"archive/zip"
+	"backend/modules/tenant/service"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"net/http"
+	"time"
+)
+
+func ExportTenantHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+	if _, err := service.GetTenant(id); err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+
+	filename := fmt.Sprintf("tenant-%s-%s.zip", id, time.Now().UTC().Format("20060102-150405"))
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	ctx.Status(http.StatusOK)
+
+	// Архів пишеться потоком, тому після початку відповіді помилку можна лише залогувати
+	if err := service.ExportTenant(id, ctx.Writer); err != nil {
+		log.Printf("❌ Tenant %s export failed: %v", id, err)
+		_ = ctx.Error(err)
+	}
+}
+
+func ImportTenantHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	header, err := ctx.FormFile("archive")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Archive file is required"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	archive, err := zip.NewReader(file, header.Size)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid archive: " + err.Error()})
+		return
+	}
+
+	report, err := service.ImportTenant(id, archive)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondTenantError(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
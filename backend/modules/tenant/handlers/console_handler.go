@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"backend/internal/services/utils"
+	"backend/modules/tenant/models"
+	"backend/modules/tenant/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+)
+
+func PlatformLoginHandler(ctx *gin.Context) {
+	var req models.PlatformLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid login request"})
+		return
+	}
+
+	token, err := service.PlatformLogin(req.Email, req.Password)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
+		return
+	}
+	utils.MarkSessionIssued(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"access_token": token, "token_type": "bearer"})
+}
+
+func ListTenantsHandler(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	skip, err := strconv.Atoi(ctx.DefaultQuery("skip", "0"))
+	if err != nil || skip < 0 {
+		skip = 0
+	}
+
+	var status *bool
+	if raw := ctx.Query("status"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status filter"})
+			return
+		}
+		status = &parsed
+	}
+
+	tenants, err := service.ListTenants(ctx.Query("q"), status, limit, skip)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, tenants)
+}
+
+func GetTenantHealthHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	health, err := service.GetTenantHealth(id)
+	if err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, health)
+}
+
+func UpdateTenantStatusHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	var req models.UpdateTenantStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	tenant, err := service.SetTenantStatus(id, *req.Status)
+	if err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, tenant)
+}
+
+func InvalidateTenantCacheHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	if err := service.InvalidateTenantCache(id); err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tenant cache invalidated"})
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"backend/modules/tenant/models"
+	"backend/modules/tenant/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func ListTenantDomainsHandler(ctx *gin.Context) {
+	tenantID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	domains, err := service.ListTenantDomains(tenantID)
+	if err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, domains)
+}
+
+func AddTenantDomainHandler(ctx *gin.Context) {
+	tenantID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	var req models.AddTenantDomainRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	domain, err := service.AddTenantDomain(tenantID, req.Host, req.Kind)
+	if err != nil {
+		switch err.Error() {
+		case "invalid host", "invalid domain kind", "subdomain host must match the tenant domain",
+			"hosts under the platform domain must use kind subdomain":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case "host already registered":
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			respondTenantError(ctx, err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"domain": domain,
+		"verification": gin.H{
+			"txt_name":  service.VerificationTXTPrefix + domain.Host,
+			"txt_value": service.VerificationTXTValue + domain.VerificationToken,
+		},
+	})
+}
+
+func VerifyTenantDomainHandler(ctx *gin.Context) {
+	tenantID, domainID, ok := parseTenantDomainIDs(ctx)
+	if !ok {
+		return
+	}
+
+	domain, err := service.VerifyTenantDomain(tenantID, domainID)
+	if err != nil {
+		if err.Error() == "tenant domain not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, domain)
+}
+
+func DeleteTenantDomainHandler(ctx *gin.Context) {
+	tenantID, domainID, ok := parseTenantDomainIDs(ctx)
+	if !ok {
+		return
+	}
+
+	if err := service.DeleteTenantDomain(tenantID, domainID); err != nil {
+		if err.Error() == "tenant domain not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Domain deleted"})
+}
+
+func parseTenantDomainIDs(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	domainID, err := uuid.Parse(ctx.Param("domainId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return tenantID, domainID, true
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"backend/modules/tenant/service"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func RotateEncryptionKeysHandler(ctx *gin.Context) {
+	report, err := service.RotateEncryptionKeys(ctx.Query("dry_run") == "true")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if report.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	ctx.JSON(status, report)
+}
+
+func VerifyEncryptionKeysHandler(ctx *gin.Context) {
+	report, err := service.VerifyEncryptionKeys()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"backend/internal/db/postgres"
+	"backend/modules/tenant/models"
+	"backend/modules/tenant/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+func MigrateAllTenantsHandler(ctx *gin.Context) {
+	dryRun := ctx.Query("dry_run") == "true"
+
+	reports, err := postgres.MigrateAllTenants(dryRun)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"dry_run": dryRun, "tenants": reports})
+}
+
+func GetTenantMigrationsHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	statuses, err := service.TenantMigrationStatus(id)
+	if err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, statuses)
+}
+
+func MigrateTenantHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	report, err := service.MigrateTenantUp(id, ctx.Query("dry_run") == "true")
+	if err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+func RollbackTenantMigrationsHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	var req models.RollbackMigrationsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	steps, err := service.RollbackTenantMigrations(id, req.Module, req.Steps, req.DryRun)
+	if err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"dry_run": req.DryRun, "steps": steps})
+}
+
+func respondTenantError(ctx *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tenant not found"})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"backend/internal/db/postgres"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func GetPoolStatsHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"pool":                      postgres.Pool.Stats(),
+		"change_listener_connected": postgres.ListenerConnected(),
+	})
+}
+
+func EvictTenantPoolHandler(ctx *gin.Context) {
+	postgres.Pool.Delete(ctx.Param("domain"))
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tenant pool evicted"})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"backend/modules/tenant/models"
+	"backend/modules/tenant/repository"
+	"backend/modules/tenant/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func ProvisionTenantHandler(ctx *gin.Context) {
+	var req models.ProvisionTenantRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	job, err := service.StartProvisioning(&req)
+	if err != nil {
+		if errors.Is(err, repository.ErrTenantExists) {
+			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+func GetProvisioningJobHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := repository.GetProvisioningJob(id)
+	if err != nil {
+		if err.Error() == "provisioning job not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
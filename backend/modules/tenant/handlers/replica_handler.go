@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"backend/modules/tenant/models"
+	"backend/modules/tenant/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func ListTenantReplicasHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	replicas, err := service.ListTenantReplicas(id)
+	if err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, replicas)
+}
+
+func AddTenantReplicaHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	var req models.AddReplicaRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	replica, err := service.AddTenantReplica(id, &req)
+	if err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, replica)
+}
+
+func DeleteTenantReplicaHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+	replicaID, err := uuid.Parse(ctx.Param("replicaId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid replica ID"})
+		return
+	}
+
+	if err := service.DeleteTenantReplica(id, replicaID); err != nil {
+		if err.Error() == "replica not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		respondTenantError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Replica deleted"})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	settingsHandlers "backend/modules/settings/handlers"
+	settingsModels "backend/modules/settings/models"
+	settingsService "backend/modules/settings/service"
+	"backend/modules/tenant/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+func GetTenantSettingsHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+	if _, err := service.GetTenant(id); err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+
+	settings, err := settingsService.GetSettings(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, settingsModels.ToSettingsResponse(settings))
+}
+
+func UpdateTenantSettingsHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+	tenant, err := service.GetTenant(id)
+	if err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+
+	var req settingsModels.UpdateSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	settings, err := settingsService.UpdateSettings(tenant, &req)
+	if err != nil {
+		settingsHandlers.RespondSettingsError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, settingsModels.ToSettingsResponse(settings))
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"backend/modules/tenant/models"
+	"backend/modules/tenant/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func GetTenantUsageHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+	months, _ := strconv.Atoi(ctx.DefaultQuery("months", "12"))
+
+	report, err := service.TenantUsage(id, months)
+	if err != nil {
+		respondTenantError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
+
+func SetTenantQuotasHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tenant ID"})
+		return
+	}
+
+	var req models.SetQuotasRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+
+	report, err := service.SetTenantQuotas(id, req.Limits)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unknown metric") {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		respondTenantError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"backend/internal/db/migrator"
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"github.com/google/uuid"
+	"time"
+)
+
+type ProvisionTenantRequest struct {
+	Name   string             `json:"name" binding:"required"`
+	Domain string             `json:"domain" binding:"required"`
+	Admin  ProvisionAdminUser `json:"admin" binding:"required"`
+	// IsolationMode — database або schema; за замовчуванням TENANT_DEFAULT_ISOLATION
+	IsolationMode string `json:"isolation_mode" binding:"omitempty,oneof=database schema"`
+}
+
+type ProvisionAdminUser struct {
+	FullName string `json:"fullName" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8"`
+	Acronym  string `json:"acronym"`
+}
+
+type RollbackMigrationsRequest struct {
+	Module string `json:"module"`
+	Steps  int    `json:"steps" binding:"required,min=1"`
+	DryRun bool   `json:"dry_run"`
+}
+
+type PlatformLoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// TenantSummary — тентант без зашифрованих облікових даних БД
+type TenantSummary struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Domain        string    `json:"domain"`
+	Migrated      bool      `json:"migrated"`
+	Status        bool      `json:"status"`
+	IsolationMode string    `json:"isolation_mode"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type TenantList struct {
+	Data  []TenantSummary `json:"data"`
+	Count int64           `json:"count"`
+}
+
+type TenantUserCounts struct {
+	Total      int64 `json:"total"`
+	Active     int64 `json:"active"`
+	Admins     int64 `json:"admins"`
+	SuperUsers int64 `json:"superUsers"`
+}
+
+type TenantHealth struct {
+	Tenant            TenantSummary             `json:"tenant"`
+	Reachable         bool                      `json:"reachable"`
+	Error             string                    `json:"error,omitempty"`
+	Pool              *postgres.TenantPoolStats `json:"pool,omitempty"`
+	LastMigration     *migrator.SchemaMigration `json:"last_migration,omitempty"`
+	PendingMigrations int                       `json:"pending_migrations"`
+	Users             *TenantUserCounts         `json:"users,omitempty"`
+}
+
+type UpdateTenantStatusRequest struct {
+	Status *bool `json:"status" binding:"required"`
+}
+
+type AddTenantDomainRequest struct {
+	Host string `json:"host" binding:"required"`
+	Kind string `json:"kind"`
+}
+
+// SetQuotasRequest — ліміти за метриками; від'ємне значення знімає обмеження
+type SetQuotasRequest struct {
+	Limits map[string]int64 `json:"limits" binding:"required"`
+}
+
+type AddReplicaRequest struct {
+	DBHost        string `json:"db_host" binding:"required"`
+	DBPort        string `json:"db_port"`
+	DBUser        string `json:"db_user" binding:"required"`
+	DBPassword    string `json:"db_password" binding:"required"`
+	DBName        string `json:"db_name" binding:"required"`
+	Priority      int    `json:"priority"`
+	MaxLagSeconds int    `json:"max_lag_seconds" binding:"omitempty,min=0"`
+}
+
+type ReplicaStatus struct {
+	entities.TenantReplica
+	LagSeconds *float64 `json:"lag_seconds"`
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrTenantExists = errors.New("tenant with this name or domain already exists")
+
+// uniqueViolation — SQLSTATE порушення унікального обмеження
+const uniqueViolation = "23505"
+
+// CreateTenant покладається на унікальні name і domain: перевірка перед вставкою
+// не захищає від паралельних запитів
+func CreateTenant(tenant *entities.Tenant) error {
+	err := postgres.GetDB().Create(tenant).Error
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return ErrTenantExists
+	}
+	return err
+}
+
+func GetTenantByID(id uuid.UUID) (*entities.Tenant, error) {
+	var tenant entities.Tenant
+	if err := postgres.GetDB().Where("id = ?", id).First(&tenant).Error; err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func UpdateTenantFields(id uuid.UUID, fields map[string]interface{}) error {
+	return postgres.GetDB().Model(&entities.Tenant{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func DeleteTenant(id uuid.UUID) error {
+	return postgres.GetDB().Where("id = ?", id).Delete(&entities.Tenant{}).Error
+}
+
+func CreateProvisioningJob(job *entities.ProvisioningJob) error {
+	return postgres.GetDB().Create(job).Error
+}
+
+func GetProvisioningJob(id uuid.UUID) (*entities.ProvisioningJob, error) {
+	var job entities.ProvisioningJob
+	err := postgres.GetDB().Where("id = ?", id).First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("provisioning job not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// UpdateProvisioningJob зберігає поточний крок, прогрес і статус задачі
+func UpdateProvisioningJob(job *entities.ProvisioningJob) error {
+	job.UpdatedAt = time.Now()
+	return postgres.GetDB().Save(job).Error
+}
+
+// SearchTenants повертає сторінку тентантів, відфільтровану за назвою/доменом і статусом
+func SearchTenants(query string, status *bool, limit, skip int) ([]entities.Tenant, int64, error) {
+	db := postgres.GetDB().Model(&entities.Tenant{})
+	if query != "" {
+		like := "%" + query + "%"
+		db = db.Where("name ILIKE ? OR domain ILIKE ?", like, like)
+	}
+	if status != nil {
+		db = db.Where("status = ?", *status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tenants []entities.Tenant
+	if err := db.Order("domain ASC").Limit(limit).Offset(skip).Find(&tenants).Error; err != nil {
+		return nil, 0, err
+	}
+	return tenants, total, nil
+}
+
+func GetPlatformAdminByEmail(email string) (*entities.PlatformAdmin, error) {
+	var admin entities.PlatformAdmin
+	if err := postgres.GetDB().Where("email = ?", email).First(&admin).Error; err != nil {
+		return nil, err
+	}
+	return &admin, nil
+}
+
+func CreatePlatformAdmin(admin *entities.PlatformAdmin) error {
+	return postgres.GetDB().Create(admin).Error
+}
+
+func TouchPlatformAdminLogin(id uuid.UUID) error {
+	return postgres.GetDB().Model(&entities.PlatformAdmin{}).
+		Where("id = ?", id).
+		Update("last_login_at", time.Now()).Error
+}
+
+func ListTenantDomains(tenantID uuid.UUID) ([]entities.TenantDomain, error) {
+	var domains []entities.TenantDomain
+	err := postgres.GetDB().Where("tenant_id = ?", tenantID).Order("created_at ASC").Find(&domains).Error
+	return domains, err
+}
+
+func GetTenantDomain(tenantID, id uuid.UUID) (*entities.TenantDomain, error) {
+	var domain entities.TenantDomain
+	err := postgres.GetDB().Where("tenant_id = ? AND id = ?", tenantID, id).First(&domain).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("tenant domain not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+func TenantDomainHostExists(host string) (bool, error) {
+	var count int64
+	err := postgres.GetDB().Model(&entities.TenantDomain{}).Where("host = ?", host).Count(&count).Error
+	return count > 0, err
+}
+
+func CreateTenantDomain(domain *entities.TenantDomain) error {
+	return postgres.GetDB().Create(domain).Error
+}
+
+func UpdateTenantDomain(domain *entities.TenantDomain) error {
+	return postgres.GetDB().Save(domain).Error
+}
+
+func DeleteTenantDomain(id uuid.UUID) error {
+	return postgres.GetDB().Where("id = ?", id).Delete(&entities.TenantDomain{}).Error
+}
+
+func ListTenantReplicas(tenantID uuid.UUID) ([]entities.TenantReplica, error) {
+	var replicas []entities.TenantReplica
+	err := postgres.GetDB().Where("tenant_id = ?", tenantID).Order("priority ASC, created_at ASC").Find(&replicas).Error
+	return replicas, err
+}
+
+func CreateTenantReplica(replica *entities.TenantReplica) error {
+	return postgres.GetDB().Create(replica).Error
+}
+
+func DeleteTenantReplica(tenantID, id uuid.UUID) error {
+	result := postgres.GetDB().Where("tenant_id = ? AND id = ?", tenantID, id).Delete(&entities.TenantReplica{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("replica not found")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package tenant
+
+import (
+	"backend/internal/middleware"
+	"backend/modules/tenant/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/login", middleware.PlatformLoginLimiterMiddleware(), handlers.PlatformLoginHandler)
+
+	admin := r.Group("", middleware.PlatformAdminMiddleware())
+
+	tenantGroup := admin.Group("/tenants")
+	{
+		tenantGroup.POST("/", handlers.ProvisionTenantHandler)
+		tenantGroup.GET("/", handlers.ListTenantsHandler)
+		tenantGroup.GET("/:id", handlers.GetTenantHealthHandler)
+		tenantGroup.PATCH("/:id/status", handlers.UpdateTenantStatusHandler)
+		tenantGroup.POST("/:id/cache/invalidate", handlers.InvalidateTenantCacheHandler)
+		tenantGroup.GET("/:id/domains", handlers.ListTenantDomainsHandler)
+		tenantGroup.POST("/:id/domains", handlers.AddTenantDomainHandler)
+		tenantGroup.POST("/:id/domains/:domainId/verify", handlers.VerifyTenantDomainHandler)
+		tenantGroup.DELETE("/:id/domains/:domainId", handlers.DeleteTenantDomainHandler)
+		tenantGroup.GET("/:id/settings", handlers.GetTenantSettingsHandler)
+		tenantGroup.PATCH("/:id/settings", handlers.UpdateTenantSettingsHandler)
+		tenantGroup.GET("/:id/replicas", handlers.ListTenantReplicasHandler)
+		tenantGroup.POST("/:id/replicas", handlers.AddTenantReplicaHandler)
+		tenantGroup.DELETE("/:id/replicas/:replicaId", handlers.DeleteTenantReplicaHandler)
+		tenantGroup.GET("/:id/usage", handlers.GetTenantUsageHandler)
+		tenantGroup.PUT("/:id/quotas", handlers.SetTenantQuotasHandler)
+		tenantGroup.GET("/:id/export", handlers.ExportTenantHandler)
+		tenantGroup.POST("/:id/import", handlers.ImportTenantHandler)
+		tenantGroup.GET("/:id/migrations", handlers.GetTenantMigrationsHandler)
+		tenantGroup.POST("/:id/migrations/up", handlers.MigrateTenantHandler)
+		tenantGroup.POST("/:id/migrations/down", handlers.RollbackTenantMigrationsHandler)
+	}
+
+	jobGroup := admin.Group("/provisioning-jobs")
+	{
+		jobGroup.GET("/:id", handlers.GetProvisioningJobHandler)
+	}
+
+	poolGroup := admin.Group("/pool")
+	{
+		poolGroup.GET("/stats", handlers.GetPoolStatsHandler)
+		poolGroup.DELETE("/:domain", handlers.EvictTenantPoolHandler)
+	}
+
+	keyGroup := admin.Group("/encryption-keys")
+	{
+		keyGroup.GET("/status", handlers.VerifyEncryptionKeysHandler)
+		keyGroup.POST("/rotate", handlers.RotateEncryptionKeysHandler)
+	}
+
+	migrationGroup := admin.Group("/migrations")
+	{
+		migrationGroup.POST("/run", handlers.MigrateAllTenantsHandler)
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"backend/internal/db/migrator"
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"backend/internal/services/utils"
+	"backend/modules/tenant/models"
+	"backend/modules/tenant/repository"
+	userModels "backend/modules/user/models"
+	userUtils "backend/modules/user/utils"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"sync"
+)
+
+func ToSummary(t *entities.Tenant) models.TenantSummary {
+	return models.TenantSummary{
+		ID:            t.ID,
+		Name:          t.Name,
+		Domain:        t.Domain,
+		Migrated:      t.Migrated,
+		Status:        t.Status,
+		IsolationMode: t.IsolationMode,
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
+	}
+}
+
+func GetTenant(id uuid.UUID) (*entities.Tenant, error) {
+	return repository.GetTenantByID(id)
+}
+
+func ListTenants(query string, status *bool, limit, skip int) (*models.TenantList, error) {
+	tenants, total, err := repository.SearchTenants(query, status, limit, skip)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.TenantList{Data: make([]models.TenantSummary, 0, len(tenants)), Count: total}
+	for i := range tenants {
+		response.Data = append(response.Data, ToSummary(&tenants[i]))
+	}
+	return response, nil
+}
+
+// tenantConnection повертає підключення з пулу, а якщо його немає (тентант
+// неактивний або давно не використовувався) — тимчасове, яке треба закрити
+func tenantConnection(tenant *entities.Tenant) (*gorm.DB, func(), error) {
+	if db, ok := postgres.Pool.Get(tenant.Domain); ok {
+		return db, func() {}, nil
+	}
+	db, err := postgres.OpenTenantDB(tenant)
+	if err != nil {
+		return nil, nil, err
+	}
+	return db, func() { postgres.CloseTenantDB(db) }, nil
+}
+
+// GetTenantHealth збирає стан тентанта: пул з'єднань, міграції та кількість користувачів
+func GetTenantHealth(id uuid.UUID) (*models.TenantHealth, error) {
+	tenant, err := repository.GetTenantByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &models.TenantHealth{Tenant: ToSummary(tenant)}
+	if stats, ok := postgres.Pool.TenantStats(tenant.Domain); ok {
+		health.Pool = stats
+	}
+
+	db, release, err := tenantConnection(tenant)
+	if err != nil {
+		health.Error = err.Error()
+		return health, nil
+	}
+	defer release()
+	health.Reachable = true
+
+	// Перевірка стану лише читає: транзакція READ ONLY не дасть нічого записати
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION READ ONLY").Error; err != nil {
+			return err
+		}
+		return collectTenantHealth(tx, health)
+	})
+	if err != nil {
+		health.Error = err.Error()
+	}
+	return health, nil
+}
+
+func collectTenantHealth(db *gorm.DB, health *models.TenantHealth) error {
+	if last, err := migrator.LastApplied(db); err == nil {
+		health.LastMigration = last
+	}
+	plan, err := migrator.Plan(db, postgres.TenantMigrations())
+	if err != nil {
+		return err
+	}
+	health.PendingMigrations = len(plan)
+
+	counts := &models.TenantUserCounts{}
+	if err := db.Model(&userModels.User{}).Count(&counts.Total).Error; err != nil {
+		return err
+	}
+	db.Model(&userModels.User{}).Where("is_active = ?", true).Count(&counts.Active)
+	db.Model(&userModels.User{}).Where("is_admin = ?", true).Count(&counts.Admins)
+	db.Model(&userModels.User{}).Where("is_super_user = ?", true).Count(&counts.SuperUsers)
+	health.Users = counts
+	return nil
+}
+
+// SetTenantStatus призупиняє або відновлює тентанта і скидає його кеш
+func SetTenantStatus(id uuid.UUID, status bool) (*models.TenantSummary, error) {
+	if err := repository.UpdateTenantFields(id, map[string]interface{}{"status": status}); err != nil {
+		return nil, err
+	}
+	tenant, err := repository.GetTenantByID(id)
+	if err != nil {
+		return nil, err
+	}
+	postgres.Manager.ClearTenantCache(tenant.Domain)
+	log.Printf("🏷️ Tenant %s status set to %v", tenant.Domain, status)
+
+	summary := ToSummary(tenant)
+	return &summary, nil
+}
+
+func InvalidateTenantCache(id uuid.UUID) error {
+	tenant, err := repository.GetTenantByID(id)
+	if err != nil {
+		return err
+	}
+	postgres.Manager.ClearTenantCache(tenant.Domain)
+	return nil
+}
+
+// dummyPasswordHash порівнюється, коли оператора не знайдено: відповідь
+// займає стільки ж часу, і за нею не можна перебрати існуючі email
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, err := userUtils.HashPassword(uuid.NewString())
+	if err != nil {
+		log.Printf("❌ Cannot prepare dummy password hash: %v", err)
+	}
+	return hash
+})
+
+func PlatformLogin(email, password string) (string, error) {
+	admin, err := repository.GetPlatformAdminByEmail(email)
+	if err != nil {
+		userUtils.ComparePasswords(password, dummyPasswordHash())
+		return "", errors.New("invalid credentials")
+	}
+	if !userUtils.ComparePasswords(password, admin.Password) || !admin.IsActive {
+		return "", errors.New("invalid credentials")
+	}
+
+	token, err := utils.GeneratePlatformToken(admin.ID, admin.Email)
+	if err != nil {
+		return "", err
+	}
+	if err := repository.TouchPlatformAdminLogin(admin.ID); err != nil {
+		log.Printf("❌ Cannot update platform admin login time: %v", err)
+	}
+	return token, nil
+}
+
+// CreatePlatformAdmin створює оператора платформи (використовується CLI-командою)
+func CreatePlatformAdmin(email, fullName, password string) (*entities.PlatformAdmin, error) {
+	if len(password) < 8 {
+		return nil, errors.New("password must be at least 8 characters")
+	}
+	hashed, err := userUtils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+	admin := &entities.PlatformAdmin{Email: email, FullName: fullName, Password: hashed, IsActive: true}
+	if err := repository.CreatePlatformAdmin(admin); err != nil {
+		return nil, err
+	}
+	return admin, nil
+}
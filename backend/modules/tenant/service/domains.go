@@ -0,0 +1,141 @@
+package service
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"backend/modules/tenant/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// TXT-запис _admin-go-panel-verify.<host> зі значенням admin-go-panel-verify=<token>
+	VerificationTXTPrefix = "_admin-go-panel-verify."
+	VerificationTXTValue  = "admin-go-panel-verify="
+)
+
+var hostPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+func ListTenantDomains(tenantID uuid.UUID) ([]entities.TenantDomain, error) {
+	if _, err := repository.GetTenantByID(tenantID); err != nil {
+		return nil, err
+	}
+	return repository.ListTenantDomains(tenantID)
+}
+
+// AddTenantDomain реєструє хост тентанта. Власні домени й аліаси потребують
+// підтвердження, субдомен основного APP_URL підтверджується одразу.
+func AddTenantDomain(tenantID uuid.UUID, host, kind string) (*entities.TenantDomain, error) {
+	tenant, err := repository.GetTenantByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	host = postgres.NormalizeHost(host)
+	if !hostPattern.MatchString(host) {
+		return nil, errors.New("invalid host")
+	}
+	if kind == "" {
+		kind = entities.TenantDomainCustom
+	}
+
+	domain := &entities.TenantDomain{TenantID: tenant.ID, Host: host, Kind: kind}
+	switch kind {
+	case entities.TenantDomainSubdomain:
+		if host != tenant.Domain+"."+os.Getenv("APP_URL") {
+			return nil, errors.New("subdomain host must match the tenant domain")
+		}
+		now := time.Now()
+		domain.Verified = true
+		domain.VerifiedAt = &now
+	case entities.TenantDomainCustom, entities.TenantDomainAlias:
+		if appURL := os.Getenv("APP_URL"); appURL != "" && strings.HasSuffix(host, "."+appURL) {
+			return nil, errors.New("hosts under the platform domain must use kind subdomain")
+		}
+	default:
+		return nil, errors.New("invalid domain kind")
+	}
+
+	exists, err := repository.TenantDomainHostExists(host)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("host already registered")
+	}
+
+	token, err := randomSecret(16)
+	if err != nil {
+		return nil, err
+	}
+	domain.VerificationToken = token
+
+	if err := repository.CreateTenantDomain(domain); err != nil {
+		return nil, err
+	}
+	postgres.ClearHostCache(host, "")
+	return domain, nil
+}
+
+// VerifyTenantDomain перевіряє токен у DNS TXT і позначає хост підтвердженим.
+// HTTP-перевірки немає: хост, уже спрямований на платформу, не доводить володіння доменом.
+func VerifyTenantDomain(tenantID, domainID uuid.UUID) (*entities.TenantDomain, error) {
+	domain, err := repository.GetTenantDomain(tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if domain.Verified {
+		return domain, nil
+	}
+
+	if err := verifyByTXT(domain); err != nil {
+		return nil, fmt.Errorf("domain verification failed: %v", err)
+	}
+
+	now := time.Now()
+	domain.Verified = true
+	domain.VerifiedAt = &now
+	if err := repository.UpdateTenantDomain(domain); err != nil {
+		return nil, err
+	}
+	postgres.ClearHostCache(domain.Host, "")
+	log.Printf("🌐 Domain %s verified for tenant %s", domain.Host, tenantID)
+	return domain, nil
+}
+
+func verifyByTXT(domain *entities.TenantDomain) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	records, err := net.DefaultResolver.LookupTXT(ctx, VerificationTXTPrefix+domain.Host)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if strings.TrimSpace(record) == VerificationTXTValue+domain.VerificationToken {
+			return nil
+		}
+	}
+	return errors.New("verification TXT record not found")
+}
+
+func DeleteTenantDomain(tenantID, domainID uuid.UUID) error {
+	domain, err := repository.GetTenantDomain(tenantID, domainID)
+	if err != nil {
+		return err
+	}
+	if err := repository.DeleteTenantDomain(domain.ID); err != nil {
+		return err
+	}
+	postgres.ClearHostCache(domain.Host, "")
+	return nil
+}
@@ -0,0 +1,463 @@
+package service
+
+import (
+	"archive/zip"
+	"backend/internal/db/migrator"
+	"backend/internal/db/postgres"
+	blogModels "backend/modules/blog/models"
+	calendarModels "backend/modules/calendar/models"
+	messageModels "backend/modules/chat/messages/models"
+	roomModels "backend/modules/chat/rooms/models"
+	directModels "backend/modules/direct/models"
+	employeeModels "backend/modules/employees/models"
+	invitationModels "backend/modules/invitation/models"
+	itemModels "backend/modules/item/models"
+	mediaModels "backend/modules/media/models"
+	propertyModels "backend/modules/property/models"
+	reactionModels "backend/modules/reaction/models"
+	roleModels "backend/modules/role/models"
+	"backend/modules/tenant/repository"
+	userModels "backend/modules/user/models"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"io"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	ArchiveFormatVersion = 1
+	archiveManifest      = "manifest.json"
+	importBatchSize      = 500
+)
+
+// exportTables — таблиці тентанта в порядку залежностей (батьківські першими).
+// Нова таблиця модуля додається сюди або в excludedTables — інакше експорт
+// відмовить, щоб архів не виявився тихо неповним.
+var exportTables = []struct {
+	Module string
+	Model  interface{}
+}{
+	{"user", &userModels.User{}},
+	{"user", &userModels.UserTOTP{}},
+	{"user", &userModels.UserIdentity{}},
+	{"user", &userModels.APIKey{}},
+	{"role", &roleModels.Role{}},
+	{"role", &roleModels.UserRole{}},
+	{"invitation", &invitationModels.Invitation{}},
+	{"employees", &employeeModels.Employees{}},
+	{"calendar", &calendarModels.Calendar{}},
+	{"blog", &blogModels.Blog{}},
+	{"media", &mediaModels.Media{}},
+	{"item", &itemModels.Items{}},
+	{"property", &propertyModels.Property{}},
+	{"chat", &roomModels.ChatRooms{}},
+	{"chat", &messageModels.Messages{}},
+	{"chat", &messageModels.MessageRead{}},
+	{"direct", &directModels.DirectChat{}},
+	{"direct", &directModels.DirectMessage{}},
+	{"reactions", &reactionModels.Reaction{}},
+}
+
+// excludedTables — таблиці, які свідомо не переносяться, з причиною
+var excludedTables = map[string]string{
+	"schema_migrations": "schema state is recorded in the manifest",
+	// Журнал лише доповнюється і належить тентанту-джерелу; вивантажується через /audit-logs/export
+	"audit_logs": "append-only audit trail of the source tenant",
+	// Короткоживучі облікові дані прив'язані до тентанта-джерела і після перенесення не діють
+	"user_sessions":         "sessions are bound to the source tenant",
+	"password_reset_tokens": "single-use tokens are bound to the source tenant",
+	"sso_login_states":      "pending SSO logins expire within minutes",
+}
+
+type ArchiveTable struct {
+	Module string `json:"module"`
+	Table  string `json:"table"`
+	File   string `json:"file"`
+	Rows   int64  `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+type ArchiveManifest struct {
+	FormatVersion int               `json:"format_version"`
+	TenantID      uuid.UUID         `json:"tenant_id"`
+	Domain        string            `json:"domain"`
+	ExportedAt    time.Time         `json:"exported_at"`
+	Migrations    []migrator.Status `json:"migrations"`
+	Tables        []ArchiveTable    `json:"tables"`
+}
+
+type ImportReport struct {
+	TenantID uuid.UUID      `json:"tenant_id"`
+	Source   string         `json:"source_domain"`
+	Tables   []ArchiveTable `json:"tables"`
+}
+
+func tableName(db *gorm.DB, model interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+	return stmt.Schema.Table, nil
+}
+
+// ExportTenant пише zip-архив з даними тентанта: NDJSON на кожну таблицю
+// (рядок — row_to_json) та manifest.json з кількістю рядків і контрольними сумами
+func ExportTenant(tenantID uuid.UUID, w io.Writer) error {
+	tenant, err := repository.GetTenantByID(tenantID)
+	if err != nil {
+		return err
+	}
+	db, release, err := tenantConnection(tenant)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	statuses, err := migrator.List(db, postgres.TenantMigrations())
+	if err != nil {
+		return err
+	}
+
+	manifest := ArchiveManifest{
+		FormatVersion: ArchiveFormatVersion,
+		TenantID:      tenant.ID,
+		Domain:        tenant.Domain,
+		ExportedAt:    time.Now().UTC(),
+	}
+	for _, status := range statuses {
+		if status.Applied {
+			manifest.Migrations = append(manifest.Migrations, status)
+		}
+	}
+
+	archive := zip.NewWriter(w)
+
+	// Знімок в одній транзакції, щоб таблиці були узгоджені між собою
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY").Error; err != nil {
+			return err
+		}
+		if err := checkExportCoverage(tx); err != nil {
+			return err
+		}
+		for _, target := range exportTables {
+			table, err := tableName(tx, target.Model)
+			if err != nil {
+				return err
+			}
+			entry, err := exportTable(tx, archive, target.Module, table)
+			if err != nil {
+				return fmt.Errorf("export %s: %w", table, err)
+			}
+			manifest.Tables = append(manifest.Tables, *entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := archive.Create(archiveManifest)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return err
+	}
+
+	log.Printf("📦 Tenant %s exported (%d tables)", tenant.Domain, len(manifest.Tables))
+	return archive.Close()
+}
+
+// checkExportCoverage перевіряє, що кожна таблиця тентанта або експортується,
+// або явно виключена
+func checkExportCoverage(tx *gorm.DB) error {
+	covered := make(map[string]bool, len(exportTables)+len(excludedTables))
+	for _, target := range exportTables {
+		table, err := tableName(tx, target.Model)
+		if err != nil {
+			return err
+		}
+		covered[table] = true
+	}
+	for table := range excludedTables {
+		covered[table] = true
+	}
+
+	tables, err := tx.Migrator().GetTables()
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if !covered[table] {
+			return fmt.Errorf("table %s is neither exported nor excluded from the archive", table)
+		}
+	}
+	return nil
+}
+
+func exportTable(tx *gorm.DB, archive *zip.Writer, module, table string) (*ArchiveTable, error) {
+	entry := &ArchiveTable{Module: module, Table: table, File: "data/" + table + ".ndjson"}
+
+	file, err := archive.Create(entry.File)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	out := io.MultiWriter(file, hash)
+
+	rows, err := tx.Raw(fmt.Sprintf(`SELECT row_to_json(t)::text FROM %s t`, quoteTable(table))).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(out, line+"\n"); err != nil {
+			return nil, err
+		}
+		entry.Rows++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	entry.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return entry, nil
+}
+
+// ImportTenant відновлює архів у щойно створеного тентанта: перевіряє маніфест,
+// контрольні суми та сумісність схеми, потім вставляє всі таблиці однією транзакцією
+// зі збереженням ідентифікаторів
+func ImportTenant(tenantID uuid.UUID, archive *zip.Reader) (*ImportReport, error) {
+	tenant, err := repository.GetTenantByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, files, err := readManifest(archive)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range manifest.Tables {
+		if err := verifyArchiveFile(files[entry.File], entry); err != nil {
+			return nil, err
+		}
+	}
+
+	db, release, err := tenantConnection(tenant)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if err := CheckImportTarget(db, manifest); err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{TenantID: tenant.ID, Source: manifest.Domain}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Прибираємо те, що створив провіженінг (суперкористувач), у зворотному порядку
+		for i := len(manifest.Tables) - 1; i >= 0; i-- {
+			if err := tx.Exec("DELETE FROM " + quoteTable(manifest.Tables[i].Table)).Error; err != nil {
+				return err
+			}
+		}
+		for _, entry := range manifest.Tables {
+			if err := importTable(tx, files[entry.File], entry); err != nil {
+				return fmt.Errorf("import %s: %w", entry.Table, err)
+			}
+			report.Tables = append(report.Tables, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📥 Tenant %s imported from %s", tenant.Domain, manifest.Domain)
+	return report, nil
+}
+
+func readManifest(archive *zip.Reader) (*ArchiveManifest, map[string]*zip.File, error) {
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	file, ok := files[archiveManifest]
+	if !ok {
+		return nil, nil, errors.New("archive manifest not found")
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer reader.Close()
+
+	var manifest ArchiveManifest
+	if err := json.NewDecoder(reader).Decode(&manifest); err != nil {
+		return nil, nil, fmt.Errorf("invalid archive manifest: %w", err)
+	}
+	if manifest.FormatVersion != ArchiveFormatVersion {
+		return nil, nil, fmt.Errorf("unsupported archive format version %d", manifest.FormatVersion)
+	}
+
+	known := make(map[string]bool, len(exportTables))
+	for _, target := range exportTables {
+		table, err := tableName(postgres.GetDB(), target.Model)
+		if err != nil {
+			return nil, nil, err
+		}
+		known[table] = true
+	}
+	for _, entry := range manifest.Tables {
+		if !known[entry.Table] {
+			return nil, nil, fmt.Errorf("unknown table %s in archive", entry.Table)
+		}
+		if files[entry.File] == nil {
+			return nil, nil, fmt.Errorf("archive file %s is missing", entry.File)
+		}
+	}
+	return &manifest, files, nil
+}
+
+func verifyArchiveFile(file *zip.File, entry ArchiveTable) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	var rows int64
+	scanner := bufio.NewScanner(io.TeeReader(reader, hash))
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if hex.EncodeToString(hash.Sum(nil)) != entry.SHA256 {
+		return fmt.Errorf("checksum mismatch for %s", entry.File)
+	}
+	if rows != entry.Rows {
+		return fmt.Errorf("row count mismatch for %s: manifest %d, file %d", entry.File, entry.Rows, rows)
+	}
+	return nil
+}
+
+// CheckImportTarget дозволяє імпорт лише в новий тентант зі схемою не старішою за архів
+func CheckImportTarget(db *gorm.DB, manifest *ArchiveManifest) error {
+	statuses, err := migrator.List(db, postgres.TenantMigrations())
+	if err != nil {
+		return err
+	}
+	applied := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		applied[status.ID] = status.Applied
+	}
+	for _, migration := range manifest.Migrations {
+		if !applied[migration.ID] {
+			return fmt.Errorf("target schema is missing migration %s", migration.ID)
+		}
+	}
+
+	seeded := provisionedRows()
+	for _, entry := range manifest.Tables {
+		var count int64
+		if err := db.Table(entry.Table).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > seeded[entry.Table] {
+			return fmt.Errorf("target tenant is not empty: table %s has %d rows", entry.Table, count)
+		}
+	}
+	return nil
+}
+
+func importTable(tx *gorm.DB, file *zip.File, entry ArchiveTable) error {
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+
+	var columns []string
+	batch := make([]string, 0, importBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		// json_populate_recordset приводить значення до типів колонок цільової таблиці;
+		// колонки, яких немає в архіві, отримують значення за замовчуванням
+		sql := fmt.Sprintf(
+			`INSERT INTO %[1]s (%[2]s) SELECT %[2]s FROM json_populate_recordset(NULL::%[1]s, ?::json)`,
+			quoteTable(entry.Table), strings.Join(columns, ", "),
+		)
+		if err := tx.Exec(sql, "["+strings.Join(batch, ",")+"]").Error; err != nil {
+			return err
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	var inserted int64
+	for scanner.Scan() {
+		line := scanner.Text()
+		if columns == nil {
+			var row map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(line), &row); err != nil {
+				return err
+			}
+			for column := range row {
+				columns = append(columns, quoteTable(column))
+			}
+		}
+		batch = append(batch, line)
+		inserted++
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Table(entry.Table).Count(&count).Error; err != nil {
+		return err
+	}
+	if count != entry.Rows || inserted != entry.Rows {
+		return fmt.Errorf("expected %d rows, got %d", entry.Rows, count)
+	}
+	return nil
+}
+
+func quoteTable(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
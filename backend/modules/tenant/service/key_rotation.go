@@ -0,0 +1,183 @@
+package service
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/services/utils"
+	"fmt"
+	"gorm.io/gorm"
+	"log"
+	"sort"
+)
+
+// encryptedColumns описує таблицю адмін-БД з колонками, зашифрованими utils.Encrypt
+type encryptedColumns struct {
+	Table   string
+	Columns []string
+	// Domain — колонка, за якою очищаємо кеш тентанта після перешифрування (необов'язкова)
+	Domain string
+}
+
+var rotationTargets = []encryptedColumns{
+	{Table: "tenants", Columns: []string{"db_host", "db_user", "db_password", "db_name"}, Domain: "domain"},
+	{Table: "tenant_settings", Columns: []string{"smtp_password", "oidc_client_secret"}},
+	{Table: "tenant_replicas", Columns: []string{"db_host", "db_user", "db_password", "db_name"}},
+}
+
+type KeyRotationRowResult struct {
+	Table   string `json:"table"`
+	ID      string `json:"id"`
+	Rotated int    `json:"rotated"`
+	Error   string `json:"error,omitempty"`
+}
+
+type KeyRotationReport struct {
+	DryRun     bool                   `json:"dry_run"`
+	ActiveKey  string                 `json:"active_key"`
+	Rows       []KeyRotationRowResult `json:"rows"`
+	Failed     int                    `json:"failed"`
+	KeyUsage   map[string]int         `json:"key_usage"`
+	Retirable  []string               `json:"retirable_keys"`
+	Unreadable int                    `json:"unreadable"`
+}
+
+// RotateEncryptionKeys перешифровує всі значення, зашифровані неактивними ключами,
+// активним ключем. Кожне нове значення перевіряється розшифруванням до збереження.
+func RotateEncryptionKeys(dryRun bool) (*KeyRotationReport, error) {
+	activeKey, err := utils.ActiveKeyID()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &KeyRotationReport{DryRun: dryRun, ActiveKey: activeKey}
+	db := postgres.GetDB()
+
+	for _, target := range rotationTargets {
+		rows, err := loadEncryptedRows(db, target)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			result := rotateRow(db, target, row, activeKey, dryRun)
+			if result.Error != "" {
+				report.Failed++
+			}
+			if result.Rotated > 0 || result.Error != "" {
+				report.Rows = append(report.Rows, result)
+			}
+		}
+	}
+
+	if err := fillKeyUsage(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// VerifyEncryptionKeys розшифровує всі значення поточним набором ключів і рахує,
+// які ключі ще використовуються. Ключ можна виводити з обігу, коли він у списку Retirable.
+func VerifyEncryptionKeys() (*KeyRotationReport, error) {
+	activeKey, err := utils.ActiveKeyID()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &KeyRotationReport{DryRun: true, ActiveKey: activeKey}
+	if err := fillKeyUsage(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func loadEncryptedRows(db *gorm.DB, target encryptedColumns) ([]map[string]interface{}, error) {
+	columns := append([]string{"id"}, target.Columns...)
+	if target.Domain != "" {
+		columns = append(columns, target.Domain)
+	}
+	var rows []map[string]interface{}
+	if err := db.Table(target.Table).Select(columns).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("load %s: %w", target.Table, err)
+	}
+	return rows, nil
+}
+
+func rotateRow(db *gorm.DB, target encryptedColumns, row map[string]interface{}, activeKey string, dryRun bool) KeyRotationRowResult {
+	result := KeyRotationRowResult{Table: target.Table, ID: fmt.Sprint(row["id"])}
+	updates := make(map[string]interface{})
+
+	for _, column := range target.Columns {
+		value, _ := row[column].(string)
+		if value == "" || utils.CiphertextKeyID(value) == activeKey {
+			continue
+		}
+
+		plaintext, err := utils.Decrypt(value)
+		if err != nil {
+			result.Error = fmt.Sprintf("%s: decrypt: %v", column, err)
+			return result
+		}
+		reencrypted, err := utils.EncryptWithKey(activeKey, plaintext)
+		if err != nil {
+			result.Error = fmt.Sprintf("%s: encrypt: %v", column, err)
+			return result
+		}
+		// Перевіряємо, що нове значення читається, перш ніж перезаписати старе
+		if check, err := utils.Decrypt(reencrypted); err != nil || check != plaintext {
+			result.Error = fmt.Sprintf("%s: verification failed", column)
+			return result
+		}
+		updates[column] = reencrypted
+	}
+
+	result.Rotated = len(updates)
+	if len(updates) == 0 || dryRun {
+		return result
+	}
+
+	if err := db.Table(target.Table).Where("id = ?", row["id"]).Updates(updates).Error; err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if domain, ok := row[target.Domain].(string); ok {
+		postgres.Manager.ClearTenantCache(domain)
+	}
+	log.Printf("🔑 Re-encrypted %d columns of %s %s with key %s", len(updates), target.Table, result.ID, activeKey)
+	return result
+}
+
+func fillKeyUsage(report *KeyRotationReport) error {
+	db := postgres.GetDB()
+	report.KeyUsage = make(map[string]int)
+
+	for _, target := range rotationTargets {
+		rows, err := loadEncryptedRows(db, target)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			for _, column := range target.Columns {
+				value, _ := row[column].(string)
+				if value == "" {
+					continue
+				}
+				if _, err := utils.Decrypt(value); err != nil {
+					report.Unreadable++
+					continue
+				}
+				report.KeyUsage[utils.CiphertextKeyID(value)]++
+			}
+		}
+	}
+
+	configured, err := utils.ConfiguredKeyIDs()
+	if err != nil {
+		return err
+	}
+	for _, id := range configured {
+		if id != report.ActiveKey && report.KeyUsage[id] == 0 {
+			report.Retirable = append(report.Retirable, id)
+		}
+	}
+	sort.Strings(report.Retirable)
+	return nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"backend/internal/db/migrator"
+	"backend/internal/db/postgres"
+	"backend/modules/tenant/repository"
+	"github.com/google/uuid"
+)
+
+// TenantMigrationStatus повертає стан кожної міграції в БД тентанта
+func TenantMigrationStatus(tenantID uuid.UUID) ([]migrator.Status, error) {
+	tenant, err := repository.GetTenantByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := postgres.OpenTenantDB(tenant)
+	if err != nil {
+		return nil, err
+	}
+	defer postgres.CloseTenantDB(db)
+
+	return migrator.List(db, postgres.TenantMigrations())
+}
+
+// MigrateTenantUp застосовує незастосовані міграції одного тентанта
+func MigrateTenantUp(tenantID uuid.UUID, dryRun bool) (*postgres.TenantMigrationReport, error) {
+	tenant, err := repository.GetTenantByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	report := postgres.MigrateSingleTenant(tenant, dryRun)
+	return &report, nil
+}
+
+// RollbackTenantMigrations відкочує останні steps міграцій модуля
+func RollbackTenantMigrations(tenantID uuid.UUID, module string, steps int, dryRun bool) ([]migrator.Step, error) {
+	tenant, err := repository.GetTenantByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := postgres.OpenTenantDB(tenant)
+	if err != nil {
+		return nil, err
+	}
+	defer postgres.CloseTenantDB(db)
+
+	return migrator.Down(db, postgres.TenantMigrations(), module, steps, dryRun)
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"backend/internal/services/utils"
+	"backend/modules/calendar/service/reminder"
+	roleModels "backend/modules/role/models"
+	"backend/modules/tenant/models"
+	"backend/modules/tenant/repository"
+	userModels "backend/modules/user/models"
+	userRepository "backend/modules/user/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const (
+	JobStatusPending    = "pending"
+	JobStatusRunning    = "running"
+	JobStatusSucceeded  = "succeeded"
+	JobStatusRolledBack = "rolled_back"
+	JobStatusFailed     = "failed"
+)
+
+var domainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// StartProvisioning перевіряє запит, створює задачу провіженінгу
+// і запускає її у фоні. Стан задачі можна відстежувати за її ID.
+func StartProvisioning(req *models.ProvisionTenantRequest) (*entities.ProvisioningJob, error) {
+	req.Domain = strings.ToLower(strings.TrimSpace(req.Domain))
+	if !domainPattern.MatchString(req.Domain) {
+		return nil, errors.New("domain must contain only lowercase letters, digits and hyphens")
+	}
+	if req.IsolationMode == "" {
+		req.IsolationMode = os.Getenv("TENANT_DEFAULT_ISOLATION")
+	}
+	switch req.IsolationMode {
+	case "":
+		req.IsolationMode = entities.IsolationDatabase
+	case entities.IsolationDatabase, entities.IsolationSchema:
+	default:
+		return nil, errors.New("isolation mode must be database or schema")
+	}
+
+	// Запис тентанта створюємо синхронно: унікальні name/domain відсікають
+	// паралельні запити на той самий тентант ще до запуску фонової задачі
+	p := &provisioner{req: *req}
+	if err := p.createTenantRecord(); err != nil {
+		return nil, err
+	}
+
+	job := &entities.ProvisioningJob{
+		Domain:    req.Domain,
+		TenantID:  &p.tenant.ID,
+		Status:    JobStatusPending,
+		StartedAt: time.Now(),
+	}
+	if err := repository.CreateProvisioningJob(job); err != nil {
+		if rollbackErr := repository.DeleteTenant(p.tenant.ID); rollbackErr != nil {
+			log.Printf("❌ [%s] rollback error: %v", req.Domain, rollbackErr)
+		}
+		return nil, err
+	}
+
+	p.job = job
+	go p.run()
+
+	return job, nil
+}
+
+type provisionStep struct {
+	name string
+	run  func() error
+}
+
+type provisioner struct {
+	job        *entities.ProvisioningJob
+	req        models.ProvisionTenantRequest
+	tenant     *entities.Tenant
+	dbName     string
+	schemaName string
+	dbUser     string
+	dbPassword string
+	db         *gorm.DB
+
+	// Кроки відкату виконуються у зворотному порядку
+	rollbacks []func() error
+}
+
+func (p *provisioner) run() {
+	// Паніка в будь-якому кроці не повинна валити сервер і лишати задачу в running
+	// зі створеними БД і роллю: відкочуємо зареєстровані кроки й позначаємо задачу failed
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ [%s] provisioning step %s panicked: %v\n%s", p.req.Domain, p.job.Step, r, debug.Stack())
+			p.rollback(fmt.Errorf("%s: panic: %v", p.job.Step, r), JobStatusFailed)
+		}
+	}()
+
+	storage := provisionStep{"create_database", p.createDatabase}
+	if p.req.IsolationMode == entities.IsolationSchema {
+		storage = provisionStep{"create_schema", p.createSchema}
+	}
+
+	steps := []provisionStep{
+		{"create_role", p.createRole},
+		storage,
+		{"run_migrations", p.runMigrations},
+		{"seed_superuser", p.seedSuperUser},
+		{"activate_tenant", p.activateTenant},
+		{"start_reminders", p.startReminders},
+	}
+
+	p.job.Status = JobStatusRunning
+	for i, step := range steps {
+		p.job.Step = step.name
+		p.job.Progress = i * 100 / len(steps)
+		p.save()
+
+		if err := step.run(); err != nil {
+			log.Printf("❌ [%s] provisioning step %s failed: %v", p.req.Domain, step.name, err)
+			p.rollback(fmt.Errorf("%s: %w", step.name, err), JobStatusRolledBack)
+			return
+		}
+	}
+
+	postgres.CloseTenantDB(p.db)
+
+	now := time.Now()
+	p.job.Status = JobStatusSucceeded
+	p.job.Step = "done"
+	p.job.Progress = 100
+	p.job.FinishedAt = &now
+	p.save()
+	log.Printf("✅ Tenant %s provisioned", p.req.Domain)
+}
+
+// rollback виконує зареєстровані відкати; status — підсумковий стан задачі,
+// якщо всі відкати вдалися (інакше failed)
+func (p *provisioner) rollback(cause error, status string) {
+	postgres.CloseTenantDB(p.db)
+
+	var rollbackErrs []string
+	for i := len(p.rollbacks) - 1; i >= 0; i-- {
+		if err := safeRollback(p.rollbacks[i]); err != nil {
+			log.Printf("❌ [%s] rollback error: %v", p.req.Domain, err)
+			rollbackErrs = append(rollbackErrs, err.Error())
+			status = JobStatusFailed
+		}
+	}
+
+	now := time.Now()
+	p.job.Status = status
+	p.job.Error = cause.Error()
+	if len(rollbackErrs) > 0 {
+		p.job.Error += "; rollback: " + strings.Join(rollbackErrs, "; ")
+	}
+	p.job.FinishedAt = &now
+	p.save()
+}
+
+// safeRollback не дає паніці одного відкату зупинити решту
+func safeRollback(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn()
+}
+
+func (p *provisioner) save() {
+	if err := repository.UpdateProvisioningJob(p.job); err != nil {
+		log.Printf("❌ [%s] cannot save provisioning job: %v", p.req.Domain, err)
+	}
+}
+
+func (p *provisioner) createTenantRecord() error {
+	p.dbName = "tenant_" + strings.ReplaceAll(p.req.Domain, "-", "_")
+	p.dbUser = p.dbName + "_user"
+	if p.req.IsolationMode == entities.IsolationSchema {
+		// Тентант живе в окремій схемі спільної БД під власною роллю
+		p.schemaName = p.dbName
+		p.dbName = postgres.SharedTenantDBName()
+	}
+
+	password, err := randomSecret(24)
+	if err != nil {
+		return err
+	}
+	p.dbPassword = password
+
+	host := os.Getenv("TENANT_DB_HOST")
+	if host == "" {
+		host = os.Getenv("POSTGRES_SERVER")
+	}
+
+	tenant := &entities.Tenant{
+		Name:          p.req.Name,
+		Domain:        p.req.Domain,
+		DBPort:        os.Getenv("POSTGRES_PORT"),
+		IsolationMode: p.req.IsolationMode,
+		SchemaName:    p.schemaName,
+	}
+	for _, field := range []struct {
+		dst   *string
+		value string
+	}{
+		{&tenant.DBHost, host},
+		{&tenant.DBUser, p.dbUser},
+		{&tenant.DBPassword, p.dbPassword},
+		{&tenant.DBName, p.dbName},
+	} {
+		encrypted, err := utils.Encrypt(field.value)
+		if err != nil {
+			return fmt.Errorf("encrypt tenant credentials: %w", err)
+		}
+		*field.dst = encrypted
+	}
+
+	if err := repository.CreateTenant(tenant); err != nil {
+		return err
+	}
+	p.tenant = tenant
+	p.rollbacks = append(p.rollbacks, func() error {
+		return repository.DeleteTenant(tenant.ID)
+	})
+	return nil
+}
+
+func (p *provisioner) createRole() error {
+	if err := postgres.CreateTenantRole(p.dbUser, p.dbPassword); err != nil {
+		return err
+	}
+	p.rollbacks = append(p.rollbacks, func() error {
+		return postgres.DropTenantRole(p.dbUser)
+	})
+	return nil
+}
+
+func (p *provisioner) createDatabase() error {
+	// Відкат реєструємо заздалегідь: база могла створитися, навіть якщо розширення — ні
+	p.rollbacks = append(p.rollbacks, func() error {
+		return postgres.DropTenantDatabase(p.dbName)
+	})
+	return postgres.CreateTenantDatabase(p.dbName, p.dbUser)
+}
+
+func (p *provisioner) createSchema() error {
+	p.rollbacks = append(p.rollbacks, func() error {
+		return postgres.DropTenantSchema(p.schemaName, p.dbUser)
+	})
+	return postgres.CreateTenantSchema(p.schemaName, p.dbUser)
+}
+
+func (p *provisioner) runMigrations() error {
+	db, err := postgres.OpenTenantDB(p.tenant)
+	if err != nil {
+		return err
+	}
+	p.db = db
+
+	if err := postgres.MigrateTenant(db); err != nil {
+		return err
+	}
+	return repository.UpdateTenantFields(p.tenant.ID, map[string]interface{}{"migrated": true})
+}
+
+func (p *provisioner) seedSuperUser() error {
+	return SeedSuperUser(p.db, p.req.Admin)
+}
+
+// SeedSuperUser створює першого адміністратора тентанта
+func SeedSuperUser(db *gorm.DB, admin models.ProvisionAdminUser) error {
+	user := &userModels.User{
+		FullName:    admin.FullName,
+		Email:       admin.Email,
+		Password:    admin.Password,
+		Acronym:     admin.Acronym,
+		IsActive:    true,
+		IsAdmin:     true,
+		IsSuperUser: true,
+	}
+	_, err := userRepository.CreateUser(db, user, uuid.Nil, "SYS")
+	return err
+}
+
+// provisionedRows — рядки, які провіженінг лишає в БД тентанта: системні ролі
+// з міграцій і суперкористувач разом з профілем працівника та роллю admin
+// (див. SeedSuperUser). Імпорт дозволений лише поверх них.
+func provisionedRows() map[string]int64 {
+	return map[string]int64{
+		"roles":      int64(len(roleModels.SystemRoles())),
+		"users":      1,
+		"employees":  1,
+		"user_roles": 1,
+	}
+}
+
+func (p *provisioner) activateTenant() error {
+	if err := repository.UpdateTenantFields(p.tenant.ID, map[string]interface{}{"status": true}); err != nil {
+		return err
+	}
+	p.rollbacks = append(p.rollbacks, func() error {
+		postgres.Manager.ClearTenantCache(p.req.Domain)
+		return repository.UpdateTenantFields(p.tenant.ID, map[string]interface{}{"status": false})
+	})
+	postgres.Manager.ClearTenantCache(p.req.Domain)
+	return nil
+}
+
+func (p *provisioner) startReminders() error {
+	// Перевіряємо, що БД нового тентанта доступна; задача далі бере підключення сама
+	if _, err := postgres.Manager.GetConnectionByDomain(p.req.Domain); err != nil {
+		return err
+	}
+	reminder.StartReminderJobs(p.req.Domain)
+	return nil
+}
+
+func randomSecret(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"backend/internal/services/utils"
+	"backend/modules/tenant/models"
+	"backend/modules/tenant/repository"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+func ListTenantReplicas(tenantID uuid.UUID) ([]models.ReplicaStatus, error) {
+	if _, err := repository.GetTenantByID(tenantID); err != nil {
+		return nil, err
+	}
+	replicas, err := repository.ListTenantReplicas(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.ReplicaStatus, 0, len(replicas))
+	for _, replica := range replicas {
+		status := models.ReplicaStatus{TenantReplica: replica}
+		if lag, ok := postgres.ReplicaLag(replica.ID); ok {
+			status.LagSeconds = &lag
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// AddTenantReplica реєструє репліку; облікові дані шифруються так само, як у тентанта
+func AddTenantReplica(tenantID uuid.UUID, req *models.AddReplicaRequest) (*entities.TenantReplica, error) {
+	tenant, err := repository.GetTenantByID(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	replica := &entities.TenantReplica{
+		TenantID:      tenant.ID,
+		DBPort:        req.DBPort,
+		Priority:      req.Priority,
+		MaxLagSeconds: req.MaxLagSeconds,
+		Enabled:       true,
+	}
+	if replica.MaxLagSeconds == 0 {
+		replica.MaxLagSeconds = 10
+	}
+	for _, field := range []struct {
+		dst   *string
+		value string
+	}{
+		{&replica.DBHost, req.DBHost},
+		{&replica.DBUser, req.DBUser},
+		{&replica.DBPassword, req.DBPassword},
+		{&replica.DBName, req.DBName},
+	} {
+		encrypted, err := utils.Encrypt(field.value)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt replica credentials: %w", err)
+		}
+		*field.dst = encrypted
+	}
+
+	if err := repository.CreateTenantReplica(replica); err != nil {
+		return nil, err
+	}
+	postgres.Manager.ClearTenantCache(tenant.Domain)
+	return replica, nil
+}
+
+func DeleteTenantReplica(tenantID, replicaID uuid.UUID) error {
+	tenant, err := repository.GetTenantByID(tenantID)
+	if err != nil {
+		return err
+	}
+	// Спершу скидаємо кеш, поки в ньому ще є репліка — разом з нею закриється її пул
+	postgres.Manager.ClearTenantCache(tenant.Domain)
+	return repository.DeleteTenantReplica(tenant.ID, replicaID)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"backend/internal/services/metering"
+	"backend/modules/tenant/repository"
+	"github.com/google/uuid"
+	"log"
+)
+
+// TenantUsage повертає звіт використання; користувачів рахує наживо в БД тентанта
+func TenantUsage(id uuid.UUID, months int) (*metering.UsageReport, error) {
+	tenant, err := repository.GetTenantByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	db, release, err := tenantConnection(tenant)
+	if err != nil {
+		log.Printf("⚠️ Tenant %s DB unavailable for usage report: %v", tenant.Domain, err)
+		return metering.Report(tenant.ID, nil, months)
+	}
+	defer release()
+	return metering.Report(tenant.ID, db, months)
+}
+
+func SetTenantQuotas(id uuid.UUID, limits map[string]int64) (*metering.UsageReport, error) {
+	if _, err := repository.GetTenantByID(id); err != nil {
+		return nil, err
+	}
+	if err := metering.SetQuotas(id, limits); err != nil {
+		return nil, err
+	}
+	return TenantUsage(id, 1)
+}
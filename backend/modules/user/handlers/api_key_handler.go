@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	utils2 "backend/internal/services/utils"
+	roleModels "backend/modules/role/models"
+	"backend/modules/user/models"
+	"backend/modules/user/repository"
+	"backend/modules/user/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+)
+
+func respondAPIKeyError(ctx *gin.Context, err error) {
+	switch {
+	case err.Error() == "api key not found" || err.Error() == "service account not found" || err.Error() == "role not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "permission denied"):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "unknown permission") || strings.HasSuffix(err.Error(), "is required") ||
+		strings.HasPrefix(err.Error(), "expiresInDays"):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// Ключами керують лише з інтерактивної сесії, не іншим ключем
+func requireSession(ctx *gin.Context) bool {
+	_, ok := utils2.GetSessionIDFromContext(ctx)
+	return ok
+}
+
+func ListMyAPIKeysHandler(ctx *gin.Context) {
+	userID, ok := utils2.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	keys, err := service.ListAPIKeys(db, userID)
+	if err != nil {
+		respondAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, keys)
+}
+
+func CreateMyAPIKeyHandler(ctx *gin.Context) {
+	if !requireSession(ctx) {
+		return
+	}
+	var req models.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	user, ok := utils2.GetCurrentUserFromContext(ctx, db)
+	if !ok {
+		return
+	}
+	granted, err := utils2.GetPermissionsFromContext(ctx, db)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, err := service.CreateAPIKey(db, user, user.ID, granted, &req)
+	if err != nil {
+		respondAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, key)
+}
+
+func RevokeMyAPIKeyHandler(ctx *gin.Context) {
+	if !requireSession(ctx) {
+		return
+	}
+	keyID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid key ID"})
+		return
+	}
+	userID, ok := utils2.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := repository.RevokeAPIKey(db, userID, keyID); err != nil {
+		respondAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
+
+func ListServiceAccountsHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	accounts, err := service.ListServiceAccounts(db)
+	if err != nil {
+		respondAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, accounts)
+}
+
+func CreateServiceAccountHandler(ctx *gin.Context) {
+	if !requireSession(ctx) {
+		return
+	}
+	var req models.CreateServiceAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	canAssignRoles := utils2.HasPermission(ctx, db, roleModels.PermRolesManage)
+	account, err := service.CreateServiceAccount(db, &req, canAssignRoles)
+	if err != nil {
+		respondAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, account)
+}
+
+func DeleteServiceAccountHandler(ctx *gin.Context) {
+	if !requireSession(ctx) {
+		return
+	}
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service account ID"})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := service.DeleteServiceAccount(db, id); err != nil {
+		respondAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Service account deleted"})
+}
+
+func ListServiceAccountKeysHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service account ID"})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	if _, err := repository.GetServiceAccountByID(db, id); err != nil {
+		respondAPIKeyError(ctx, err)
+		return
+	}
+	keys, err := service.ListAPIKeys(db, id)
+	if err != nil {
+		respondAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, keys)
+}
+
+func CreateServiceAccountKeyHandler(ctx *gin.Context) {
+	if !requireSession(ctx) {
+		return
+	}
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service account ID"})
+		return
+	}
+	var req models.CreateAPIKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	account, err := repository.GetServiceAccountByID(db, id)
+	if err != nil {
+		respondAPIKeyError(ctx, err)
+		return
+	}
+	creatorID, ok := utils2.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	granted, err := utils2.GetPermissionsFromContext(ctx, db)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, err := service.CreateAPIKey(db, account, creatorID, granted, &req)
+	if err != nil {
+		respondAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, key)
+}
+
+func RevokeServiceAccountKeyHandler(ctx *gin.Context) {
+	if !requireSession(ctx) {
+		return
+	}
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service account ID"})
+		return
+	}
+	keyID, err := uuid.Parse(ctx.Param("keyId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid key ID"})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := repository.RevokeAPIKey(db, id, keyID); err != nil {
+		respondAPIKeyError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
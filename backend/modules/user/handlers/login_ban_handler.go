@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"backend/internal/db/postgres"
+	utils2 "backend/internal/services/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+)
+
+// ListLoginBansHandler — чинні блокування входу в тентанті
+func ListLoginBansHandler(ctx *gin.Context) {
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+	bans, err := postgres.ListLoginBans(tenant.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"data": bans, "count": len(bans)})
+}
+
+func DeleteLoginBanHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ban ID"})
+		return
+	}
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+	if err := postgres.DeleteLoginBan(tenant.ID, id); err != nil {
+		if err.Error() == "login ban not found" {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Login ban cleared"})
+}
+
+// ClearLoginBansHandler знімає всі блокування облікового запису (?email=)
+func ClearLoginBansHandler(ctx *gin.Context) {
+	email := strings.ToLower(strings.TrimSpace(ctx.Query("email")))
+	if email == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Email is required"})
+		return
+	}
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+	cleared, err := postgres.DeleteLoginBansByEmail(tenant.ID, email)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Login bans cleared", "cleared": cleared})
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
-	"backend/internal/entities"
 	utils3 "backend/internal/services/utils"
+	roleModels "backend/modules/role/models"
+	roleRepository "backend/modules/role/repository"
 	"backend/modules/user/models"
 	"backend/modules/user/repository"
+	"backend/modules/user/service"
 	utils2 "backend/modules/user/utils"
 	"errors"
 	"github.com/gin-gonic/gin"
@@ -34,26 +36,68 @@ func LoginHandler(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if !utils2.ComparePasswords(loginRequest.Password, user.Password) {
+	// Сервісні акаунти входять лише через API-ключі
+	if user.IsServiceAccount || !utils2.ComparePasswords(loginRequest.Password, user.Password) {
 		log.Println("Password mismatch for user:", user.Email)
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
+	// Статус перевіряємо лише після пароля, щоб не розкривати його стороннім
+	if !user.IsActive {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Account is deactivated"})
+		return
+	}
+
+	// Для тентантів лише з SSO пароль лишається тільки у власників (роль з "*"),
+	// щоб збій IdP не закрив доступ до панелі
+	if utils3.GetTenantSettingsFromContext(ctx).PasswordLoginDisabled {
+		permissions, err := roleRepository.GetUserPermissions(db, user.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !roleModels.HasPermission(permissions, roleModels.PermAll) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "Password login is disabled, use SSO"})
+			return
+		}
+	}
 
 	// Tenant отримуємо з middleware-контексту
-	tenant, ok := ctx.Get("tenant")
+	tenant, ok := utils3.GetTenantFromContext(ctx)
 	if !ok {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
 		return
 	}
 
-	tenantData := tenant.(entities.Tenant)
+	// Якщо увімкнено 2FA (або тентант її вимагає) — замість токенів видаємо
+	// проміжний токен для другого кроку
+	mfaEnabled, err := service.IsMFAEnabled(db, user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if mfaEnabled || utils3.GetTenantSettingsFromContext(ctx).Require2FA {
+		mfaToken, err := utils3.GenerateMFAToken(user.ID, user.Email, tenant.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+			return
+		}
+		ctx.JSON(http.StatusOK, models.MFAChallengeResponse{
+			MFARequired:        true,
+			EnrollmentRequired: !mfaEnabled,
+			MFAToken:           mfaToken,
+			TokenType:          "mfa",
+			ExpiresIn:          int(utils3.MFATokenTTL.Seconds()),
+		})
+		return
+	}
 
-	token, err := utils3.GenerateJWTToken(user.Email, user.FullName, user.ID, tenantData.Domain, tenantData.ID)
+	tokens, err := service.StartSession(db, user, tenant, ctx.Request.UserAgent(), ctx.ClientIP())
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{"access_token": token, "token_type": "bearer"})
+	utils3.MarkSessionIssued(ctx)
+	ctx.JSON(http.StatusOK, tokens)
 	log.Println("Login successful")
 }
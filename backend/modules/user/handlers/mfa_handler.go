@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	utils2 "backend/internal/services/utils"
+	"backend/modules/user/models"
+	"backend/modules/user/repository"
+	"backend/modules/user/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func respondMFAError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidMFACode):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMFAAlreadyEnabled), errors.Is(err, service.ErrMFANotEnabled),
+		errors.Is(err, service.ErrMFASetupNotStarted):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrMFARequired):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case err.Error() == "current password is incorrect":
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// mfaIssuer — назва, під якою код з'явиться в застосунку-автентифікаторі
+func mfaIssuer(ctx *gin.Context) string {
+	if brand := utils2.GetTenantSettingsFromContext(ctx).BrandName; brand != "" {
+		return brand
+	}
+	if tenant, ok := utils2.GetTenantFromContext(ctx); ok {
+		return tenant.Domain
+	}
+	return "admin-go-panel"
+}
+
+// userFromMFAToken перевіряє проміжний токен входу і повертає його користувача
+func userFromMFAToken(ctx *gin.Context, token string) (*models.User, bool) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Database not found in context"})
+		return nil, false
+	}
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return nil, false
+	}
+
+	claims, err := utils2.ParseMFAToken(token)
+	if err != nil || claims.TenantID != tenant.ID {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa token"})
+		return nil, false
+	}
+
+	user, err := repository.GetUserByIdFull(db, claims.ID)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired mfa token"})
+		return nil, false
+	}
+	// Акаунт могли деактивувати між першим і другим кроком входу
+	if !user.IsActive {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Account is deactivated"})
+		return nil, false
+	}
+	return user, true
+}
+
+// VerifyMFALoginHandler — другий крок входу: код з автентифікатора або код відновлення
+func VerifyMFALoginHandler(ctx *gin.Context) {
+	var req models.MFAVerifyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || (req.Code == "" && req.RecoveryCode == "") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "mfa_token and code or recovery_code are required"})
+		return
+	}
+
+	user, ok := userFromMFAToken(ctx, req.MFAToken)
+	if !ok {
+		return
+	}
+	db, _ := utils2.GetDBFromContext(ctx)
+	tenant, _ := utils2.GetTenantFromContext(ctx)
+
+	if err := service.VerifySecondFactor(db, user.ID, req.Code, req.RecoveryCode); err != nil {
+		respondMFAError(ctx, err)
+		return
+	}
+
+	tokens, err := service.StartSession(db, user, tenant, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+	utils2.MarkSessionIssued(ctx)
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+// BeginMFAEnrollmentHandler — налаштування 2FA під час входу, коли тентант її вимагає
+func BeginMFAEnrollmentHandler(ctx *gin.Context) {
+	var req models.MFAEnrollRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "mfa_token is required"})
+		return
+	}
+
+	user, ok := userFromMFAToken(ctx, req.MFAToken)
+	if !ok {
+		return
+	}
+	db, _ := utils2.GetDBFromContext(ctx)
+
+	setup, err := service.BeginMFASetup(db, user, mfaIssuer(ctx))
+	if err != nil {
+		respondMFAError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, setup)
+}
+
+func ConfirmMFAEnrollmentHandler(ctx *gin.Context) {
+	var req models.MFAVerifyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "mfa_token and code are required"})
+		return
+	}
+
+	user, ok := userFromMFAToken(ctx, req.MFAToken)
+	if !ok {
+		return
+	}
+	db, _ := utils2.GetDBFromContext(ctx)
+	tenant, _ := utils2.GetTenantFromContext(ctx)
+
+	codes, err := service.ConfirmMFASetup(db, user.ID, req.Code)
+	if err != nil {
+		respondMFAError(ctx, err)
+		return
+	}
+
+	tokens, err := service.StartSession(db, user, tenant, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+	utils2.MarkSessionIssued(ctx)
+	ctx.JSON(http.StatusOK, models.MFAEnrollmentCompleteResponse{TokenResponse: *tokens, RecoveryCodes: codes})
+}
+
+func GetMFAStatusHandler(ctx *gin.Context) {
+	userID, ok := utils2.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	status, err := service.GetMFAStatus(db, userID, utils2.GetTenantSettingsFromContext(ctx).Require2FA)
+	if err != nil {
+		respondMFAError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, status)
+}
+
+func SetupMFAHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	user, ok := utils2.GetCurrentUserFromContext(ctx, db)
+	if !ok {
+		return
+	}
+
+	setup, err := service.BeginMFASetup(db, user, mfaIssuer(ctx))
+	if err != nil {
+		respondMFAError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, setup)
+}
+
+func ConfirmMFAHandler(ctx *gin.Context) {
+	userID, ok := utils2.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	codes, err := service.ConfirmMFASetup(db, userID, req.Code)
+	if err != nil {
+		respondMFAError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, models.MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func DisableMFAHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	user, ok := utils2.GetCurrentUserFromContext(ctx, db)
+	if !ok {
+		return
+	}
+
+	var req models.MFADisableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "password and code are required"})
+		return
+	}
+
+	required := utils2.GetTenantSettingsFromContext(ctx).Require2FA
+	if err := service.DisableMFA(db, user, req.Password, req.Code, required); err != nil {
+		respondMFAError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+func RegenerateRecoveryCodesHandler(ctx *gin.Context) {
+	userID, ok := utils2.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.MFACodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	codes, err := service.RegenerateRecoveryCodes(db, userID, req.Code)
+	if err != nil {
+		respondMFAError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, models.MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
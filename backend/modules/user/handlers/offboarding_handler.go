@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	utils2 "backend/internal/services/utils"
+	roleModels "backend/modules/role/models"
+	roleRepository "backend/modules/role/repository"
+	"backend/modules/user/models"
+	"backend/modules/user/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+func respondOffboardingError(ctx *gin.Context, err error) {
+	switch {
+	case err.Error() == "user not found":
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, service.ErrInvalidTransferTarget):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserOwnsContent), err.Error() == "user has not accepted the invitation yet":
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// rejectSuperuserTarget не дає прибрати власника тентанта: спершу треба зняти роль з "*"
+func rejectSuperuserTarget(ctx *gin.Context, db *gorm.DB, id uuid.UUID, message string) bool {
+	permissions, err := roleRepository.GetUserPermissions(db, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return true
+	}
+	if roleModels.HasPermission(permissions, roleModels.PermAll) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": message})
+		return true
+	}
+	return false
+}
+
+// offboardingTarget розбирає :id і відсікає дії над собою та над власником
+func offboardingTarget(ctx *gin.Context) (*gorm.DB, uuid.UUID, bool) {
+	userID, ok := utils2.GetUserIDFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, uuid.Nil, false
+	}
+	if id == userID {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Cannot deactivate yourself"})
+		return nil, uuid.Nil, false
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+	if rejectSuperuserTarget(ctx, db, id, "Cannot deactivate a superuser") {
+		return nil, uuid.Nil, false
+	}
+	return db, id, true
+}
+
+func DeactivateUserHandler(ctx *gin.Context) {
+	db, id, ok := offboardingTarget(ctx)
+	if !ok {
+		return
+	}
+	if err := service.DeactivateUser(db, id); err != nil {
+		respondOffboardingError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "User deactivated"})
+}
+
+func ReactivateUserHandler(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := service.ReactivateUser(db, id); err != nil {
+		respondOffboardingError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"message": "User reactivated"})
+}
+
+// OffboardUserHandler передає вміст користувача іншому і деактивує або видаляє акаунт
+func OffboardUserHandler(ctx *gin.Context) {
+	var req models.OffboardUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	db, id, ok := offboardingTarget(ctx)
+	if !ok {
+		return
+	}
+	response, err := service.OffboardUser(db, id, req)
+	if err != nil {
+		respondOffboardingError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, response)
+}
@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"backend/internal/db/postgres"
 	utils2 "backend/internal/services/utils"
 	"backend/modules/user/models"
-	"backend/modules/user/repository"
 	"backend/modules/user/service"
+	"errors"
 	"github.com/gin-gonic/gin"
+	"log"
 	"net/http"
 )
 
@@ -15,6 +17,11 @@ func UpdatePasswordCurrentUser(ctx *gin.Context) {
 		return
 	}
 
+	sessionID, ok := utils2.GetSessionIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
 	db, ok := utils2.GetDBFromContext(ctx)
 	if !ok {
 		return
@@ -25,7 +32,7 @@ func UpdatePasswordCurrentUser(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	message, err := service.UpdateCurrentUserPassword(db, userID, &updatePassword)
+	message, err := service.UpdateCurrentUserPassword(db, userID, sessionID, &updatePassword)
 	if err != nil {
 		if err.Error() == "user not found" {
 			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
@@ -46,28 +53,34 @@ func RequestPasswordRecover(ctx *gin.Context) {
 	if !ok {
 		return
 	}
-
-	// Перевірка чи існує користувач з таким email
-	user, err := repository.GetUserByEmail(db, email)
-	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		return
-	}
-
-	// Генерація токена для відновлення пароля
-	resetToken, err := utils2.GenerateResetToken(user.Email)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate reset token"})
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
 		return
 	}
+	settings := utils2.GetTenantSettingsFromContext(ctx)
+	ip := ctx.ClientIP()
+
+	// Контекст з даними запиту для журналу аудиту
+	auditCtx := db.Statement.Context
+
+	// Лист надсилаємо у фоні, а відповідаємо однаково для будь-якого email,
+	// щоб за відповіддю чи її часом не можна було перевірити наявність акаунта.
+	// Пул запиту звільняється разом із запитом, тож горутина закріплює свій.
+	go func() {
+		tenantDB, release, err := postgres.Manager.AcquireConnectionByDomain(tenant.Domain)
+		if err != nil {
+			log.Printf("❌ Password reset for tenant %s failed: %v", tenant.Domain, err)
+			return
+		}
+		defer release()
 
-	// Надсилання листа відновлення пароля
-	if err := utils2.SendPasswordResetEmail(user.Email, resetToken); err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send reset email"})
-		return
-	}
+		if err := service.RequestPasswordReset(tenantDB.WithContext(auditCtx), email, tenant, &settings, ip); err != nil {
+			log.Printf("❌ Password reset for tenant %s failed: %v", tenant.Domain, err)
+		}
+	}()
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Password reset email sent successfully"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "If the account exists, a password reset email has been sent"})
 }
 
 func ResetPassword(ctx *gin.Context) {
@@ -88,17 +101,16 @@ func ResetPassword(ctx *gin.Context) {
 		return
 	}
 
-	// Перевірка токена
-	claims, err := utils2.VerifyResetToken(req.Token)
-	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Зміна пароля
-	_, err = service.ResetCurrentUserPassword(db, claims.Email, req.NewPassword)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Токен одноразовий: після успішного скидання повторно не спрацює
+	if err := service.ResetPasswordWithToken(db, req.Token, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidResetToken):
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case err.Error() == "new password is required":
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
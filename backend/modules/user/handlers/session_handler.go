@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	utils2 "backend/internal/services/utils"
+	"backend/modules/user/models"
+	"backend/modules/user/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func RefreshTokenHandler(ctx *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "refresh_token is required"})
+		return
+	}
+
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Database not found in context"})
+		return
+	}
+
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+
+	require2FA := utils2.GetTenantSettingsFromContext(ctx).Require2FA
+	tokens, err := service.RefreshSession(db, req.RefreshToken, tenant, require2FA)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) ||
+			errors.Is(err, service.ErrMFAEnrollmentRequired) {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tokens)
+}
+
+func LogoutHandler(ctx *gin.Context) {
+	sessionID, ok := utils2.GetSessionIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Database not found in context"})
+		return
+	}
+
+	if err := service.EndSession(db, sessionID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+func LogoutAllHandler(ctx *gin.Context) {
+	userID, ok := utils2.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Database not found in context"})
+		return
+	}
+
+	if err := service.EndAllSessions(db, userID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Logged out from all sessions"})
+}
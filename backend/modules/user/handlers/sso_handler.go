@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"backend/internal/services/metering"
+	"backend/internal/services/oidc"
+	utils2 "backend/internal/services/utils"
+	"backend/modules/user/models"
+	"backend/modules/user/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+)
+
+func respondSSOError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSSONotConfigured):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidSSOState), errors.Is(err, oidc.ErrInvalidIDToken), errors.Is(err, oidc.ErrNonceMismatch):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrSSOUserNotFound), errors.Is(err, service.ErrSSODomainNotAllowed),
+		errors.Is(err, service.ErrUserInactive), errors.Is(err, metering.ErrQuotaExceeded):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		log.Println("❌ SSO login failed:", err)
+		ctx.JSON(http.StatusBadGateway, gin.H{"error": "SSO login failed"})
+	}
+}
+
+// ssoStateCookie прив'язує state до браузера, який почав вхід
+const ssoStateCookie = "sso_state"
+
+// setSSOStateCookie ставить (або видаляє при maxAge < 0) cookie зі state. Фронтенд
+// може жити на іншому домені, ніж API, тому через https cookie йде з SameSite=None.
+func setSSOStateCookie(ctx *gin.Context, state string, maxAge int) {
+	secure := ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https"
+	if secure {
+		ctx.SetSameSite(http.SameSiteNoneMode)
+	} else {
+		ctx.SetSameSite(http.SameSiteLaxMode)
+	}
+	ctx.SetCookie(ssoStateCookie, state, maxAge, "/v1/login/sso", "", secure, true)
+}
+
+// LoginMethodsHandler — публічний: сторінка входу дізнається, чи показувати SSO і форму пароля
+func LoginMethodsHandler(ctx *gin.Context) {
+	settings := utils2.GetTenantSettingsFromContext(ctx)
+	ctx.JSON(http.StatusOK, models.LoginMethodsResponse{
+		Password: !settings.PasswordLoginDisabled,
+		SSO:      settings.SSOConfigured(),
+	})
+}
+
+func StartSSOLoginHandler(ctx *gin.Context) {
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+	settings := utils2.GetTenantSettingsFromContext(ctx)
+
+	authURL, state, err := service.BeginSSOLogin(ctx.Request.Context(), db, tenant, &settings)
+	if err != nil {
+		respondSSOError(ctx, err)
+		return
+	}
+	setSSOStateCookie(ctx, state, int(service.SSOStateTTL.Seconds()))
+	ctx.JSON(http.StatusOK, models.SSOStartResponse{AuthorizationURL: authURL})
+}
+
+// SSOCallbackHandler приймає code і state, які фронтенд отримав від IdP.
+// Другий фактор тут не запитуємо — за автентифікацію відповідає IdP.
+func SSOCallbackHandler(ctx *gin.Context) {
+	var req models.SSOCallbackRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		return
+	}
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+	settings := utils2.GetTenantSettingsFromContext(ctx)
+
+	browserState, _ := ctx.Cookie(ssoStateCookie)
+	setSSOStateCookie(ctx, "", -1)
+	user, err := service.CompleteSSOLogin(ctx.Request.Context(), db, tenant, &settings, req.Code, req.State, browserState)
+	if err != nil {
+		respondSSOError(ctx, err)
+		return
+	}
+
+	tokens, err := service.StartSession(db, user, tenant, ctx.Request.UserAgent(), ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate token"})
+		return
+	}
+	ctx.JSON(http.StatusOK, tokens)
+	log.Println("SSO login successful")
+}
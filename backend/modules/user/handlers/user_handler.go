@@ -1,15 +1,18 @@
 package handlers
 
 import (
+	"backend/internal/services/metering"
 	utils2 "backend/internal/services/utils"
+	roleModels "backend/modules/role/models"
 	"backend/modules/user/models"
 	"backend/modules/user/repository"
 	"backend/modules/user/service"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 	"net/http"
-	"strconv"
 	"time"
 )
 
@@ -36,9 +39,30 @@ func CreateUser(ctx *gin.Context) {
 		return
 	}
 
-	// Створюємо нового користувача
-	newUser, err := repository.CreateUser(db, userModel, currentUser.ID, currentUser.Acronym)
+	// Прапорці IsSuperUser/IsAdmin перетворюються на системні ролі,
+	// тож задавати їх може лише той, хто керує ролями
+	if (userModel.IsSuperUser || userModel.IsAdmin) && !utils2.HasPermission(ctx, db, roleModels.PermRolesManage) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Permission denied: " + roleModels.PermRolesManage})
+		return
+	}
+
+	// Створюємо нового користувача; ліміт місць тарифного плану перевіряється в тій самій транзакції
+	var newUser *models.UserResponse
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if tenant, ok := utils2.GetTenantFromContext(ctx); ok {
+			if err := metering.ReserveSeats(tx, tenant.ID, 1); err != nil {
+				return err
+			}
+		}
+		var err error
+		newUser, err = repository.CreateUser(tx, userModel, currentUser.ID, currentUser.Acronym)
+		return err
+	})
 	if err != nil {
+		if errors.Is(err, metering.ErrQuotaExceeded) {
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -71,6 +95,7 @@ func ReadUserMe(ctx *gin.Context) {
 		IsAdmin:     user.IsAdmin,
 		Acronym:     user.Acronym,
 		LastSeenAt:  user.LastSeenAt,
+		Status:      user.Status(),
 	}
 	ctx.JSON(http.StatusOK, response)
 }
@@ -101,33 +126,25 @@ func ReadUserById(ctx *gin.Context) {
 }
 
 func ReadAllUsers(ctx *gin.Context) {
-	limitParam := ctx.DefaultQuery("limit", "100")
-	skipParam := ctx.DefaultQuery("skip", "0")
-
-	limit, err := strconv.Atoi(limitParam)
-	if err != nil || limit < 0 {
-		limit = 100
-	}
-
-	skip, err := strconv.Atoi(skipParam)
-	if err != nil || skip < 0 {
-		skip = 0
+	var filter models.UserFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter: " + err.Error()})
+		return
 	}
 	db, ok := utils2.GetDBFromContext(ctx)
 	if !ok {
 		return
 	}
 
-	users, err := repository.GetAllUsers(db, limit, skip)
+	response, err := service.ListUsers(db, filter)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidUserSort) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	userResponses := service.TransformUsers(users)
-	response := models.AllUsers{
-		Data:  userResponses,
-		Count: len(userResponses),
-	}
 	ctx.JSON(http.StatusOK, response)
 }
 
@@ -179,35 +196,33 @@ func DeleteUser(ctx *gin.Context) {
 		return
 	}
 
-	isSuperUser, err := utils2.GetIsSuperUser(db, userID)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	isTargetSuperUser, err := utils2.GetIsSuperUser(db, id)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if rejectSuperuserTarget(ctx, db, id, "Cannot delete a superuser") {
 		return
 	}
 
-	if isTargetSuperUser && isSuperUser {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "Cannot delete a superuser"})
+	// Видалити себе можна лише з інтерактивної сесії, не API-ключем
+	if id == userID && !requireSession(ctx) {
 		return
 	}
-
-	if !isSuperUser && id != userID {
+	if id != userID && !utils2.HasPermission(ctx, db, roleModels.PermUsersManage) {
 		ctx.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized to delete this user"})
 		return
 	}
 
-	err = repository.DeleteUserById(db, id)
-	if err != nil {
-		if err.Error() == "user not found" {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-		} else {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Вміст користувача (блоги, товари, кімнати, календар) передається вказаному
+	// користувачу; без transferTo видалити можна лише того, кому нічого не належить
+	var transferTo *uuid.UUID
+	if raw := ctx.Query("transferTo"); raw != "" {
+		target, err := uuid.Parse(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transferTo user ID"})
+			return
 		}
+		transferTo = &target
+	}
+
+	if err := service.DeleteUser(db, id, transferTo); err != nil {
+		respondOffboardingError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
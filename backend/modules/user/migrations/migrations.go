@@ -0,0 +1,241 @@
+package migrations
+
+import (
+	"backend/internal/db/migrator"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+var Migrations = []migrator.Migration{
+	{
+		Module:  "user",
+		Version: 1,
+		Name:    "create_users",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&userV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&userV1{})
+		},
+	},
+	{
+		Module:  "user",
+		Version: 2,
+		Name:    "create_user_sessions",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&userSessionV2{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&userSessionV2{})
+		},
+	},
+	{
+		Module:  "user",
+		Version: 3,
+		Name:    "create_user_totp",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&userTOTPV3{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&userTOTPV3{})
+		},
+	},
+	{
+		Module:  "user",
+		Version: 4,
+		Name:    "create_password_reset_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&passwordResetTokenV4{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&passwordResetTokenV4{})
+		},
+	},
+	{
+		Module:  "user",
+		Version: 5,
+		Name:    "add_users_invited_at",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&userInvitedAtV5{}, "InvitedAt") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&userInvitedAtV5{}, "InvitedAt")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&userInvitedAtV5{}, "InvitedAt")
+		},
+	},
+	{
+		Module:  "user",
+		Version: 6,
+		Name:    "add_users_is_service_account",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&userServiceAccountV6{}, "IsServiceAccount") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&userServiceAccountV6{}, "IsServiceAccount")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&userServiceAccountV6{}, "IsServiceAccount")
+		},
+	},
+	{
+		Module:  "user",
+		Version: 7,
+		Name:    "create_api_keys",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&apiKeyV7{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&apiKeyV7{})
+		},
+	},
+	{
+		Module:  "user",
+		Version: 8,
+		Name:    "create_sso_tables",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&userIdentityV8{}, &ssoLoginStateV8{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&ssoLoginStateV8{}, &userIdentityV8{})
+		},
+	},
+}
+
+// Знімки схеми на момент кожної міграції: зміни моделей не змінюють уже
+// застосовані міграції. Суфікс — версія міграції, що створює таблицю чи колонку.
+
+type userV1 struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	FullName    string    `gorm:"not null"`
+	Avatar      string    `gorm:"default:null"`
+	Email       string    `gorm:"unique;not null"`
+	Password    string    `gorm:"not null"`
+	IsActive    bool      `gorm:"default:true"`
+	IsAdmin     bool      `gorm:"default:false"`
+	IsSuperUser bool      `gorm:"default:false"`
+	Acronym     string    `gorm:"unique;default:null"`
+
+	LastSeenAt *time.Time `gorm:"default:null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (userV1) TableName() string {
+	return "users"
+}
+
+type userSessionV2 struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID            uuid.UUID `gorm:"type:uuid;not null;index"`
+	RefreshTokenHash  string    `gorm:"not null;uniqueIndex"`
+	PreviousTokenHash string    `gorm:"default:null;index"`
+	UserAgent         string    `gorm:"default:null"`
+	IP                string    `gorm:"default:null"`
+	ExpiresAt         time.Time `gorm:"not null"`
+	LastUsedAt        time.Time
+	RevokedAt         *time.Time `gorm:"default:null"`
+
+	CreatedAt time.Time
+}
+
+func (userSessionV2) TableName() string {
+	return "user_sessions"
+}
+
+type userTOTPV3 struct {
+	UserID        uuid.UUID                   `gorm:"type:uuid;primaryKey"`
+	Secret        string                      `gorm:"not null"`
+	Enabled       bool                        `gorm:"default:false"`
+	LastUsedStep  int64                       `gorm:"default:0"`
+	RecoveryCodes datatypes.JSONSlice[string] `gorm:"type:jsonb"`
+	ConfirmedAt   *time.Time                  `gorm:"default:null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (userTOTPV3) TableName() string {
+	return "user_totp"
+}
+
+type passwordResetTokenV4 struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	TokenHash string     `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time `gorm:"default:null"`
+	RequestIP string     `gorm:"default:null"`
+
+	CreatedAt time.Time
+}
+
+func (passwordResetTokenV4) TableName() string {
+	return "password_reset_tokens"
+}
+
+type userInvitedAtV5 struct {
+	InvitedAt *time.Time `gorm:"default:null"`
+}
+
+func (userInvitedAtV5) TableName() string {
+	return "users"
+}
+
+type userServiceAccountV6 struct {
+	IsServiceAccount bool `gorm:"default:false"`
+}
+
+func (userServiceAccountV6) TableName() string {
+	return "users"
+}
+
+type apiKeyV7 struct {
+	ID          uuid.UUID                   `gorm:"type:uuid;primaryKey"`
+	UserID      uuid.UUID                   `gorm:"type:uuid;not null;index"`
+	Name        string                      `gorm:"not null"`
+	KeyHash     string                      `gorm:"not null;uniqueIndex"`
+	Hint        string                      `gorm:"not null"`
+	Scopes      datatypes.JSONSlice[string] `gorm:"type:jsonb;not null"`
+	CreatedByID uuid.UUID                   `gorm:"type:uuid;not null"`
+	ExpiresAt   *time.Time                  `gorm:"default:null"`
+	LastUsedAt  *time.Time                  `gorm:"default:null"`
+	LastUsedIP  string                      `gorm:"default:null"`
+	RevokedAt   *time.Time                  `gorm:"default:null"`
+	CreatedAt   time.Time
+}
+
+func (apiKeyV7) TableName() string {
+	return "api_keys"
+}
+
+type userIdentityV8 struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	Issuer      string    `gorm:"not null;uniqueIndex:idx_identity_issuer_subject"`
+	Subject     string    `gorm:"not null;uniqueIndex:idx_identity_issuer_subject"`
+	Email       string
+	LastLoginAt *time.Time `gorm:"default:null"`
+	CreatedAt   time.Time
+}
+
+func (userIdentityV8) TableName() string {
+	return "user_identities"
+}
+
+type ssoLoginStateV8 struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	StateHash    string    `gorm:"not null;uniqueIndex"`
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+func (ssoLoginStateV8) TableName() string {
+	return "sso_login_states"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"time"
+)
+
+// APIKeyPrefix відрізняє API-ключі від JWT у заголовку Authorization
+const APIKeyPrefix = "agp_"
+
+// APIKey — персональний токен користувача або ключ сервісного акаунта.
+// Зберігається лише хеш; Scopes обмежують дозволи власника.
+type APIKey struct {
+	ID          uuid.UUID                   `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      uuid.UUID                   `gorm:"type:uuid;not null;index" json:"userId"`
+	Name        string                      `gorm:"not null" json:"name"`
+	KeyHash     string                      `gorm:"not null;uniqueIndex" json:"-"`
+	Hint        string                      `gorm:"not null" json:"hint"`
+	Scopes      datatypes.JSONSlice[string] `gorm:"type:jsonb;not null" json:"scopes"`
+	CreatedByID uuid.UUID                   `gorm:"type:uuid;not null" json:"createdById"`
+	ExpiresAt   *time.Time                  `gorm:"default:null" json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time                  `gorm:"default:null" json:"lastUsedAt,omitempty"`
+	LastUsedIP  string                      `gorm:"default:null" json:"lastUsedIp,omitempty"`
+	RevokedAt   *time.Time                  `gorm:"default:null" json:"revokedAt,omitempty"`
+	CreatedAt   time.Time                   `json:"createdAt"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+func (k *APIKey) BeforeCreate(*gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
@@ -16,11 +16,39 @@ type UserResponse struct {
 	IsAdmin     bool       `json:"isAdmin"`
 	Acronym     string     `json:"acronym"`
 	LastSeenAt  *time.Time `json:"lastSeenAt,omitempty"`
+	Status      string     `json:"status"`
+	// Presence — online/away/offline з живих підключень; заповнюється лише там, де потрібен
+	Presence string `json:"presence,omitempty"`
+	// Поля працівника — лише в довіднику користувачів
+	Position string `json:"position,omitempty"`
+	Company  string `json:"company,omitempty"`
 }
 
+// AllUsers — сторінка довідника; Count — загальна кількість за фільтром, а не розмір сторінки
 type AllUsers struct {
 	Data  []*UserResponse `json:"data"`
-	Count int             `json:"count"`
+	Count int64           `json:"count"`
+}
+
+// UserFilter — пошук, фільтри й сортування довідника користувачів (query string)
+type UserFilter struct {
+	Query        string     `form:"q"`
+	IsActive     *bool      `form:"isActive"`
+	IsAdmin      *bool      `form:"isAdmin"`
+	IsSuperUser  *bool      `form:"isSuperUser"`
+	LastSeenFrom *time.Time `form:"lastSeenFrom" time_format:"2006-01-02T15:04:05Z07:00"`
+	LastSeenTo   *time.Time `form:"lastSeenTo" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort         string     `form:"sort"`
+	Order        string     `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit        int        `form:"limit"`
+	Skip         int        `form:"skip"`
+}
+
+// UserDirectoryRow — користувач разом з полями його запису працівника
+type UserDirectoryRow struct {
+	User
+	Position *string
+	Company  *string
 }
 
 type UpdateUser struct {
@@ -44,3 +72,142 @@ type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// MFAChallengeResponse повертається замість токенів, коли після пароля
+// потрібен другий фактор або його налаштування
+type MFAChallengeResponse struct {
+	MFARequired        bool   `json:"mfa_required"`
+	EnrollmentRequired bool   `json:"enrollment_required"`
+	MFAToken           string `json:"mfa_token"`
+	TokenType          string `json:"token_type"`
+	ExpiresIn          int    `json:"expires_in"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type MFAEnrollRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFADisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type MFASetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+type MFAStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	Required               bool       `json:"required"`
+	ConfirmedAt            *time.Time `json:"confirmedAt,omitempty"`
+	RecoveryCodesRemaining int        `json:"recoveryCodesRemaining"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAEnrollmentCompleteResponse — коди відновлення і токени після
+// обов'язкового налаштування 2FA під час входу
+type MFAEnrollmentCompleteResponse struct {
+	TokenResponse
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+	// 0 — ключ без терміну дії
+	ExpiresInDays *int `json:"expiresInDays"`
+}
+
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"userId"`
+	Name       string     `json:"name"`
+	Hint       string     `json:"hint"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIP string     `json:"lastUsedIp,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// CreatedAPIKeyResponse — ключ у відкритому вигляді показується лише раз
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type CreateServiceAccountRequest struct {
+	Name   string     `json:"name" binding:"required"`
+	RoleID *uuid.UUID `json:"roleId"`
+}
+
+type ServiceAccountResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	IsActive  bool      `json:"isActive"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// LoginMethodsResponse — які способи входу показувати на сторінці логіну
+type LoginMethodsResponse struct {
+	Password bool `json:"password"`
+	SSO      bool `json:"sso"`
+}
+
+type SSOStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type SSOCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// OwnedContent — записи інших модулів, власником яких є користувач
+type OwnedContent struct {
+	Blogs          int64 `json:"blogs"`
+	Items          int64 `json:"items"`
+	Rooms          int64 `json:"rooms"`
+	CalendarEvents int64 `json:"calendarEvents"`
+}
+
+func (content OwnedContent) Total() int64 {
+	return content.Blogs + content.Items + content.Rooms + content.CalendarEvents
+}
+
+// OffboardUserRequest — кому передати вміст і чи видаляти акаунт після передачі
+type OffboardUserRequest struct {
+	TransferTo uuid.UUID `json:"transferTo" binding:"required"`
+	Delete     bool      `json:"delete"`
+}
+
+type OffboardUserResponse struct {
+	Transferred OwnedContent `json:"transferred"`
+	Deleted     bool         `json:"deleted"`
+}
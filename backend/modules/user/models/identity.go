@@ -0,0 +1,43 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserIdentity пов'язує користувача з обліковим записом у зовнішньому IdP
+type UserIdentity struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	Issuer      string     `gorm:"not null;uniqueIndex:idx_identity_issuer_subject" json:"issuer"`
+	Subject     string     `gorm:"not null;uniqueIndex:idx_identity_issuer_subject" json:"subject"`
+	Email       string     `json:"email"`
+	LastLoginAt *time.Time `gorm:"default:null" json:"lastLoginAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+func (identity *UserIdentity) BeforeCreate(*gorm.DB) error {
+	if identity.ID == uuid.Nil {
+		identity.ID = uuid.New()
+	}
+	return nil
+}
+
+// SSOLoginState — незавершений вхід через IdP: state з редиректу, nonce
+// для ID-токена і PKCE code_verifier. Використовується один раз.
+type SSOLoginState struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	StateHash    string    `gorm:"not null;uniqueIndex"`
+	Nonce        string    `gorm:"not null"`
+	CodeVerifier string    `gorm:"not null"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+func (state *SSOLoginState) BeforeCreate(*gorm.DB) error {
+	if state.ID == uuid.Nil {
+		state.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// PasswordResetToken — одноразовий токен скидання пароля; у БД лише sha256-хеш
+type PasswordResetToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	TokenHash string     `gorm:"not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expiresAt"`
+	UsedAt    *time.Time `gorm:"default:null" json:"usedAt,omitempty"`
+	RequestIP string     `gorm:"default:null" json:"requestIp"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (t *PasswordResetToken) BeforeCreate(*gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
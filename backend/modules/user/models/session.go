@@ -0,0 +1,31 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserSession — сесія входу. ID сесії потрапляє в access-токен як "sid",
+// а refresh-токен зберігається лише у вигляді sha256-хешу і міняється
+// при кожному оновленні.
+type UserSession struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID            uuid.UUID  `gorm:"type:uuid;not null;index" json:"userId"`
+	RefreshTokenHash  string     `gorm:"not null;uniqueIndex" json:"-"`
+	PreviousTokenHash string     `gorm:"default:null;index" json:"-"`
+	UserAgent         string     `gorm:"default:null" json:"userAgent"`
+	IP                string     `gorm:"default:null" json:"ip"`
+	ExpiresAt         time.Time  `gorm:"not null" json:"expiresAt"`
+	LastUsedAt        time.Time  `json:"lastUsedAt"`
+	RevokedAt         *time.Time `gorm:"default:null" json:"revokedAt,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (s *UserSession) BeforeCreate(*gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
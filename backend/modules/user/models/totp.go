@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"time"
+)
+
+// UserTOTP — другий фактор користувача. Секрет зашифрований ключем тентантів,
+// коди відновлення зберігаються лише як sha256-хеші.
+type UserTOTP struct {
+	UserID        uuid.UUID                   `gorm:"type:uuid;primaryKey" json:"userId"`
+	Secret        string                      `gorm:"not null" json:"-"`
+	Enabled       bool                        `gorm:"default:false" json:"enabled"`
+	LastUsedStep  int64                       `gorm:"default:0" json:"-"`
+	RecoveryCodes datatypes.JSONSlice[string] `gorm:"type:jsonb" json:"-"`
+	ConfirmedAt   *time.Time                  `gorm:"default:null" json:"confirmedAt,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (UserTOTP) TableName() string {
+	return "user_totp"
+}
@@ -18,11 +18,32 @@ type User struct {
 	Acronym     string    `gorm:"unique;default:null" json:"acronym"`
 
 	LastSeenAt *time.Time `gorm:"default:null" json:"lastSeenAt,omitempty"`
+	// InvitedAt заповнений, поки користувач не прийняв запрошення
+	InvitedAt *time.Time `gorm:"default:null" json:"invitedAt,omitempty"`
+	// Сервісний акаунт не входить паролем, лише через API-ключі
+	IsServiceAccount bool `gorm:"default:false" json:"isServiceAccount"`
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+const (
+	UserStatusActive   = "active"
+	UserStatusInvited  = "invited"
+	UserStatusInactive = "inactive"
+)
+
+func (user *User) Status() string {
+	switch {
+	case user.InvitedAt != nil:
+		return UserStatusInvited
+	case !user.IsActive:
+		return UserStatusInactive
+	default:
+		return UserStatusActive
+	}
+}
+
 func (user *User) BeforeCreate(*gorm.DB) error {
 	if user.ID == uuid.Nil {
 		user.ID = uuid.New()
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"backend/modules/user/models"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+func CreateAPIKey(db *gorm.DB, key *models.APIKey) error {
+	return db.Create(key).Error
+}
+
+func GetAPIKeysByUser(db *gorm.DB, userID uuid.UUID) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// GetActiveAPIKey шукає чинний ключ за хешем; власник має бути активним
+func GetActiveAPIKey(db *gorm.DB, keyHash string) (*models.APIKey, *models.User, error) {
+	var key models.APIKey
+	err := db.Where("key_hash = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", keyHash, time.Now()).
+		First(&key).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var user models.User
+	err = db.Where("id = ? AND is_active = ? AND invited_at IS NULL", key.UserID, true).First(&user).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	return &key, &user, nil
+}
+
+func RevokeAPIKey(db *gorm.DB, userID, keyID uuid.UUID) error {
+	result := db.Model(&models.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("api key not found")
+	}
+	return nil
+}
+
+func RevokeUserAPIKeys(db *gorm.DB, userID uuid.UUID) error {
+	return db.Model(&models.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// TouchAPIKey оновлює час останнього використання не частіше, ніж раз на interval,
+// щоб кожен запит скрипта не перетворювався на запис у БД
+func TouchAPIKey(db *gorm.DB, id uuid.UUID, ip string, interval time.Duration) error {
+	now := time.Now()
+	return db.Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-interval)).
+		Updates(map[string]interface{}{
+			"last_used_at": now,
+			"last_used_ip": ip,
+		}).Error
+}
+
+func GetServiceAccounts(db *gorm.DB) ([]models.User, error) {
+	var users []models.User
+	if err := db.Where("is_service_account = ?", true).Order("created_at").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func GetServiceAccountByID(db *gorm.DB, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := db.Where("id = ? AND is_service_account = ?", id, true).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("service account not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
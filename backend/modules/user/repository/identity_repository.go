@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"backend/modules/user/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+func GetIdentity(db *gorm.DB, issuer, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := db.Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func CreateIdentity(db *gorm.DB, identity *models.UserIdentity) error {
+	return db.Create(identity).Error
+}
+
+func TouchIdentity(db *gorm.DB, id uuid.UUID, email string) error {
+	return db.Model(&models.UserIdentity{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":         email,
+		"last_login_at": time.Now(),
+	}).Error
+}
+
+func CreateSSOLoginState(db *gorm.DB, state *models.SSOLoginState) error {
+	// Заодно прибираємо прострочені спроби входу
+	if err := db.Where("expires_at < ?", time.Now()).Delete(&models.SSOLoginState{}).Error; err != nil {
+		return err
+	}
+	return db.Create(state).Error
+}
+
+// ConsumeSSOLoginState видаляє і повертає state; повторний виклик з тим самим state нічого не знайде
+func ConsumeSSOLoginState(db *gorm.DB, stateHash string) (*models.SSOLoginState, error) {
+	var states []models.SSOLoginState
+	err := db.Clauses(clause.Returning{}).
+		Where("state_hash = ? AND expires_at > ?", stateHash, time.Now()).
+		Delete(&states).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &states[0], nil
+}
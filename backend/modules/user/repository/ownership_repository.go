@@ -0,0 +1,47 @@
+package repository
+
+import (
+	blogModels "backend/modules/blog/models"
+	calendarModels "backend/modules/calendar/models"
+	roomModels "backend/modules/chat/rooms/models"
+	itemModels "backend/modules/item/models"
+	"backend/modules/user/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ownedTables — записи, які зникли б разом з користувачем (OnDelete:CASCADE
+// або осиротілий owner_id у кімнат), і колонка власника в кожному з них
+var ownedTables = []struct {
+	model  interface{}
+	column string
+	count  func(*models.OwnedContent) *int64
+}{
+	{&blogModels.Blog{}, "owner_id", func(c *models.OwnedContent) *int64 { return &c.Blogs }},
+	{&itemModels.Items{}, "owner_id", func(c *models.OwnedContent) *int64 { return &c.Items }},
+	{&roomModels.ChatRooms{}, "owner_id", func(c *models.OwnedContent) *int64 { return &c.Rooms }},
+	{&calendarModels.Calendar{}, "user_id", func(c *models.OwnedContent) *int64 { return &c.CalendarEvents }},
+}
+
+func CountOwnedContent(db *gorm.DB, userID uuid.UUID) (*models.OwnedContent, error) {
+	var content models.OwnedContent
+	for _, table := range ownedTables {
+		if err := db.Model(table.model).Where(table.column+" = ?", userID).Count(table.count(&content)).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &content, nil
+}
+
+// TransferOwnedContent передає весь вміст користувача fromID користувачу toID
+func TransferOwnedContent(db *gorm.DB, fromID, toID uuid.UUID) (*models.OwnedContent, error) {
+	var content models.OwnedContent
+	for _, table := range ownedTables {
+		result := db.Model(table.model).Where(table.column+" = ?", fromID).Update(table.column, toID)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		*table.count(&content) = result.RowsAffected
+	}
+	return &content, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"backend/modules/user/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+func CreateResetToken(db *gorm.DB, token *models.PasswordResetToken) error {
+	return db.Create(token).Error
+}
+
+// GetActiveResetTokenForUpdate блокує невикористаний і непрострочений токен до кінця транзакції
+func GetActiveResetTokenForUpdate(tx *gorm.DB, tokenHash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, time.Now()).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// InvalidateResetTokens позначає всі невикористані токени користувача як використані
+func InvalidateResetTokens(db *gorm.DB, userID uuid.UUID) error {
+	return db.Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
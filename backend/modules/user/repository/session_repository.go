@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"backend/modules/user/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+func CreateSession(db *gorm.DB, session *models.UserSession) error {
+	return db.Create(session).Error
+}
+
+func GetSessionByID(db *gorm.DB, id uuid.UUID) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := db.Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// RotateSessionToken замінює refresh-токен лише якщо в БД досі лежить
+// очікуваний хеш — з двох паралельних оновлень виграє одне.
+func RotateSessionToken(db *gorm.DB, id uuid.UUID, currentHash, newHash string, expiresAt time.Time) (bool, error) {
+	result := db.Model(&models.UserSession{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", id, currentHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  newHash,
+			"previous_token_hash": currentHash,
+			"expires_at":          expiresAt,
+			"last_used_at":        time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func RevokeSession(db *gorm.DB, id uuid.UUID) error {
+	return db.Model(&models.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserSessions відкликає всі активні сесії користувача
+func RevokeUserSessions(db *gorm.DB, userID uuid.UUID) error {
+	return db.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeOtherSessions відкликає всі сесії користувача, крім поточної
+func RevokeOtherSessions(db *gorm.DB, userID, keepID uuid.UUID) error {
+	return db.Model(&models.UserSession{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// IsSessionActive — сесія не відкликана, не прострочена, а користувач
+// існує й активний. Викликається на кожен автентифікований запит.
+func IsSessionActive(db *gorm.DB, sessionID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := db.Table("user_sessions AS s").
+		Joins("JOIN users u ON u.id = s.user_id").
+		Where("s.id = ? AND s.user_id = ? AND s.revoked_at IS NULL AND s.expires_at > ? AND u.is_active", sessionID, userID, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeleteStaleSessions прибирає сесії користувача, що закінчились
+// або відкликані давніше за before
+func DeleteStaleSessions(db *gorm.DB, userID uuid.UUID, before time.Time) error {
+	return db.Where("user_id = ? AND (expires_at < ? OR revoked_at < ?)", userID, before, before).
+		Delete(&models.UserSession{}).Error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"backend/modules/user/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func GetUserTOTP(db *gorm.DB, userID uuid.UUID) (*models.UserTOTP, error) {
+	var record models.UserTOTP
+	if err := db.Where("user_id = ?", userID).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func SaveUserTOTP(db *gorm.DB, record *models.UserTOTP) error {
+	return db.Save(record).Error
+}
+
+func DeleteUserTOTP(db *gorm.DB, userID uuid.UUID) error {
+	return db.Where("user_id = ?", userID).Delete(&models.UserTOTP{}).Error
+}
+
+// MarkTOTPStepUsed фіксує використаний крок лише якщо він новіший за збережений,
+// тож один код не пройде двічі навіть у паралельних запитах
+func MarkTOTPStepUsed(db *gorm.DB, userID uuid.UUID, step int64) (bool, error) {
+	result := db.Model(&models.UserTOTP{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
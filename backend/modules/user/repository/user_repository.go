@@ -3,12 +3,15 @@ package repository
 import (
 	"backend/internal/repository"
 	employees "backend/modules/employees/models"
+	roleModels "backend/modules/role/models"
+	roleRepository "backend/modules/role/repository"
 	"backend/modules/user/models"
 	"backend/modules/user/utils"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"strings"
 )
 
 func CreateUser(db *gorm.DB, user *models.User, createdByID uuid.UUID, createdByAcron string) (*models.UserResponse, error) {
@@ -39,7 +42,7 @@ func CreateUser(db *gorm.DB, user *models.User, createdByID uuid.UUID, createdBy
 			return err
 		}
 
-		return nil
+		return roleRepository.AssignFlagRoles(tx, user.ID, user.IsSuperUser, user.IsAdmin)
 	})
 	if err != nil {
 		return nil, err
@@ -52,15 +55,87 @@ func CreateUser(db *gorm.DB, user *models.User, createdByID uuid.UUID, createdBy
 		IsSuperUser: user.IsSuperUser,
 		IsAdmin:     user.IsAdmin,
 		Acronym:     user.Acronym,
+		Status:      user.Status(),
 	}, err
 }
 
-func GetAllUsers(db *gorm.DB, limit int, skip int) ([]*models.User, error) {
-	var users []*models.User
-	if err := db.Limit(limit).Offset(skip).Find(&users).Error; err != nil {
-		return nil, err
+// userSortColumns — колонки, за якими дозволено сортувати довідник
+var userSortColumns = map[string]string{
+	"fullName":   "users.full_name",
+	"email":      "users.email",
+	"acronym":    "users.acronym",
+	"lastSeenAt": "users.last_seen_at",
+	"createdAt":  "users.created_at",
+	"position":   "employees.position",
+	"company":    "employees.company",
+}
+
+func IsUserSortColumn(sort string) bool {
+	_, ok := userSortColumns[sort]
+	return ok
+}
+
+// likeEscaper екранує шаблонні символи LIKE: пошук "50%" чи "a_b" має шукати
+// саме ці символи, а не будь-що
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchUsers повертає сторінку довідника разом з загальною кількістю за фільтром
+func SearchUsers(db *gorm.DB, filter *models.UserFilter) ([]models.UserDirectoryRow, int64, error) {
+	// Сервісні акаунти мають окремий список
+	query := db.Model(&models.User{}).
+		Joins("LEFT JOIN employees ON employees.user_id = users.id").
+		Where("users.is_service_account = ?", false)
+	if filter.Query != "" {
+		like := "%" + likeEscaper.Replace(filter.Query) + "%"
+		query = query.Where(`users.full_name ILIKE ? ESCAPE '\' OR users.email ILIKE ? ESCAPE '\' OR users.acronym ILIKE ? ESCAPE '\'`,
+			like, like, like)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("users.is_active = ?", *filter.IsActive)
+	}
+	if filter.IsAdmin != nil {
+		query = query.Where("users.is_admin = ?", *filter.IsAdmin)
+	}
+	if filter.IsSuperUser != nil {
+		query = query.Where("users.is_super_user = ?", *filter.IsSuperUser)
+	}
+	if filter.LastSeenFrom != nil {
+		query = query.Where("users.last_seen_at >= ?", *filter.LastSeenFrom)
+	}
+	if filter.LastSeenTo != nil {
+		query = query.Where("users.last_seen_at < ?", *filter.LastSeenTo)
+	}
+
+	// Одна і та сама вибірка йде і на підрахунок, і на сторінку
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := userSortColumns[filter.Sort]
+	if !ok {
+		column = userSortColumns["fullName"]
+	}
+	direction := "ASC"
+	if filter.Order == "desc" {
+		direction = "DESC"
+	}
+	// id в кінці робить порядок стабільним між сторінками
+	order := fmt.Sprintf("%s %s NULLS LAST, users.id", column, direction)
+
+	var rows []models.UserDirectoryRow
+	err := query.
+		Select("users.*, employees.position, employees.company").
+		Order(order).
+		Limit(filter.Limit).
+		Offset(filter.Skip).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
 	}
-	return users, nil
+	return rows, total, nil
 }
 
 func GetUserById(db *gorm.DB, id uuid.UUID) (*models.UserResponse, error) {
@@ -82,6 +157,7 @@ func GetUserById(db *gorm.DB, id uuid.UUID) (*models.UserResponse, error) {
 		IsAdmin:     user.IsAdmin,
 		Acronym:     user.Acronym,
 		LastSeenAt:  user.LastSeenAt,
+		Status:      user.Status(),
 	}
 	return UserResponse, nil
 }
@@ -142,9 +218,22 @@ func UpdateUserById(db *gorm.DB, id uuid.UUID, updateUser *models.UpdateUser) (*
 		IsAdmin:     user.IsAdmin,
 		Acronym:     user.Acronym,
 		LastSeenAt:  user.LastSeenAt,
+		Status:      user.Status(),
 	}, nil
 }
 
+// SetUserActive вмикає або вимикає вхід користувача
+func SetUserActive(db *gorm.DB, id uuid.UUID, active bool) error {
+	result := db.Model(&models.User{}).Where("id = ?", id).Update("is_active", active)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
 func DeleteUserById(db *gorm.DB, id uuid.UUID) error {
 
 	err := repository.DeleteByID(db, id, &models.User{})
@@ -152,6 +241,7 @@ func DeleteUserById(db *gorm.DB, id uuid.UUID) error {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("user not found")
 		}
+		return err
 	}
 
 	err = repository.DeleteByUserID(db, id, &employees.Employees{})
@@ -159,6 +249,48 @@ func DeleteUserById(db *gorm.DB, id uuid.UUID) error {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("employees not found")
 		}
+		return err
+	}
+
+	// Сесії, другий фактор, токени скидання, API-ключі, зв'язки з IdP і ролі
+	// видаленого користувача більше не потрібні
+	if err := db.Where("user_id = ?", id).Delete(&models.UserSession{}).Error; err != nil {
+		return err
+	}
+	if err := DeleteUserTOTP(db, id); err != nil {
+		return err
+	}
+	if err := db.Where("user_id = ?", id).Delete(&models.PasswordResetToken{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("user_id = ?", id).Delete(&models.APIKey{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("user_id = ?", id).Delete(&models.UserIdentity{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("user_id = ?", id).Delete(&roleModels.UserRole{}).Error; err != nil {
+		return err
 	}
 	return nil
 }
+
+// GetExistingEmails повертає ті з emails, які вже зайняті (без урахування регістру)
+func GetExistingEmails(db *gorm.DB, emails []string) ([]string, error) {
+	var existing []string
+	if len(emails) == 0 {
+		return existing, nil
+	}
+	err := db.Model(&models.User{}).Where("LOWER(email) IN ?", emails).Pluck("LOWER(email)", &existing).Error
+	return existing, err
+}
+
+// GetExistingAcronyms повертає ті з acronyms, які вже зайняті
+func GetExistingAcronyms(db *gorm.DB, acronyms []string) ([]string, error) {
+	var existing []string
+	if len(acronyms) == 0 {
+		return existing, nil
+	}
+	err := db.Model(&models.User{}).Where("acronym IN ?", acronyms).Pluck("acronym", &existing).Error
+	return existing, err
+}
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"backend/internal/middleware"
+	roleModels "backend/modules/role/models"
 	"backend/modules/user/handlers"
 	"github.com/gin-gonic/gin"
 )
@@ -10,11 +12,42 @@ func RegisterRoutes(r *gin.RouterGroup) {
 	userGroup := r.Group("/users")
 	{
 		userGroup.GET("/me", handlers.ReadUserMe)
-		userGroup.PATCH("/me", handlers.UpdateCurrentUser)
-		userGroup.PATCH("/me/password/", handlers.UpdatePasswordCurrentUser)
-		userGroup.GET("/", handlers.ReadAllUsers)
-		userGroup.GET("/:id", handlers.ReadUserById)
-		userGroup.POST("/", handlers.CreateUser)
+		userGroup.PATCH("/me", middleware.RequireSession(), handlers.UpdateCurrentUser)
+		userGroup.PATCH("/me/password/", middleware.RequireSession(), handlers.UpdatePasswordCurrentUser)
+		userGroup.GET("/me/2fa", middleware.RequireSession(), handlers.GetMFAStatusHandler)
+		userGroup.POST("/me/2fa/setup", middleware.RequireSession(), handlers.SetupMFAHandler)
+		userGroup.POST("/me/2fa/confirm", middleware.RequireSession(), handlers.ConfirmMFAHandler)
+		userGroup.POST("/me/2fa/disable", middleware.RequireSession(), handlers.DisableMFAHandler)
+		userGroup.POST("/me/2fa/recovery-codes", middleware.RequireSession(), handlers.RegenerateRecoveryCodesHandler)
+		userGroup.GET("/me/tokens", handlers.ListMyAPIKeysHandler)
+		userGroup.POST("/me/tokens", handlers.CreateMyAPIKeyHandler)
+		userGroup.DELETE("/me/tokens/:id", handlers.RevokeMyAPIKeyHandler)
+		userGroup.GET("/", middleware.RequirePermission(roleModels.PermUsersRead), handlers.ReadAllUsers)
+		userGroup.GET("/:id", middleware.RequirePermission(roleModels.PermUsersRead), handlers.ReadUserById)
+		userGroup.POST("/", middleware.RequirePermission(roleModels.PermUsersManage), handlers.CreateUser)
 		userGroup.DELETE("/:id", handlers.DeleteUser)
+		userGroup.POST("/:id/deactivate", middleware.RequirePermission(roleModels.PermUsersManage), handlers.DeactivateUserHandler)
+		userGroup.POST("/:id/reactivate", middleware.RequirePermission(roleModels.PermUsersManage), handlers.ReactivateUserHandler)
+		userGroup.POST("/:id/offboard", middleware.RequirePermission(roleModels.PermUsersManage), handlers.OffboardUserHandler)
 	}
+
+	serviceAccountGroup := r.Group("/service-accounts", middleware.RequirePermission(roleModels.PermUsersManage))
+	{
+		serviceAccountGroup.GET("/", handlers.ListServiceAccountsHandler)
+		serviceAccountGroup.POST("/", handlers.CreateServiceAccountHandler)
+		serviceAccountGroup.DELETE("/:id", handlers.DeleteServiceAccountHandler)
+		serviceAccountGroup.GET("/:id/keys", handlers.ListServiceAccountKeysHandler)
+		serviceAccountGroup.POST("/:id/keys", handlers.CreateServiceAccountKeyHandler)
+		serviceAccountGroup.DELETE("/:id/keys/:keyId", handlers.RevokeServiceAccountKeyHandler)
+	}
+
+	loginBanGroup := r.Group("/login-bans", middleware.RequirePermission(roleModels.PermUsersManage))
+	{
+		loginBanGroup.GET("/", handlers.ListLoginBansHandler)
+		loginBanGroup.DELETE("/", handlers.ClearLoginBansHandler)
+		loginBanGroup.DELETE("/:id", handlers.DeleteLoginBanHandler)
+	}
+
+	r.POST("/logout", middleware.RequireSession(), handlers.LogoutHandler)
+	r.POST("/logout/all", middleware.RequireSession(), handlers.LogoutAllHandler)
 }
@@ -0,0 +1,214 @@
+package service
+
+import (
+	roleModels "backend/modules/role/models"
+	roleRepository "backend/modules/role/repository"
+	"backend/modules/user/models"
+	"backend/modules/user/repository"
+	"backend/modules/user/utils"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultAPIKeyTTLDays = 90
+	MaxAPIKeyTTLDays     = 365
+	// Як часто оновлювати last_used_at для ключа
+	apiKeyTouchInterval = time.Minute
+)
+
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func newAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return models.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func ToAPIKeyResponse(key *models.APIKey) models.APIKeyResponse {
+	return models.APIKeyResponse{
+		ID:         key.ID,
+		UserID:     key.UserID,
+		Name:       key.Name,
+		Hint:       key.Hint,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		LastUsedIP: key.LastUsedIP,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// CreateAPIKey видає ключ для owner. granted — дозволи того, хто створює:
+// ключ не може мати scope, якого в нього немає.
+func CreateAPIKey(db *gorm.DB, owner *models.User, createdByID uuid.UUID, granted []string, req *models.CreateAPIKeyRequest) (*models.CreatedAPIKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("api key name is required")
+	}
+	for _, scope := range req.Scopes {
+		if !roleModels.IsKnownPermission(scope) {
+			return nil, errors.New("unknown permission: " + scope)
+		}
+		if !roleModels.HasPermission(granted, scope) {
+			return nil, errors.New("permission denied: " + scope)
+		}
+	}
+
+	days := DefaultAPIKeyTTLDays
+	if req.ExpiresInDays != nil {
+		days = *req.ExpiresInDays
+	}
+	if days < 0 || days > MaxAPIKeyTTLDays {
+		return nil, errors.New("expiresInDays must be between 0 and 365")
+	}
+
+	raw, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key := &models.APIKey{
+		UserID:      owner.ID,
+		Name:        name,
+		KeyHash:     hashAPIKey(raw),
+		Hint:        raw[:len(models.APIKeyPrefix)+4] + "…" + raw[len(raw)-4:],
+		Scopes:      datatypes.JSONSlice[string](req.Scopes),
+		CreatedByID: createdByID,
+	}
+	if days > 0 {
+		expiresAt := time.Now().AddDate(0, 0, days)
+		key.ExpiresAt = &expiresAt
+	}
+	if err := repository.CreateAPIKey(db, key); err != nil {
+		return nil, err
+	}
+	return &models.CreatedAPIKeyResponse{APIKeyResponse: ToAPIKeyResponse(key), Key: raw}, nil
+}
+
+func ListAPIKeys(db *gorm.DB, userID uuid.UUID) ([]models.APIKeyResponse, error) {
+	keys, err := repository.GetAPIKeysByUser(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := make([]models.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		response = append(response, ToAPIKeyResponse(&keys[i]))
+	}
+	return response, nil
+}
+
+// AuthenticateAPIKey повертає ключ і його власника для заголовка Authorization
+func AuthenticateAPIKey(db *gorm.DB, raw, ip string) (*models.APIKey, *models.User, error) {
+	key, user, err := repository.GetActiveAPIKey(db, hashAPIKey(raw))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+	if err := repository.TouchAPIKey(db, key.ID, ip, apiKeyTouchInterval); err != nil {
+		return nil, nil, err
+	}
+	return key, user, nil
+}
+
+func ToServiceAccountResponse(user *models.User) models.ServiceAccountResponse {
+	return models.ServiceAccountResponse{
+		ID:        user.ID,
+		Name:      user.FullName,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+// CreateServiceAccount створює користувача без пароля і без картки
+// співробітника; його дозволи задає роль, а ключі звужують їх далі
+func CreateServiceAccount(db *gorm.DB, req *models.CreateServiceAccountRequest, canAssignRoles bool) (*models.ServiceAccountResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("service account name is required")
+	}
+
+	var role *roleModels.Role
+	var err error
+	if req.RoleID != nil {
+		role, err = roleRepository.GetRoleByID(db, *req.RoleID)
+	} else {
+		role, err = roleRepository.GetRoleByName(db, roleModels.RoleMember)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if role.Name != roleModels.RoleMember && !canAssignRoles {
+		return nil, errors.New("permission denied: " + roleModels.PermRolesManage)
+	}
+
+	// Пароль ніхто не знає, а вхід паролем для сервісних акаунтів заборонений
+	secret, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	password, err := utils.HashPassword(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.New()
+	user := &models.User{
+		ID:               id,
+		FullName:         name,
+		Email:            "svc-" + id.String() + "@service-accounts.invalid",
+		Password:         password,
+		IsServiceAccount: true,
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return roleRepository.SetUserRoles(tx, user.ID, []uuid.UUID{role.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := ToServiceAccountResponse(user)
+	return &response, nil
+}
+
+func ListServiceAccounts(db *gorm.DB) ([]models.ServiceAccountResponse, error) {
+	users, err := repository.GetServiceAccounts(db)
+	if err != nil {
+		return nil, err
+	}
+	response := make([]models.ServiceAccountResponse, 0, len(users))
+	for i := range users {
+		response = append(response, ToServiceAccountResponse(&users[i]))
+	}
+	return response, nil
+}
+
+// DeleteServiceAccount видаляє акаунт разом з усіма його ключами
+func DeleteServiceAccount(db *gorm.DB, id uuid.UUID) error {
+	if _, err := repository.GetServiceAccountByID(db, id); err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		return repository.DeleteUserById(tx, id)
+	})
+}
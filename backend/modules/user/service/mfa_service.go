@@ -0,0 +1,246 @@
+package service
+
+import (
+	"backend/internal/services/totp"
+	utils2 "backend/internal/services/utils"
+	"backend/modules/user/models"
+	"backend/modules/user/repository"
+	"backend/modules/user/utils"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"strings"
+	"time"
+)
+
+const recoveryCodeCount = 10
+
+var (
+	ErrInvalidMFACode     = errors.New("invalid two-factor code")
+	ErrMFAAlreadyEnabled  = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled      = errors.New("two-factor authentication is not enabled")
+	ErrMFASetupNotStarted = errors.New("two-factor setup not started")
+	ErrMFARequired        = errors.New("two-factor authentication is required by the tenant")
+)
+
+func IsMFAEnabled(db *gorm.DB, userID uuid.UUID) (bool, error) {
+	record, err := repository.GetUserTOTP(db, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return record.Enabled, nil
+}
+
+func GetMFAStatus(db *gorm.DB, userID uuid.UUID, required bool) (*models.MFAStatusResponse, error) {
+	status := &models.MFAStatusResponse{Required: required}
+	record, err := repository.GetUserTOTP(db, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return status, nil
+		}
+		return nil, err
+	}
+	status.Enabled = record.Enabled
+	status.ConfirmedAt = record.ConfirmedAt
+	status.RecoveryCodesRemaining = len(record.RecoveryCodes)
+	return status, nil
+}
+
+// BeginMFASetup створює новий секрет; 2FA вмикається лише після підтвердження кодом
+func BeginMFASetup(db *gorm.DB, user *models.User, issuer string) (*models.MFASetupResponse, error) {
+	existing, err := repository.GetUserTOTP(db, user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := utils2.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.UserTOTP{UserID: user.ID, Secret: encrypted}
+	if err := repository.SaveUserTOTP(db, record); err != nil {
+		return nil, err
+	}
+	return &models.MFASetupResponse{
+		Secret:     secret,
+		OTPAuthURL: totp.ProvisioningURI(issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmMFASetup вмикає 2FA і повертає коди відновлення (показуються один раз)
+func ConfirmMFASetup(db *gorm.DB, userID uuid.UUID, code string) ([]string, error) {
+	record, err := repository.GetUserTOTP(db, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMFASetupNotStarted
+		}
+		return nil, err
+	}
+	if record.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if err := checkTOTP(db, record, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	record.Enabled = true
+	record.ConfirmedAt = &now
+	record.RecoveryCodes = hashes
+	if err := repository.SaveUserTOTP(db, record); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func DisableMFA(db *gorm.DB, user *models.User, password, code string, required bool) error {
+	if required {
+		return ErrMFARequired
+	}
+	if !utils.ComparePasswords(password, user.Password) {
+		return errors.New("current password is incorrect")
+	}
+	if err := VerifySecondFactor(db, user.ID, code, ""); err != nil {
+		return err
+	}
+	return repository.DeleteUserTOTP(db, user.ID)
+}
+
+func RegenerateRecoveryCodes(db *gorm.DB, userID uuid.UUID, code string) ([]string, error) {
+	if err := VerifySecondFactor(db, userID, code, ""); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = db.Model(&models.UserTOTP{}).Where("user_id = ?", userID).
+		Update("recovery_codes", datatypes.JSONSlice[string](hashes)).Error
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// VerifySecondFactor приймає або TOTP-код, або одноразовий код відновлення
+func VerifySecondFactor(db *gorm.DB, userID uuid.UUID, code, recoveryCode string) error {
+	record, err := repository.GetUserTOTP(db, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMFANotEnabled
+		}
+		return err
+	}
+	if !record.Enabled {
+		return ErrMFANotEnabled
+	}
+	if recoveryCode != "" {
+		return consumeRecoveryCode(db, userID, recoveryCode)
+	}
+	return checkTOTP(db, record, code)
+}
+
+func checkTOTP(db *gorm.DB, record *models.UserTOTP, code string) error {
+	secret, err := utils2.Decrypt(record.Secret)
+	if err != nil {
+		return err
+	}
+	step, ok := totp.Validate(secret, code, time.Now(), record.LastUsedStep)
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	fresh, err := repository.MarkTOTPStepUsed(db, record.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidMFACode
+	}
+	record.LastUsedStep = step
+
+	// Ротація ключів охоплює лише адмінську БД, тож секрет, зашифрований
+	// старим ключем, перешифровуємо при успішному вході
+	if active, err := utils2.ActiveKeyID(); err == nil && utils2.CiphertextKeyID(record.Secret) != active {
+		if encrypted, err := utils2.Encrypt(secret); err == nil {
+			if err := db.Model(&models.UserTOTP{}).Where("user_id = ?", record.UserID).
+				Update("secret", encrypted).Error; err != nil {
+				log.Println("⚠️ Cannot re-encrypt TOTP secret:", err)
+			} else {
+				record.Secret = encrypted
+			}
+		}
+	}
+	return nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(buf))[:10]
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func consumeRecoveryCode(db *gorm.DB, userID uuid.UUID, code string) error {
+	hash := hashRecoveryCode(code)
+	return db.Transaction(func(tx *gorm.DB) error {
+		var record models.UserTOTP
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", userID).First(&record).Error
+		if err != nil {
+			return err
+		}
+
+		remaining := make([]string, 0, len(record.RecoveryCodes))
+		found := false
+		for _, stored := range record.RecoveryCodes {
+			if !found && stored == hash {
+				found = true
+				continue
+			}
+			remaining = append(remaining, stored)
+		}
+		if !found {
+			return ErrInvalidMFACode
+		}
+		return tx.Model(&models.UserTOTP{}).Where("user_id = ?", userID).
+			Update("recovery_codes", datatypes.JSONSlice[string](remaining)).Error
+	})
+}
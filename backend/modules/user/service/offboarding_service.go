@@ -0,0 +1,116 @@
+package service
+
+import (
+	"backend/modules/user/models"
+	"backend/modules/user/repository"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidTransferTarget = errors.New("invalid transfer target")
+	ErrUserOwnsContent       = errors.New("user owns content, transfer it to another user first")
+)
+
+// DeactivateUser блокує вхід: сесії й API-ключі відкликаються одразу,
+// тож уже видані токени перестають працювати на наступному запиті
+func DeactivateUser(db *gorm.DB, id uuid.UUID) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return deactivate(tx, id)
+	})
+}
+
+func deactivate(tx *gorm.DB, id uuid.UUID) error {
+	if err := repository.SetUserActive(tx, id, false); err != nil {
+		return err
+	}
+	if err := repository.RevokeUserSessions(tx, id); err != nil {
+		return err
+	}
+	return repository.RevokeUserAPIKeys(tx, id)
+}
+
+// ReactivateUser повертає доступ; відкликані ключі й сесії лишаються відкликаними
+func ReactivateUser(db *gorm.DB, id uuid.UUID) error {
+	user, err := repository.GetUserByIdFull(db, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+	// Запрошений користувач активується лише прийняттям запрошення
+	if user.InvitedAt != nil {
+		return errors.New("user has not accepted the invitation yet")
+	}
+	return repository.SetUserActive(db, id, true)
+}
+
+// OffboardUser передає блоги, товари, кімнати й події календаря іншому
+// користувачу, після чого деактивує або видаляє акаунт — усе в одній транзакції
+func OffboardUser(db *gorm.DB, id uuid.UUID, req models.OffboardUserRequest) (*models.OffboardUserResponse, error) {
+	if err := checkTransferTarget(db, id, req.TransferTo); err != nil {
+		return nil, err
+	}
+
+	response := &models.OffboardUserResponse{Deleted: req.Delete}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if _, err := repository.GetUserByIdFull(tx, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return err
+		}
+		transferred, err := repository.TransferOwnedContent(tx, id, req.TransferTo)
+		if err != nil {
+			return err
+		}
+		response.Transferred = *transferred
+		if req.Delete {
+			return repository.DeleteUserById(tx, id)
+		}
+		return deactivate(tx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// DeleteUser видаляє користувача; якщо йому щось належить, без transferTo
+// видалення відхиляється, щоб каскад не забрав чужі для команди дані
+func DeleteUser(db *gorm.DB, id uuid.UUID, transferTo *uuid.UUID) error {
+	if transferTo != nil {
+		_, err := OffboardUser(db, id, models.OffboardUserRequest{TransferTo: *transferTo, Delete: true})
+		return err
+	}
+	content, err := repository.CountOwnedContent(db, id)
+	if err != nil {
+		return err
+	}
+	if content.Total() > 0 {
+		return ErrUserOwnsContent
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		return repository.DeleteUserById(tx, id)
+	})
+}
+
+// checkTransferTarget — новий власник має бути іншим активним користувачем-людиною
+func checkTransferTarget(db *gorm.DB, id, targetID uuid.UUID) error {
+	if targetID == id {
+		return ErrInvalidTransferTarget
+	}
+	target, err := repository.GetUserByIdFull(db, targetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidTransferTarget
+		}
+		return err
+	}
+	if !target.IsActive || target.InvitedAt != nil || target.IsServiceAccount {
+		return ErrInvalidTransferTarget
+	}
+	return nil
+}
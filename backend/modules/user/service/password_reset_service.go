@@ -0,0 +1,116 @@
+package service
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	utils2 "backend/internal/services/utils"
+	"backend/modules/user/models"
+	"backend/modules/user/repository"
+	"backend/modules/user/utils"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"gorm.io/gorm"
+	"log"
+	"net/url"
+	"time"
+)
+
+const ResetTokenTTL = time.Hour
+
+var ErrInvalidResetToken = errors.New("invalid or expired reset token")
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// FrontendBaseURL — фронтенд тентанта, на який ведуть посилання з листів
+func FrontendBaseURL(tenant *entities.Tenant) string {
+	customHost, err := postgres.PrimaryCustomHost(tenant.ID)
+	if err != nil {
+		log.Println("⚠️ Cannot load tenant custom domain:", err)
+	}
+	return utils2.TenantFrontendURL(tenant.Domain, customHost)
+}
+
+// RequestPasswordReset створює одноразовий токен і надсилає лист. Для невідомого
+// або неактивного email нічого не робить — відповідь клієнту однакова.
+func RequestPasswordReset(db *gorm.DB, email string, tenant *entities.Tenant, settings *entities.TenantSettings, ip string) error {
+	user, err := repository.GetUserByEmail(db, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive || user.IsServiceAccount {
+		return nil
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	// Діє лише останнє посилання
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := repository.InvalidateResetTokens(tx, user.ID); err != nil {
+			return err
+		}
+		return repository.CreateResetToken(tx, &models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: hashResetToken(token),
+			ExpiresAt: time.Now().Add(ResetTokenTTL),
+			RequestIP: ip,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	resetLink := FrontendBaseURL(tenant) + "/reset-password?token=" + url.QueryEscape(token)
+	return utils2.SendPasswordResetEmail(settings, user.Email, resetLink)
+}
+
+// ResetPasswordWithToken споживає токен, змінює пароль і завершує всі сесії
+func ResetPasswordWithToken(db *gorm.DB, token string, newPassword string) error {
+	if newPassword == "" {
+		return errors.New("new password is required")
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		resetToken, err := repository.GetActiveResetTokenForUpdate(tx, hashResetToken(token))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+
+		user, err := repository.GetUserByIdFull(tx, resetToken.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+
+		hashedPassword, err := utils.HashPassword(newPassword)
+		if err != nil {
+			return err
+		}
+		user.Password = hashedPassword
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+
+		if err := repository.InvalidateResetTokens(tx, user.ID); err != nil {
+			return err
+		}
+		return repository.RevokeUserSessions(tx, user.ID)
+	})
+}
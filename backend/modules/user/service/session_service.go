@@ -0,0 +1,231 @@
+package service
+
+import (
+	"backend/internal/entities"
+	utils2 "backend/internal/services/utils"
+	"backend/modules/user/models"
+	"backend/modules/user/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"strings"
+	"time"
+)
+
+// Термін життя refresh-токена; кожне оновлення продовжує сесію
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	// ErrMFAEnrollmentRequired — тентант увімкнув обов'язкову 2FA
+	ErrMFAEnrollmentRequired = errors.New("two-factor enrollment required")
+	ErrUserInactive          = errors.New("user is inactive")
+	ErrSessionRevoked        = errors.New("session revoked")
+)
+
+func hashRefreshToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func newRefreshSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Refresh-токен має вигляд "<session id>.<secret>": за id шукаємо сесію,
+// секрет порівнюємо з хешем у БД
+func splitRefreshToken(token string) (uuid.UUID, string, error) {
+	idPart, secret, found := strings.Cut(token, ".")
+	if !found || secret == "" {
+		return uuid.Nil, "", ErrInvalidRefreshToken
+	}
+	id, err := uuid.Parse(idPart)
+	if err != nil {
+		return uuid.Nil, "", ErrInvalidRefreshToken
+	}
+	return id, secret, nil
+}
+
+func issueTokens(user *models.User, tenant *entities.Tenant, sessionID uuid.UUID, secret string) (*models.TokenResponse, error) {
+	accessToken, err := utils2.GenerateJWTToken(user.Email, user.FullName, user.ID, tenant.Domain, tenant.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: sessionID.String() + "." + secret,
+		TokenType:    "bearer",
+		ExpiresIn:    int(utils2.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// StartSession створює сесію після успішного входу і повертає пару токенів
+func StartSession(db *gorm.DB, user *models.User, tenant *entities.Tenant, userAgent, ip string) (*models.TokenResponse, error) {
+	// Останній рубіж: деактивований акаунт не отримує сесію жодним шляхом входу
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	secret, err := newRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.UserSession{
+		UserID:           user.ID,
+		RefreshTokenHash: hashRefreshToken(secret),
+		UserAgent:        userAgent,
+		IP:               ip,
+		ExpiresAt:        now.Add(RefreshTokenTTL),
+		LastUsedAt:       now,
+	}
+	if err := repository.CreateSession(db, session); err != nil {
+		return nil, err
+	}
+
+	// Заодно прибираємо старі сесії цього користувача
+	if err := repository.DeleteStaleSessions(db, user.ID, now.Add(-RefreshTokenTTL)); err != nil {
+		log.Println("⚠️ Cannot clean up stale sessions:", err)
+	}
+
+	return issueTokens(user, tenant, session.ID, secret)
+}
+
+// RefreshSession міняє refresh-токен на нову пару. Повторне використання
+// вже заміненого токена означає, що його могли вкрасти, — тоді сесія
+// відкликається повністю. Якщо тентант вимагає 2FA, а користувач її
+// не налаштував, сесію не продовжуємо — він має увійти заново.
+func RefreshSession(db *gorm.DB, refreshToken string, tenant *entities.Tenant, require2FA bool) (*models.TokenResponse, error) {
+	sessionID, secret, err := splitRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := repository.GetSessionByID(db, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	presented := hashRefreshToken(secret)
+	if subtle.ConstantTimeCompare([]byte(presented), []byte(session.RefreshTokenHash)) != 1 {
+		if session.PreviousTokenHash != "" &&
+			subtle.ConstantTimeCompare([]byte(presented), []byte(session.PreviousTokenHash)) == 1 {
+			log.Printf("⚠️ Refresh token reuse for session %s, revoking", session.ID)
+			if err := repository.RevokeSession(db, session.ID); err != nil {
+				return nil, err
+			}
+			return nil, ErrRefreshTokenReused
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := repository.GetUserByIdFull(db, session.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrInvalidRefreshToken
+	}
+	if require2FA {
+		enabled, err := IsMFAEnabled(db, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, ErrMFAEnrollmentRequired
+		}
+	}
+
+	newSecret, err := newRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := repository.RotateSessionToken(db, session.ID, session.RefreshTokenHash, hashRefreshToken(newSecret), time.Now().Add(RefreshTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Паралельний запит уже замінив токен
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return issueTokens(user, tenant, session.ID, newSecret)
+}
+
+func EndSession(db *gorm.DB, sessionID uuid.UUID) error {
+	return repository.RevokeSession(db, sessionID)
+}
+
+func EndAllSessions(db *gorm.DB, userID uuid.UUID) error {
+	return repository.RevokeUserSessions(db, userID)
+}
+
+// Як часто відкриті WebSocket/SSE-з'єднання перевіряють, чи сесія ще жива
+const sessionWatchInterval = 30 * time.Second
+
+// AuthenticateStream перевіряє токен з query-параметра WebSocket/SSE так само,
+// як AuthMiddleware: крім підпису, сесія має бути не відкликана, а користувач активний
+func AuthenticateStream(db *gorm.DB, token string) (*utils2.Claims, error) {
+	claims, err := utils2.ParseJWTToken(token)
+	if err != nil {
+		return nil, err
+	}
+	active, err := repository.IsSessionActive(db, claims.SessionID, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrSessionRevoked
+	}
+	return claims, nil
+}
+
+// WatchSession викликає onRevoked, щойно сесію відкликано (logout, вихід з усіх
+// пристроїв, зміна пароля, деактивація чи видалення користувача). Перевірка
+// періодична, тож працює й для відкликань на інших інстансах.
+// Спостереження завершується разом з ctx.
+func WatchSession(ctx context.Context, db *gorm.DB, claims *utils2.Claims, onRevoked func()) {
+	go func() {
+		ticker := time.NewTicker(sessionWatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				active, err := repository.IsSessionActive(db, claims.SessionID, claims.ID)
+				if err != nil {
+					// Збій БД не привід розривати з'єднання — перевіримо наступного разу
+					log.Printf("❌ Cannot verify session %s: %v", claims.SessionID, err)
+					continue
+				}
+				if !active {
+					log.Printf("🔒 Session %s revoked, closing live connection of user %s", claims.SessionID, claims.ID)
+					onRevoked()
+					return
+				}
+			}
+		}
+	}()
+}
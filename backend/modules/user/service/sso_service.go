@@ -0,0 +1,220 @@
+package service
+
+import (
+	"backend/internal/entities"
+	"backend/internal/services/metering"
+	"backend/internal/services/oidc"
+	utils2 "backend/internal/services/utils"
+	"backend/modules/user/models"
+	"backend/modules/user/repository"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"log"
+	"strings"
+	"time"
+)
+
+// Скільки часу користувач має, щоб пройти вхід на боці IdP
+const SSOStateTTL = 10 * time.Minute
+
+var (
+	ErrSSONotConfigured    = errors.New("sso is not configured")
+	ErrInvalidSSOState     = errors.New("invalid or expired sso state")
+	ErrSSOUserNotFound     = errors.New("no account matches this identity")
+	ErrSSODomainNotAllowed = errors.New("email domain is not allowed")
+)
+
+func hashSSOState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+// ssoConfig збирає конфігурацію клієнта з налаштувань тентанта. IdP
+// повертає користувача на сторінку фронтенду, а та передає code і state нам.
+func ssoConfig(tenant *entities.Tenant, settings *entities.TenantSettings) (oidc.Config, error) {
+	if !settings.SSOConfigured() {
+		return oidc.Config{}, ErrSSONotConfigured
+	}
+	secret, err := utils2.Decrypt(settings.OIDCClientSecret)
+	if err != nil {
+		return oidc.Config{}, err
+	}
+	return oidc.Config{
+		Issuer:       settings.OIDCIssuer,
+		ClientID:     settings.OIDCClientID,
+		ClientSecret: secret,
+		RedirectURL:  FrontendBaseURL(tenant) + "/sso/callback",
+	}, nil
+}
+
+// BeginSSOLogin готує state, nonce і PKCE та повертає адресу авторизації в IdP
+// і state, який хендлер прив'язує до браузера cookie
+func BeginSSOLogin(ctx context.Context, db *gorm.DB, tenant *entities.Tenant, settings *entities.TenantSettings) (string, string, error) {
+	cfg, err := ssoConfig(tenant, settings)
+	if err != nil {
+		return "", "", err
+	}
+	provider, err := oidc.Discover(ctx, cfg.Issuer)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = repository.CreateSSOLoginState(db, &models.SSOLoginState{
+		StateHash:    hashSSOState(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(SSOStateTTL),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return provider.AuthCodeURL(cfg, state, nonce, verifier), state, nil
+}
+
+// CompleteSSOLogin міняє code на ID-токен і знаходить (або створює) користувача.
+// browserState — state з cookie браузера, який починав вхід: без збігу чужий
+// code не можна підсунути жертві (login CSRF).
+func CompleteSSOLogin(ctx context.Context, db *gorm.DB, tenant *entities.Tenant, settings *entities.TenantSettings, code, state, browserState string) (*models.User, error) {
+	cfg, err := ssoConfig(tenant, settings)
+	if err != nil {
+		return nil, err
+	}
+	if browserState == "" || subtle.ConstantTimeCompare([]byte(browserState), []byte(state)) != 1 {
+		return nil, ErrInvalidSSOState
+	}
+
+	loginState, err := repository.ConsumeSSOLoginState(db, hashSSOState(state))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidSSOState
+		}
+		return nil, err
+	}
+
+	provider, err := oidc.Discover(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	rawIDToken, err := provider.Exchange(ctx, cfg, code, loginState.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := provider.VerifyIDToken(ctx, cfg, rawIDToken, loginState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := resolveSSOUser(db, tenant, settings, provider.Issuer, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive || user.InvitedAt != nil || user.IsServiceAccount {
+		return nil, ErrUserInactive
+	}
+	return user, nil
+}
+
+// resolveSSOUser шукає користувача за (issuer, sub); при першому вході — за
+// підтвердженим email, а якщо такого немає і ввімкнено автостворення — створює
+func resolveSSOUser(db *gorm.DB, tenant *entities.Tenant, settings *entities.TenantSettings, issuer string, claims *oidc.IDTokenClaims) (*models.User, error) {
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+
+	identity, err := repository.GetIdentity(db, issuer, claims.Subject)
+	if err == nil {
+		if err := repository.TouchIdentity(db, identity.ID, email); err != nil {
+			log.Println("⚠️ Cannot update identity:", err)
+		}
+		return repository.GetUserByIdFull(db, identity.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Без підтвердженого email не можемо зіставити з існуючим обліковим записом
+	if email == "" || !bool(claims.EmailVerified) {
+		return nil, ErrSSOUserNotFound
+	}
+
+	user, err := repository.GetUserByEmail(db, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		user, err = provisionSSOUser(db, tenant, settings, email, claims.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	err = repository.CreateIdentity(db, &models.UserIdentity{
+		UserID:      user.ID,
+		Issuer:      issuer,
+		Subject:     claims.Subject,
+		Email:       email,
+		LastLoginAt: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🔗 Linked %s identity %s to user %s", issuer, claims.Subject, user.ID)
+	return user, nil
+}
+
+func provisionSSOUser(db *gorm.DB, tenant *entities.Tenant, settings *entities.TenantSettings, email, name string) (*models.User, error) {
+	if !settings.OIDCAutoProvision {
+		return nil, ErrSSOUserNotFound
+	}
+	if len(settings.OIDCAllowedDomains) > 0 {
+		domain := email[strings.LastIndex(email, "@")+1:]
+		allowed := false
+		for _, d := range settings.OIDCAllowedDomains {
+			if d == domain {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, ErrSSODomainNotAllowed
+		}
+	}
+	// Пароль випадковий: користувач входить через IdP, а за потреби може скинути пароль
+	password, err := newRefreshSecret()
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(name) == "" {
+		name = strings.Split(email, "@")[0]
+	}
+	user := &models.User{FullName: name, Email: email, Password: password}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := metering.ReserveSeats(tx, tenant.ID, 1); err != nil {
+			return err
+		}
+		_, err := repository.CreateUser(tx, user, uuid.Nil, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("👤 Provisioned SSO user %s", email)
+	return user, nil
+}
@@ -7,9 +7,12 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"strings"
 )
 
-func UpdateCurrentUserPassword(db *gorm.DB, id uuid.UUID, password *models.UpdatePassword) (string, error) {
+// UpdateCurrentUserPassword змінює пароль і завершує всі інші сесії
+// користувача; поточна сесія (sessionID) лишається активною.
+func UpdateCurrentUserPassword(db *gorm.DB, id uuid.UUID, sessionID uuid.UUID, password *models.UpdatePassword) (string, error) {
 	user, err := repository.GetUserByIdFull(db, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -32,51 +35,75 @@ func UpdateCurrentUserPassword(db *gorm.DB, id uuid.UUID, password *models.Updat
 
 	user.Password = hashedPassword
 
-	if err = db.Save(&user).Error; err != nil {
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		// Старі посилання на скидання пароля після зміни більше не діють
+		if err := repository.InvalidateResetTokens(tx, user.ID); err != nil {
+			return err
+		}
+		return repository.RevokeOtherSessions(tx, user.ID, sessionID)
+	})
+	if err != nil {
 		return "", err
 	}
 
 	return "update password successfully", nil
 }
 
-func ResetCurrentUserPassword(db *gorm.DB, email string, password string) (string, error) {
-	user, err := repository.GetUserByEmail(db, email)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", errors.New("user not found")
-		}
-		return "", err
+func ToUserResponse(user *models.User) *models.UserResponse {
+	return &models.UserResponse{
+		ID:          user.ID,
+		FullName:    user.FullName,
+		Acronym:     user.Acronym,
+		Avatar:      user.Avatar,
+		Email:       user.Email,
+		IsActive:    user.IsActive,
+		IsSuperUser: user.IsSuperUser,
+		IsAdmin:     user.IsAdmin,
+		LastSeenAt:  user.LastSeenAt,
+		Status:      user.Status(),
 	}
+}
 
-	hashedPassword, err := utils.HashPassword(password)
-	if err != nil {
-		return "", err
-	}
+const (
+	defaultUserPageSize = 100
+	maxUserPageSize     = 500
+)
 
-	user.Password = hashedPassword
+var ErrInvalidUserSort = errors.New("invalid sort column")
 
-	if err = db.Save(&user).Error; err != nil {
-		return "", err
+// ListUsers — довідник користувачів з пошуком, фільтрами, сортуванням і загальною кількістю
+func ListUsers(db *gorm.DB, filter models.UserFilter) (*models.AllUsers, error) {
+	if filter.Sort != "" && !repository.IsUserSortColumn(filter.Sort) {
+		return nil, ErrInvalidUserSort
 	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultUserPageSize
+	}
+	if filter.Limit > maxUserPageSize {
+		filter.Limit = maxUserPageSize
+	}
+	if filter.Skip < 0 {
+		filter.Skip = 0
+	}
+	filter.Query = strings.TrimSpace(filter.Query)
 
-	return "update password successfully", nil
-}
-
-func TransformUsers(users []*models.User) []*models.UserResponse {
-	var userResponses []*models.UserResponse
-	for _, user := range users {
-		userResponse := &models.UserResponse{
-			ID:          user.ID,
-			FullName:    user.FullName,
-			Acronym:     user.Acronym,
-			Avatar:      user.Avatar,
-			Email:       user.Email,
-			IsActive:    user.IsActive,
-			IsSuperUser: user.IsSuperUser,
-			IsAdmin:     user.IsAdmin,
-			LastSeenAt:  user.LastSeenAt,
+	rows, total, err := repository.SearchUsers(db, &filter)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]*models.UserResponse, 0, len(rows))
+	for i := range rows {
+		response := ToUserResponse(&rows[i].User)
+		if rows[i].Position != nil {
+			response.Position = *rows[i].Position
+		}
+		if rows[i].Company != nil {
+			response.Company = *rows[i].Company
 		}
-		userResponses = append(userResponses, userResponse)
+		data = append(data, response)
 	}
-	return userResponses
+	return &models.AllUsers{Data: data, Count: total}, nil
 }
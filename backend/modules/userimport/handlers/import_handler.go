@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"backend/internal/services/spreadsheet"
+	utils2 "backend/internal/services/utils"
+	roleModels "backend/modules/role/models"
+	"backend/modules/userimport/models"
+	"backend/modules/userimport/service"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// Найбільший файл, який приймає імпорт
+const maxImportFileSize = 5 << 20
+
+// ImportUsersHandler приймає CSV або XLSX у полі "file". З dryRun=true лише
+// повертає звіт перевірки; інакше файл без помилок застосовується повністю.
+func ImportUsersHandler(ctx *gin.Context) {
+	// Параметри приймаємо і з query string, і з полів форми (форма має пріоритет)
+	var opts models.ImportOptions
+	if err := ctx.ShouldBindQuery(&opts); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	if err := ctx.ShouldBind(&opts); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		return
+	}
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	if header.Size > maxImportFileSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	table, err := spreadsheet.Read(header.Filename, file, header.Size)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Cannot read file: " + err.Error()})
+		return
+	}
+
+	db, ok := utils2.GetDBFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Database not found in context"})
+		return
+	}
+	actor, ok := utils2.GetCurrentUserFromContext(ctx, db)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	tenant, ok := utils2.GetTenantFromContext(ctx)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Tenant info missing"})
+		return
+	}
+	settings := utils2.GetTenantSettingsFromContext(ctx)
+
+	perms := service.Permissions{
+		AssignRoles:    utils2.HasPermission(ctx, db, roleModels.PermRolesManage),
+		WriteEmployees: utils2.HasPermission(ctx, db, roleModels.PermEmployeesWrite),
+	}
+	report, err := service.Import(db, tenant, &settings, actor, table, opts, perms)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidImport):
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrEmployeesWriteRequired):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	switch {
+	case report.Applied:
+		ctx.JSON(http.StatusCreated, report)
+	case len(report.Errors) > 0 && !report.DryRun:
+		// Нічого не записано: спершу треба виправити рядки зі звіту
+		ctx.JSON(http.StatusUnprocessableEntity, report)
+	default:
+		ctx.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import "github.com/google/uuid"
+
+// ImportOptions — параметри імпорту з multipart-форми або query string
+type ImportOptions struct {
+	// DryRun лише перевіряє файл і нічого не записує
+	DryRun bool `form:"dryRun"`
+	// Invite створює користувачів неактивними і надсилає запрошення
+	Invite bool `form:"invite"`
+	// Mapping — JSON {"Заголовок колонки": "поле"}; "extra.<ключ>" пише в ExtraData, "-" пропускає колонку
+	Mapping string `form:"mapping"`
+}
+
+// RowError — проблема в конкретному рядку файлу (рядок 1 — заголовок);
+// рядок 0 — проблема всього імпорту, наприклад бракує місць за квотою
+type RowError struct {
+	Row    int    `json:"row"`
+	Column string `json:"column,omitempty"`
+	Error  string `json:"error"`
+}
+
+type ImportedUser struct {
+	Row     int       `json:"row"`
+	UserID  uuid.UUID `json:"userId"`
+	Email   string    `json:"email"`
+	Invited bool      `json:"invited"`
+}
+
+type ImportReport struct {
+	DryRun  bool `json:"dryRun"`
+	Applied bool `json:"applied"`
+	Total   int  `json:"total"`
+	Valid   int  `json:"valid"`
+	// Columns — як розпізнано заголовки файлу: заголовок → поле
+	Columns map[string]string `json:"columns"`
+	Errors  []RowError        `json:"errors"`
+	Users   []ImportedUser    `json:"users,omitempty"`
+	// InvitationErrors — листи, які не вдалося надіслати; запрошення можна надіслати повторно
+	InvitationErrors []RowError `json:"invitationErrors,omitempty"`
+}
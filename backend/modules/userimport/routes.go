@@ -0,0 +1,12 @@
+package userimport
+
+import (
+	"backend/internal/middleware"
+	roleModels "backend/modules/role/models"
+	"backend/modules/userimport/handlers"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/users/import", middleware.RequirePermission(roleModels.PermUsersManage), handlers.ImportUsersHandler)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Поля, на які можна відобразити колонки файлу
+const (
+	FieldEmail         = "email"
+	FieldFullName      = "fullName"
+	FieldAcronym       = "acronym"
+	FieldRole          = "role"
+	FieldPhoneNumber1  = "phone_number_1"
+	FieldPhoneNumber2  = "phone_number_2"
+	FieldCompany       = "company"
+	FieldPosition      = "position"
+	FieldConditionType = "condition_type"
+	FieldSalary        = "salary"
+	FieldAddress       = "address"
+	FieldDateStart     = "date_start"
+	FieldDateEnd       = "date_end"
+
+	extraPrefix = "extra."
+	ignoreField = "-"
+)
+
+// employeeFields — колонки таблиці employees; їх запис потребує employees.write
+var employeeFields = map[string]bool{
+	FieldPhoneNumber1:  true,
+	FieldPhoneNumber2:  true,
+	FieldCompany:       true,
+	FieldPosition:      true,
+	FieldConditionType: true,
+	FieldSalary:        true,
+	FieldAddress:       true,
+	FieldDateStart:     true,
+	FieldDateEnd:       true,
+}
+
+// headerAliases — поширені назви заголовків (після normalizeHeader)
+var headerAliases = map[string]string{
+	"email":         FieldEmail,
+	"mail":          FieldEmail,
+	"пошта":         FieldEmail,
+	"fullname":      FieldFullName,
+	"name":          FieldFullName,
+	"піб":           FieldFullName,
+	"імя":           FieldFullName,
+	"acronym":       FieldAcronym,
+	"role":          FieldRole,
+	"роль":          FieldRole,
+	"phonenumber1":  FieldPhoneNumber1,
+	"phone":         FieldPhoneNumber1,
+	"телефон":       FieldPhoneNumber1,
+	"phonenumber2":  FieldPhoneNumber2,
+	"company":       FieldCompany,
+	"компанія":      FieldCompany,
+	"position":      FieldPosition,
+	"посада":        FieldPosition,
+	"conditiontype": FieldConditionType,
+	"salary":        FieldSalary,
+	"address":       FieldAddress,
+	"адреса":        FieldAddress,
+	"datestart":     FieldDateStart,
+	"startdate":     FieldDateStart,
+	"dateend":       FieldDateEnd,
+	"enddate":       FieldDateEnd,
+}
+
+var ErrInvalidImport = errors.New("invalid import file")
+
+type column struct {
+	Index  int
+	Header string
+	Field  string
+	// ExtraKey заповнений для колонок, що йдуть у ExtraData
+	ExtraKey string
+}
+
+func normalizeHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	return strings.NewReplacer(" ", "", "_", "", "-", "", ".", "", "'", "", "’", "").Replace(header)
+}
+
+func isKnownField(field string) bool {
+	switch field {
+	case FieldEmail, FieldFullName, FieldAcronym, FieldRole:
+		return true
+	}
+	return employeeFields[field]
+}
+
+// resolveColumns зіставляє заголовки з полями: спершу явний mapping, далі
+// відомі назви; невідомі колонки потрапляють у ExtraData під своїм заголовком
+func resolveColumns(header []string, rawMapping string) ([]column, error) {
+	mapping := map[string]string{}
+	if strings.TrimSpace(rawMapping) != "" {
+		if err := json.Unmarshal([]byte(rawMapping), &mapping); err != nil {
+			return nil, errors.New("invalid mapping: " + err.Error())
+		}
+	}
+	normalizedMapping := make(map[string]string, len(mapping))
+	for source, target := range mapping {
+		target = strings.TrimSpace(target)
+		if target != ignoreField && !strings.HasPrefix(target, extraPrefix) && !isKnownField(target) {
+			return nil, errors.New("invalid mapping: unknown field " + target)
+		}
+		normalizedMapping[normalizeHeader(source)] = target
+	}
+
+	var columns []column
+	seen := map[string]string{}
+	for i, raw := range header {
+		title := strings.TrimSpace(raw)
+		if title == "" {
+			continue
+		}
+		target, ok := normalizedMapping[normalizeHeader(title)]
+		if !ok {
+			if field, known := headerAliases[normalizeHeader(title)]; known {
+				target = field
+			} else {
+				target = extraPrefix + title
+			}
+		}
+		if target == ignoreField {
+			continue
+		}
+
+		col := column{Index: i, Header: title, Field: target}
+		if strings.HasPrefix(target, extraPrefix) {
+			col.ExtraKey = strings.TrimSpace(strings.TrimPrefix(target, extraPrefix))
+			if col.ExtraKey == "" {
+				return nil, errors.New("invalid mapping: empty extra key for column " + title)
+			}
+		}
+		if previous, dup := seen[target]; dup {
+			return nil, errors.New("columns " + previous + " and " + title + " both map to " + target)
+		}
+		seen[target] = title
+		columns = append(columns, col)
+	}
+
+	if _, ok := seen[FieldEmail]; !ok {
+		return nil, errors.New("email column is required")
+	}
+	return columns, nil
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"backend/internal/entities"
+	"backend/internal/services/metering"
+	employeeModels "backend/modules/employees/models"
+	invitationService "backend/modules/invitation/service"
+	roleModels "backend/modules/role/models"
+	roleRepository "backend/modules/role/repository"
+	userModels "backend/modules/user/models"
+	userRepository "backend/modules/user/repository"
+	"backend/modules/userimport/models"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxImportRows — скільки користувачів можна імпортувати одним файлом
+const MaxImportRows = 1000
+
+var ErrEmployeesWriteRequired = errors.New("permission denied: " + roleModels.PermEmployeesWrite)
+
+// Permissions — що дозволено тому, хто імпортує
+type Permissions struct {
+	AssignRoles    bool
+	WriteEmployees bool
+}
+
+type importRow struct {
+	Row      int
+	User     userModels.User
+	RoleName string
+	Employee map[string]interface{}
+	Extra    map[string]string
+}
+
+// Import перевіряє всі рядки і, якщо помилок немає і це не dryRun, створює
+// користувачів разом з працівниками в одній транзакції. Листи-запрошення
+// надсилаються лише після коміту.
+func Import(db *gorm.DB, tenant *entities.Tenant, settings *entities.TenantSettings, actor *userModels.User,
+	table [][]string, opts models.ImportOptions, perms Permissions) (*models.ImportReport, error) {
+
+	if len(table) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidImport)
+	}
+	columns, err := resolveColumns(table[0], opts.Mapping)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	report := &models.ImportReport{DryRun: opts.DryRun, Columns: map[string]string{}, Errors: []models.RowError{}}
+	for _, col := range columns {
+		report.Columns[col.Header] = col.Field
+		if employeeFields[col.Field] || col.ExtraKey != "" {
+			if !perms.WriteEmployees {
+				return nil, ErrEmployeesWriteRequired
+			}
+		}
+	}
+
+	var rows []importRow
+	for i, cells := range table[1:] {
+		if isBlank(cells) {
+			continue
+		}
+		if len(rows) >= MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImport, MaxImportRows)
+		}
+		row, rowErrors := parseRow(i+2, cells, columns)
+		report.Errors = append(report.Errors, rowErrors...)
+		if len(rowErrors) == 0 {
+			rows = append(rows, row)
+		}
+	}
+	report.Total = len(rows) + countRows(report.Errors)
+
+	roles, err := validateRows(db, rows, perms, report)
+	if err != nil {
+		return nil, err
+	}
+	report.Valid = report.Total - countRows(report.Errors)
+
+	// Місця перевіряються і в dryRun, інакше перевірка схвалила б файл,
+	// який потім не імпортується. Імпорт перевіряє їх ще раз у своїй транзакції.
+	if len(rows) > 0 {
+		if err := metering.CheckSeats(tenant.ID, db, int64(len(rows))); err != nil {
+			if !errors.Is(err, metering.ErrQuotaExceeded) {
+				return nil, err
+			}
+			report.Errors = append(report.Errors, models.RowError{Error: err.Error()})
+		}
+	}
+	if opts.DryRun || len(report.Errors) > 0 || len(rows) == 0 {
+		return report, nil
+	}
+
+	var pending []*invitationService.PendingInvitation
+	var pendingRows []int
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := metering.ReserveSeats(tx, tenant.ID, int64(len(rows))); err != nil {
+			return err
+		}
+		for i := range rows {
+			row := &rows[i]
+			role := roles[row.RoleName]
+			if opts.Invite {
+				invitation, err := invitationService.CreateInvitedUser(tx, &row.User, role.ID, actor)
+				if err != nil {
+					return fmt.Errorf("row %d: %w", row.Row, err)
+				}
+				pending = append(pending, invitation)
+				pendingRows = append(pendingRows, row.Row)
+			} else {
+				if err := createActiveUser(tx, row, role, actor); err != nil {
+					return fmt.Errorf("row %d: %w", row.Row, err)
+				}
+			}
+			if err := updateEmployee(tx, row, actor); err != nil {
+				return fmt.Errorf("row %d: %w", row.Row, err)
+			}
+			report.Users = append(report.Users, models.ImportedUser{
+				Row: row.Row, UserID: row.User.ID, Email: row.User.Email, Invited: opts.Invite,
+			})
+		}
+		return nil
+	})
+	if errors.Is(err, metering.ErrQuotaExceeded) {
+		// Місця зайняли паралельно з імпортом
+		report.Errors = append(report.Errors, models.RowError{Error: err.Error()})
+		return report, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	report.Applied = true
+
+	for i, invitation := range pending {
+		if err := invitation.Send(tenant, settings, actor.FullName); err != nil {
+			report.InvitationErrors = append(report.InvitationErrors, models.RowError{Row: pendingRows[i], Error: err.Error()})
+		}
+	}
+	return report, nil
+}
+
+// createActiveUser створює користувача без запрошення. Пароль випадковий і
+// нікому не відомий: вхід — через відновлення пароля або SSO.
+func createActiveUser(tx *gorm.DB, row *importRow, role *roleModels.Role, actor *userModels.User) error {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	row.User.Password = base64.RawURLEncoding.EncodeToString(buf)
+	if _, err := userRepository.CreateUser(tx, &row.User, actor.ID, actor.Acronym); err != nil {
+		return err
+	}
+	// CreateUser вже призначив роль member
+	if role.Name == roleModels.RoleMember {
+		return nil
+	}
+	return roleRepository.SetUserRoles(tx, row.User.ID, []uuid.UUID{role.ID})
+}
+
+// updateEmployee заповнює запис працівника, який CreateUser створює порожнім
+func updateEmployee(tx *gorm.DB, row *importRow, actor *userModels.User) error {
+	updates := row.Employee
+	if len(row.Extra) > 0 {
+		extra, err := json.Marshal(row.Extra)
+		if err != nil {
+			return err
+		}
+		updates["extra_data"] = datatypes.JSON(extra)
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	updates["whu_updated_by_id"] = actor.ID
+	updates["whu_updated_by_acron"] = actor.Acronym
+	return tx.Model(&employeeModels.Employees{}).Where("user_id = ?", row.User.ID).Updates(updates).Error
+}
+
+func parseRow(number int, cells []string, columns []column) (importRow, []models.RowError) {
+	row := importRow{Row: number, Employee: map[string]interface{}{}, Extra: map[string]string{}}
+	var rowErrors []models.RowError
+	fail := func(col column, message string) {
+		rowErrors = append(rowErrors, models.RowError{Row: number, Column: col.Header, Error: message})
+	}
+
+	for _, col := range columns {
+		value := ""
+		if col.Index < len(cells) {
+			value = strings.TrimSpace(cells[col.Index])
+		}
+		if col.ExtraKey != "" {
+			if value != "" {
+				row.Extra[col.ExtraKey] = value
+			}
+			continue
+		}
+
+		switch col.Field {
+		case FieldEmail:
+			address, err := mail.ParseAddress(value)
+			if value == "" {
+				fail(col, "email is required")
+			} else if err != nil || address.Address != value {
+				fail(col, "invalid email")
+			}
+			row.User.Email = strings.ToLower(value)
+		case FieldFullName:
+			row.User.FullName = value
+		case FieldAcronym:
+			row.User.Acronym = value
+		case FieldRole:
+			row.RoleName = value
+		case FieldDateStart, FieldDateEnd:
+			if value == "" {
+				continue
+			}
+			date, err := parseDate(value)
+			if err != nil {
+				fail(col, "invalid date "+strconv.Quote(value))
+				continue
+			}
+			row.Employee[col.Field] = date
+		default:
+			if value != "" {
+				row.Employee[col.Field] = value
+			}
+		}
+	}
+
+	if row.User.FullName == "" && row.User.Email != "" {
+		row.User.FullName = strings.Split(row.User.Email, "@")[0]
+	}
+	if row.RoleName == "" {
+		row.RoleName = roleModels.RoleMember
+	}
+	start, hasStart := row.Employee[FieldDateStart].(time.Time)
+	end, hasEnd := row.Employee[FieldDateEnd].(time.Time)
+	if hasStart && hasEnd && end.Before(start) {
+		rowErrors = append(rowErrors, models.RowError{Row: number, Column: FieldDateEnd, Error: "date_end is before date_start"})
+	}
+	return row, rowErrors
+}
+
+// validateRows шукає дублікати у файлі й у БД та перевіряє ролі; рядки з
+// помилками потрапляють у звіт. Повертає ролі за назвою для застосування.
+func validateRows(db *gorm.DB, rows []importRow, perms Permissions, report *models.ImportReport) (map[string]*roleModels.Role, error) {
+	allRoles, err := roleRepository.GetAllRoles(db)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string]*roleModels.Role, len(allRoles))
+	for i := range allRoles {
+		roles[allRoles[i].Name] = &allRoles[i]
+	}
+
+	var emails, acronyms []string
+	emailRows := map[string]int{}
+	acronymRows := map[string]int{}
+	for _, row := range rows {
+		if first, dup := emailRows[row.User.Email]; dup {
+			report.Errors = append(report.Errors, models.RowError{Row: row.Row, Column: FieldEmail, Error: fmt.Sprintf("duplicate email, see row %d", first)})
+		} else {
+			emailRows[row.User.Email] = row.Row
+			emails = append(emails, row.User.Email)
+		}
+		if row.User.Acronym == "" {
+			continue
+		}
+		if first, dup := acronymRows[row.User.Acronym]; dup {
+			report.Errors = append(report.Errors, models.RowError{Row: row.Row, Column: FieldAcronym, Error: fmt.Sprintf("duplicate acronym, see row %d", first)})
+		} else {
+			acronymRows[row.User.Acronym] = row.Row
+			acronyms = append(acronyms, row.User.Acronym)
+		}
+	}
+
+	existingEmails, err := userRepository.GetExistingEmails(db, emails)
+	if err != nil {
+		return nil, err
+	}
+	for _, email := range existingEmails {
+		report.Errors = append(report.Errors, models.RowError{Row: emailRows[email], Column: FieldEmail, Error: "user already exists"})
+	}
+	existingAcronyms, err := userRepository.GetExistingAcronyms(db, acronyms)
+	if err != nil {
+		return nil, err
+	}
+	for _, acronym := range existingAcronyms {
+		report.Errors = append(report.Errors, models.RowError{Row: acronymRows[acronym], Column: FieldAcronym, Error: "acronym is already taken"})
+	}
+
+	for _, row := range rows {
+		role, ok := roles[row.RoleName]
+		switch {
+		case !ok:
+			report.Errors = append(report.Errors, models.RowError{Row: row.Row, Column: FieldRole, Error: "role not found"})
+		case role.Name != roleModels.RoleMember && !perms.AssignRoles:
+			report.Errors = append(report.Errors, models.RowError{Row: row.Row, Column: FieldRole, Error: "permission denied: " + roleModels.PermRolesManage})
+		}
+	}
+	return roles, nil
+}
+
+// Формати дат, які трапляються в кадрових таблицях
+var dateLayouts = []string{"2006-01-02", "02.01.2006", time.RFC3339}
+
+// excelEpoch — нульовий день серійних дат Excel (з урахуванням помилки 1900 року)
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	// XLSX зберігає дату як номер дня
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial >= 1 && serial < 2958466 {
+		return excelEpoch.AddDate(0, 0, int(serial)), nil
+	}
+	return time.Time{}, errors.New("invalid date")
+}
+
+func isBlank(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// countRows — кількість різних рядків, що мають помилки
+func countRows(rowErrors []models.RowError) int {
+	rows := map[int]bool{}
+	for _, rowError := range rowErrors {
+		rows[rowError.Row] = true
+	}
+	return len(rows)
+}
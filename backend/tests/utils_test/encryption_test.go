@@ -0,0 +1,70 @@
+package utils_test
+
+import (
+	"backend/internal/services/utils"
+	"strings"
+	"testing"
+)
+
+const (
+	oldKey = "0123456789abcdef0123456789abcdef"
+	newKey = "fedcba9876543210fedcba9876543210"
+)
+
+func TestDecryptLegacyCiphertext(t *testing.T) {
+	t.Setenv("TENANT_ENCRYPTION_KEY", oldKey)
+	t.Setenv("TENANT_ENCRYPTION_KEYS", "")
+	t.Setenv("TENANT_ENCRYPTION_ACTIVE_KEY", "")
+
+	encrypted, err := utils.Encrypt("secret")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+
+	// Шифротекст у старому форматі — без префікса ключа
+	legacy := encrypted[strings.LastIndex(encrypted, ":")+1:]
+	if utils.CiphertextKeyID(legacy) != utils.LegacyKeyID {
+		t.Fatalf("expected legacy key ID for unprefixed ciphertext")
+	}
+
+	plaintext, err := utils.Decrypt(legacy)
+	if err != nil || plaintext != "secret" {
+		t.Fatalf("decrypt legacy: %q, %v", plaintext, err)
+	}
+}
+
+func TestEncryptUsesActiveKeyAndDecryptsOldOnes(t *testing.T) {
+	t.Setenv("TENANT_ENCRYPTION_KEY", "")
+	t.Setenv("TENANT_ENCRYPTION_KEYS", "k1:"+oldKey+",k2:"+newKey)
+	t.Setenv("TENANT_ENCRYPTION_ACTIVE_KEY", "k1")
+
+	old, err := utils.Encrypt("db-password")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if utils.CiphertextKeyID(old) != "k1" {
+		t.Fatalf("expected k1, got %s", utils.CiphertextKeyID(old))
+	}
+
+	t.Setenv("TENANT_ENCRYPTION_ACTIVE_KEY", "k2")
+	current, err := utils.Encrypt("db-password")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if utils.CiphertextKeyID(current) != "k2" {
+		t.Fatalf("expected k2, got %s", utils.CiphertextKeyID(current))
+	}
+
+	for _, value := range []string{old, current} {
+		plaintext, err := utils.Decrypt(value)
+		if err != nil || plaintext != "db-password" {
+			t.Fatalf("decrypt %s: %q, %v", value, plaintext, err)
+		}
+	}
+
+	// Після виведення k1 з обігу старе значення більше не читається
+	t.Setenv("TENANT_ENCRYPTION_KEYS", "k2:"+newKey)
+	if _, err := utils.Decrypt(old); err == nil {
+		t.Fatalf("expected error after retiring k1")
+	}
+}
@@ -0,0 +1,102 @@
+package utils_test
+
+import (
+	"backend/internal/db/postgres"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	pgdriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+)
+
+// adminSchemaDB — фальшива адмінська БД із заданим набором колонок login_attempts:
+// відповідає на запити information_schema і запам'ятовує виконані команди
+type adminSchemaDB struct {
+	columns  map[string]bool // nil — таблиці немає
+	commands []string
+}
+
+func (d *adminSchemaDB) Connect(context.Context) (driver.Conn, error) {
+	return &adminSchemaConn{db: d}, nil
+}
+func (d *adminSchemaDB) Driver() driver.Driver { return recordingDriver{} }
+
+type adminSchemaConn struct{ db *adminSchemaDB }
+
+func (c *adminSchemaConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c *adminSchemaConn) Close() error              { return nil }
+func (c *adminSchemaConn) Begin() (driver.Tx, error) { return tenantTx{}, nil }
+
+func (c *adminSchemaConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.db.commands = append(c.db.commands, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c *adminSchemaConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	var found int64
+	switch {
+	case strings.Contains(query, "information_schema.tables"):
+		if c.db.columns != nil {
+			found = 1
+		}
+	case strings.Contains(query, "INFORMATION_SCHEMA.columns"):
+		if name, ok := args[len(args)-1].Value.(string); ok && c.db.columns[name] {
+			found = 1
+		}
+	default:
+		c.db.commands = append(c.db.commands, query)
+		return &recordingRows{}, nil
+	}
+	return &recordingRows{columns: []string{"count"}, data: [][]driver.Value{{found}}}, nil
+}
+
+func prepareLoginAttempts(t *testing.T, columns ...string) *adminSchemaDB {
+	t.Helper()
+	fake := &adminSchemaDB{}
+	if columns != nil {
+		fake.columns = make(map[string]bool, len(columns))
+		for _, column := range columns {
+			fake.columns[column] = true
+		}
+	}
+	db, err := gorm.Open(pgdriver.New(pgdriver.Config{Conn: sql.OpenDB(fake)}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := postgres.PrepareLoginAttemptsKey(db); err != nil {
+		t.Fatal(err)
+	}
+	return fake
+}
+
+func TestPrepareLoginAttemptsKeyOnBaselineSchema(t *testing.T) {
+	// Таблиця зі старої схеми: ні tenant_id, ні scope ще немає
+	fake := prepareLoginAttempts(t, "id", "email", "ip", "attempts", "last_attempt", "banned_until", "created_at", "updated_at")
+	for _, command := range fake.commands {
+		if strings.Contains(command, "tenant_id") || strings.Contains(command, "scope") {
+			t.Errorf("baseline table has no tenant_id/scope yet, got: %s", command)
+		}
+	}
+}
+
+func TestPrepareLoginAttemptsKeyDeduplicatesScopedCounters(t *testing.T) {
+	fake := prepareLoginAttempts(t, "id", "tenant_id", "email", "ip", "scope", "updated_at")
+	joined := strings.Join(fake.commands, "\n")
+	if !strings.Contains(joined, "DELETE FROM login_attempts a USING login_attempts b") {
+		t.Errorf("duplicate counters must be removed before the unique index: %v", fake.commands)
+	}
+	if !strings.Contains(joined, "DROP INDEX IF EXISTS idx_login_attempt_key") {
+		t.Errorf("old non-unique index must be dropped: %v", fake.commands)
+	}
+}
+
+func TestPrepareLoginAttemptsKeyWithoutTable(t *testing.T) {
+	if fake := prepareLoginAttempts(t); len(fake.commands) != 0 {
+		t.Errorf("fresh database needs no preparation, got %v", fake.commands)
+	}
+}
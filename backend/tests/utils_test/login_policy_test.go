@@ -0,0 +1,43 @@
+package utils_test
+
+import (
+	"backend/internal/entities"
+	"testing"
+	"time"
+)
+
+func TestLoginPolicyLockoutSteps(t *testing.T) {
+	policy := entities.LoginPolicy{}.WithDefaults()
+
+	cases := map[int]time.Duration{
+		1:  0,
+		3:  5 * time.Minute,
+		7:  10 * time.Minute,
+		12: 24 * time.Hour,
+	}
+	for attempts, want := range cases {
+		if got := policy.IPLockout(attempts); got != want {
+			t.Errorf("IPLockout(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+	if policy.AccountLockout(policy.AccountMaxAttempts-1) != 0 || policy.AccountLockout(policy.AccountMaxAttempts) == 0 {
+		t.Error("account lockout must start exactly at AccountMaxAttempts")
+	}
+}
+
+func TestLoginPolicyValidate(t *testing.T) {
+	valid := entities.DefaultLoginPolicy()
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("default policy must be valid: %v", err)
+	}
+
+	unordered := entities.LoginPolicy{IPSteps: []entities.LockoutStep{{Attempts: 5, LockoutMinutes: 1}, {Attempts: 3, LockoutMinutes: 1}}}
+	if err := unordered.Validate(); err == nil {
+		t.Error("steps with decreasing attempts must be rejected")
+	}
+
+	forever := entities.LoginPolicy{IPSteps: []entities.LockoutStep{{Attempts: 3, LockoutMinutes: 100 * 365 * 24 * 60}}}
+	if err := forever.Validate(); err == nil {
+		t.Error("lockout longer than 30 days must be rejected")
+	}
+}
@@ -0,0 +1,27 @@
+package utils_test
+
+import (
+	roleModels "backend/modules/role/models"
+	"reflect"
+	"testing"
+)
+
+func TestRestrictPermissions(t *testing.T) {
+	cases := []struct {
+		name    string
+		granted []string
+		scopes  []string
+		want    []string
+	}{
+		{"scope narrows wildcard role", []string{"*"}, []string{"items.read"}, []string{"items.read"}},
+		{"wildcard scope keeps role", []string{"items.read", "items.write"}, []string{"*"}, []string{"items.read", "items.write"}},
+		{"scope outside role is dropped", []string{"items.read"}, []string{"items.read", "users.manage"}, []string{"items.read"}},
+		{"no overlap", []string{"blog.read"}, []string{"items.write"}, []string{}},
+	}
+	for _, tc := range cases {
+		got := roleModels.RestrictPermissions(tc.granted, tc.scopes)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
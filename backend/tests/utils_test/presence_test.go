@@ -0,0 +1,55 @@
+package utils_test
+
+import (
+	presence "backend/modules/presence/service"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestPresenceTracksConnections(t *testing.T) {
+	var changes []presence.Change
+	tracker := presence.NewTracker(func(_ uuid.UUID, _ []uuid.UUID, change presence.Change) {
+		changes = append(changes, change)
+	})
+	tenant, other, user := uuid.New(), uuid.New(), uuid.New()
+
+	first := tracker.Connect(tenant, user, nil)
+	second := tracker.Connect(tenant, user, nil)
+	if got := tracker.Status(tenant, user); got != presence.StatusOnline {
+		t.Fatalf("status after connect = %s, want online", got)
+	}
+	if got := tracker.Status(other, user); got != presence.StatusOffline {
+		t.Fatalf("presence leaked into another tenant: %s", got)
+	}
+
+	tracker.Sweep(time.Now().Add(presence.AwayAfter + time.Second))
+	if got := tracker.Status(tenant, user); got != presence.StatusAway {
+		t.Fatalf("status after idle = %s, want away", got)
+	}
+	tracker.Touch(tenant, user)
+	if got := tracker.Status(tenant, user); got != presence.StatusOnline {
+		t.Fatalf("status after activity = %s, want online", got)
+	}
+
+	// Закрилась одна вкладка з двох — користувач лишається онлайн
+	first()
+	first()
+	if got := tracker.Status(tenant, user); got != presence.StatusOnline {
+		t.Fatalf("status with one live connection = %s, want online", got)
+	}
+	second()
+	if got := tracker.Status(tenant, user); got != presence.StatusOffline {
+		t.Fatalf("status after last disconnect = %s, want offline", got)
+	}
+
+	want := []string{presence.StatusOnline, presence.StatusAway, presence.StatusOnline}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d", len(changes), len(want))
+	}
+	for i, status := range want {
+		if changes[i].Status != status || changes[i].UserID != user {
+			t.Errorf("change %d = %+v, want %s", i, changes[i], status)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package utils_test
+
+import (
+	"archive/zip"
+	"backend/internal/services/spreadsheet"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSVSemicolonWithBOM(t *testing.T) {
+	data := "\xEF\xBB\xBFemail;fullName\nann@example.com;Ann Lee\n"
+	rows, err := spreadsheet.ReadCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"email", "fullName"}, {"ann@example.com", "Ann Lee"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows = %q, want %q", rows, want)
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="People" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst><si><t>email</t></si><si><r><t>Date </t></r><r><t>start</t></r></si><si><t>ann@example.com</t></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>
+			<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3" t="inlineStr"><is><t>x</t></is></c><c r="C3"><v>45292</v></c></row>
+		</sheetData></worksheet>`,
+	}
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	archive.Close()
+
+	rows, err := spreadsheet.Read("people.xlsx", bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Пропущений рядок 2 відновлюється, щоб номери рядків у звіті збігались з файлом
+	want := [][]string{{"email", "", "Date start"}, nil, {"ann@example.com", "x", "45292"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("rows = %q, want %q", rows, want)
+	}
+
+	if _, err := spreadsheet.Read("people.txt", bytes.NewReader(nil), 0); err != spreadsheet.ErrUnsupportedFormat {
+		t.Fatalf("err = %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestEscapeFormula(t *testing.T) {
+	cases := map[string]string{
+		"=HYPERLINK(\"http://evil\")": "'=HYPERLINK(\"http://evil\")",
+		"+1+2":                        "'+1+2",
+		"-2+3":                        "'-2+3",
+		"@SUM(A1)":                    "'@SUM(A1)",
+		"\t=1":                        "'\t=1",
+		"anna@corp.example":           "anna@corp.example",
+		"/v1/users":                   "/v1/users",
+		"":                            "",
+	}
+	for in, want := range cases {
+		if got := spreadsheet.EscapeFormula(in); got != want {
+			t.Errorf("EscapeFormula(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
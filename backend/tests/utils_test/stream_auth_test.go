@@ -0,0 +1,43 @@
+package utils_test
+
+import (
+	"backend/internal/services/utils"
+	"backend/modules/user/service"
+	"errors"
+	"github.com/google/uuid"
+	"strings"
+	"testing"
+)
+
+func TestAuthenticateStreamChecksSession(t *testing.T) {
+	userID, sessionID := uuid.New(), uuid.New()
+	token, err := utils.GenerateJWTToken("user@example.com", "Test User", userID, "test", uuid.New(), sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Сесію відкликано: підпис валідний, але підключатися не можна
+	revoked := &recordingDB{total: 0}
+	if _, err := service.AuthenticateStream(openRecordingDB(t, revoked), token); !errors.Is(err, service.ErrSessionRevoked) {
+		t.Fatalf("expected ErrSessionRevoked, got %v", err)
+	}
+	if len(revoked.queries) != 1 || !strings.Contains(revoked.queries[0].sql, "user_sessions") {
+		t.Fatalf("expected a session lookup, got %+v", revoked.queries)
+	}
+	if !hasArg(revoked.queries[0].args, sessionID.String()) || !hasArg(revoked.queries[0].args, userID.String()) {
+		t.Errorf("session lookup is not bound to the token: %v", revoked.queries[0].args)
+	}
+
+	active := &recordingDB{total: 1}
+	claims, err := service.AuthenticateStream(openRecordingDB(t, active), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.ID != userID || claims.SessionID != sessionID {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	if _, err := service.AuthenticateStream(openRecordingDB(t, active), token+"x"); err == nil {
+		t.Error("expected a tampered token to be rejected")
+	}
+}
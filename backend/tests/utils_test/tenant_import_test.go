@@ -0,0 +1,130 @@
+package utils_test
+
+import (
+	"backend/internal/db/migrator"
+	"backend/modules/tenant/models"
+	"backend/modules/tenant/service"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	pgdriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tenantDB — фальшива БД тентанта: рахує вставлені рядки по таблицях і відповідає
+// на COUNT цими лічильниками, тож видно, що саме лишає провіженінг
+type tenantDB struct {
+	mu   sync.Mutex
+	rows map[string]int64
+}
+
+var (
+	insertTable = regexp.MustCompile(`(?i)^INSERT INTO "([^"]+)"`)
+	countTable  = regexp.MustCompile(`(?i)count\(\*\) FROM "([^"]+)"`)
+	returning   = regexp.MustCompile(`(?i)RETURNING (.+)$`)
+)
+
+func (d *tenantDB) Connect(context.Context) (driver.Conn, error) { return &tenantConn{db: d}, nil }
+func (d *tenantDB) Driver() driver.Driver                        { return recordingDriver{} }
+
+type tenantConn struct{ db *tenantDB }
+
+func (c *tenantConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *tenantConn) Close() error                        { return nil }
+func (c *tenantConn) Begin() (driver.Tx, error)           { return tenantTx{}, nil }
+
+type tenantTx struct{}
+
+func (tenantTx) Commit() error   { return nil }
+func (tenantTx) Rollback() error { return nil }
+
+func (c *tenantConn) record(query string) {
+	if match := insertTable.FindStringSubmatch(query); match != nil {
+		c.db.mu.Lock()
+		c.db.rows[match[1]]++
+		c.db.mu.Unlock()
+	}
+}
+
+func (c *tenantConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	c.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *tenantConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.record(query)
+	switch {
+	case insertTable.MatchString(query):
+		var columns []string
+		if match := returning.FindStringSubmatch(query); match != nil {
+			for _, column := range strings.Split(match[1], ",") {
+				columns = append(columns, strings.Trim(strings.TrimSpace(column), `"`))
+			}
+		}
+		return &recordingRows{columns: columns}, nil
+	case strings.Contains(query, "information_schema"):
+		return &recordingRows{columns: []string{"count"}, data: [][]driver.Value{{int64(1)}}}, nil
+	case countTable.MatchString(query):
+		table := countTable.FindStringSubmatch(query)[1]
+		c.db.mu.Lock()
+		defer c.db.mu.Unlock()
+		return &recordingRows{columns: []string{"count"}, data: [][]driver.Value{{c.db.rows[table]}}}, nil
+	case strings.Contains(query, `FROM "schema_migrations"`):
+		return &recordingRows{
+			columns: []string{"module", "version", "name", "applied_at"},
+			data:    [][]driver.Value{{"user", int64(1), "create_users", time.Now()}},
+		}, nil
+	case strings.Contains(query, `FROM "roles"`):
+		return &recordingRows{
+			columns: []string{"id", "name", "permissions", "is_system"},
+			data:    [][]driver.Value{{"5f0c6d1e-2a3b-4c5d-8e9f-0a1b2c3d4e5f", "admin", []byte(`["*"]`), true}},
+		}, nil
+	}
+	return &recordingRows{}, nil
+}
+
+func TestImportIntoFreshlyProvisionedTenant(t *testing.T) {
+	// Стан після міграцій: системні ролі вже засіяні
+	fake := &tenantDB{rows: map[string]int64{"roles": 3}}
+	db, err := gorm.Open(pgdriver.New(pgdriver.Config{Conn: sql.OpenDB(fake)}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = service.SeedSuperUser(db, models.ProvisionAdminUser{
+		FullName: "Tenant Admin",
+		Email:    "admin@acme.example",
+		Password: "correct horse battery",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fake.rows["employees"] == 0 {
+		t.Fatalf("expected the seed to create the admin's employee profile, got %v", fake.rows)
+	}
+
+	manifest := &service.ArchiveManifest{
+		FormatVersion: service.ArchiveFormatVersion,
+		Migrations:    []migrator.Status{{ID: "user/0001_create_users"}},
+	}
+	for table := range fake.rows {
+		manifest.Tables = append(manifest.Tables, service.ArchiveTable{Table: table})
+	}
+	manifest.Tables = append(manifest.Tables, service.ArchiveTable{Table: "calendars"})
+
+	if err := service.CheckImportTarget(db, manifest); err != nil {
+		t.Fatalf("a freshly provisioned tenant must accept an import: %v", err)
+	}
+
+	// Будь-які дані понад провіженінг означають, що тентант уже використовується
+	fake.rows["employees"]++
+	if err := service.CheckImportTarget(db, manifest); err == nil {
+		t.Fatal("expected a tenant with extra employees to be rejected")
+	}
+}
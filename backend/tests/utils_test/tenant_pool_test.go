@@ -0,0 +1,67 @@
+package utils_test
+
+import (
+	"backend/internal/db/postgres"
+	pgdriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Підключення без реального сервера: gorm не пінгує БД при відкритті
+func openUnreachableDB() (*gorm.DB, error) {
+	dsn := "host=127.0.0.1 port=1 user=test dbname=test sslmode=disable connect_timeout=1"
+	return gorm.Open(pgdriver.Open(dsn), &gorm.Config{DisableAutomaticPing: true})
+}
+
+func isClosed(t *testing.T, db *gorm.DB) bool {
+	t.Helper()
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sqlDB.Ping()
+	return err != nil && strings.Contains(err.Error(), "database is closed")
+}
+
+func TestTenantPoolKeepsPinnedPoolOpen(t *testing.T) {
+	postgres.Pool.Configure(postgres.PoolConfig{
+		MaxOpenPerTenant: 1, MaxIdlePerTenant: 1, GlobalMaxOpen: 1,
+		ConnMaxLifetime: time.Minute, IdleTimeout: time.Minute, HealthInterval: time.Minute,
+	})
+
+	db, release, err := postgres.Pool.Acquire("pinned.test", openUnreachableDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Бюджет на один пул, але закріплений пул не витісняється
+	if _, err := postgres.Pool.GetOrOpen("other.test", openUnreachableDB); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := postgres.Pool.Get("pinned.test"); !ok {
+		t.Fatal("pinned pool was evicted for the connection budget")
+	}
+
+	postgres.Pool.Delete("pinned.test")
+	if _, ok := postgres.Pool.Get("pinned.test"); ok {
+		t.Fatal("deleted pool is still cached")
+	}
+	if isClosed(t, db) {
+		t.Fatal("pinned pool was closed while in use")
+	}
+
+	release()
+	release() // повторний виклик нічого не ламає
+	deadline := time.Now().Add(time.Second)
+	for !isClosed(t, db) {
+		if time.Now().After(deadline) {
+			t.Fatal("retired pool was not closed after release")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	postgres.Pool.Delete("other.test")
+	postgres.Pool.Configure(postgres.PoolConfigFromEnv())
+}
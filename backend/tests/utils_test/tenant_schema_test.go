@@ -0,0 +1,53 @@
+package utils_test
+
+import (
+	"backend/internal/db/postgres"
+	"backend/internal/entities"
+	"backend/internal/services/utils"
+	"strings"
+	"testing"
+)
+
+func TestTenantSchemaStatementsRevokeCreateOnPublic(t *testing.T) {
+	statements := postgres.TenantSchemaStatements("tenants_shared", "tenant_acme", "acme_user")
+
+	want := []string{
+		`REVOKE CREATE ON SCHEMA public FROM PUBLIC`,
+		`GRANT CONNECT ON DATABASE "tenants_shared" TO "acme_user"`,
+		`CREATE SCHEMA "tenant_acme" AUTHORIZATION "acme_user"`,
+		`REVOKE ALL ON SCHEMA "tenant_acme" FROM PUBLIC`,
+	}
+	if len(statements) != len(want) {
+		t.Fatalf("expected %d statements, got %d: %v", len(want), len(statements), statements)
+	}
+	for i := range want {
+		if statements[i] != want[i] {
+			t.Errorf("statement %d: expected %q, got %q", i, want[i], statements[i])
+		}
+	}
+}
+
+func TestTenantSchemaStatementsQuoteIdentifiers(t *testing.T) {
+	statements := postgres.TenantSchemaStatements("shared", `evil"; DROP SCHEMA public; --`, "owner")
+
+	create := statements[2]
+	if !strings.HasPrefix(create, `CREATE SCHEMA "evil""; DROP SCHEMA public; --" AUTHORIZATION`) {
+		t.Fatalf("schema name is not quoted: %s", create)
+	}
+}
+
+func TestTenantDSNSearchPath(t *testing.T) {
+	creds := &utils.DecryptedTenantCreds{DBUser: "u", DBName: "tenants_shared", DBHost: "localhost", DBPassword: "p"}
+
+	schemaTenant := &entities.Tenant{IsolationMode: entities.IsolationSchema, SchemaName: "tenant_acme"}
+	dsn := postgres.TenantDSN(schemaTenant, creds, "5432", "UTC")
+	if !strings.HasSuffix(dsn, " search_path=tenant_acme,public") {
+		t.Errorf("schema mode must pin search_path to the tenant schema first: %s", dsn)
+	}
+
+	dbTenant := &entities.Tenant{IsolationMode: entities.IsolationDatabase}
+	dsn = postgres.TenantDSN(dbTenant, creds, "5432", "UTC")
+	if strings.Contains(dsn, "search_path") {
+		t.Errorf("database mode must not set search_path: %s", dsn)
+	}
+}
@@ -0,0 +1,52 @@
+package utils_test
+
+import (
+	"backend/internal/services/totp"
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// Тестові вектори з RFC 6238 (SHA1), обрізані до 6 цифр
+func TestTOTPCodeRFCVectors(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range cases {
+		got, err := totp.CodeAt(secret, totp.Step(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatalf("code at %d: %v", unix, err)
+		}
+		if got != want {
+			t.Errorf("code at %d: got %s, want %s", unix, got, want)
+		}
+	}
+}
+
+func TestTOTPValidateRejectsReplay(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("generate secret: %v", err)
+	}
+	now := time.Now()
+	code, err := totp.CodeAt(secret, totp.Step(now))
+	if err != nil {
+		t.Fatalf("code: %v", err)
+	}
+
+	step, ok := totp.Validate(secret, code, now, 0)
+	if !ok {
+		t.Fatalf("expected current code to be valid")
+	}
+	if _, ok := totp.Validate(secret, code, now, step); ok {
+		t.Fatalf("expected used code to be rejected")
+	}
+	if _, ok := totp.Validate(secret, "000000x", now, 0); ok {
+		t.Fatalf("expected malformed code to be rejected")
+	}
+}
@@ -0,0 +1,218 @@
+package utils_test
+
+import (
+	"backend/modules/user/models"
+	"backend/modules/user/service"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	pgdriver "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingDB — фальшива БД: запам'ятовує SQL з аргументами, на COUNT повертає
+// total, на вибірку сторінки — pageRows. Postgres для цих тестів не потрібен.
+type recordingDB struct {
+	mu       sync.Mutex
+	queries  []recordedQuery
+	total    int64
+	pageRows [][]driver.Value
+}
+
+type recordedQuery struct {
+	sql  string
+	args []driver.Value
+}
+
+var pageColumns = []string{"id", "full_name", "email", "position"}
+
+func (r *recordingDB) Connect(context.Context) (driver.Conn, error) {
+	return &recordingConn{db: r}, nil
+}
+func (r *recordingDB) Driver() driver.Driver { return recordingDriver{} }
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(string) (driver.Conn, error) { return nil, errors.New("use the connector") }
+
+type recordingConn struct{ db *recordingDB }
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *recordingConn) Close() error                        { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *recordingConn) QueryContext(_ context.Context, query string, named []driver.NamedValue) (driver.Rows, error) {
+	args := make([]driver.Value, len(named))
+	for i, arg := range named {
+		args[i] = arg.Value
+	}
+	c.db.mu.Lock()
+	c.db.queries = append(c.db.queries, recordedQuery{sql: query, args: args})
+	c.db.mu.Unlock()
+
+	if strings.Contains(strings.ToLower(query), "count(") {
+		return &recordingRows{columns: []string{"count"}, data: [][]driver.Value{{c.db.total}}}, nil
+	}
+	return &recordingRows{columns: pageColumns, data: c.db.pageRows}, nil
+}
+
+type recordingRows struct {
+	columns []string
+	data    [][]driver.Value
+	next    int
+}
+
+func (r *recordingRows) Columns() []string { return r.columns }
+func (r *recordingRows) Close() error      { return nil }
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.data) {
+		return io.EOF
+	}
+	copy(dest, r.data[r.next])
+	r.next++
+	return nil
+}
+
+func openRecordingDB(t *testing.T, rec *recordingDB) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(pgdriver.New(pgdriver.Config{Conn: sql.OpenDB(rec)}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// pageQuery — запит сторінки (не COUNT)
+func (r *recordingDB) pageQuery(t *testing.T) recordedQuery {
+	t.Helper()
+	for _, q := range r.queries {
+		if !strings.Contains(strings.ToLower(q.sql), "count(") {
+			return q
+		}
+	}
+	t.Fatalf("page query was not executed: %+v", r.queries)
+	return recordedQuery{}
+}
+
+func hasArg(args []driver.Value, want driver.Value) bool {
+	for _, arg := range args {
+		if arg == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestListUsersCountIsTotalNotPageLength(t *testing.T) {
+	rec := &recordingDB{
+		total: 42,
+		pageRows: [][]driver.Value{
+			{"8c5e3c1e-3d5b-4a5e-9d0e-1f2a3b4c5d6e", "Anna Nowak", "anna@corp.example", "Accountant"},
+			{"1b2c3d4e-5f60-4a7b-8c9d-0e1f2a3b4c5d", "Ivan Petrenko", "ivan@corp.example", nil},
+		},
+	}
+	result, err := service.ListUsers(openRecordingDB(t, rec), models.UserFilter{Limit: 2, Skip: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Count != 42 {
+		t.Errorf("expected Count to be the total 42, got %d", result.Count)
+	}
+	if len(result.Data) != 2 {
+		t.Fatalf("expected a page of 2 users, got %d", len(result.Data))
+	}
+	if result.Data[0].Position != "Accountant" || result.Data[1].Position != "" {
+		t.Errorf("unexpected positions: %q, %q", result.Data[0].Position, result.Data[1].Position)
+	}
+	page := rec.pageQuery(t)
+	if !strings.Contains(page.sql, "LIMIT") || !strings.Contains(page.sql, "OFFSET") {
+		t.Errorf("page query is not paginated: %s", page.sql)
+	}
+}
+
+func TestListUsersFilters(t *testing.T) {
+	rec := &recordingDB{}
+	inactive, admin := false, true
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	_, err := service.ListUsers(openRecordingDB(t, rec), models.UserFilter{
+		IsActive:     &inactive,
+		IsAdmin:      &admin,
+		LastSeenFrom: &from,
+		LastSeenTo:   &to,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.queries) != 2 {
+		t.Fatalf("expected count and page queries, got %d", len(rec.queries))
+	}
+	for _, q := range rec.queries {
+		for _, fragment := range []string{
+			"users.is_service_account = ", "users.is_active = ", "users.is_admin = ",
+			"users.last_seen_at >= ", "users.last_seen_at < ",
+		} {
+			if !strings.Contains(q.sql, fragment) {
+				t.Errorf("query misses %q: %s", fragment, q.sql)
+			}
+		}
+		if strings.Contains(q.sql, "is_super_user") {
+			t.Errorf("unset filter must not be applied: %s", q.sql)
+		}
+		if !hasArg(q.args, true) || !hasArg(q.args, false) {
+			t.Errorf("filter values are not bound: %v", q.args)
+		}
+	}
+}
+
+func TestListUsersEscapesLikeWildcards(t *testing.T) {
+	rec := &recordingDB{}
+	if _, err := service.ListUsers(openRecordingDB(t, rec), models.UserFilter{Query: ` 50%_off\ `}); err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range rec.queries {
+		if strings.Count(q.sql, `ILIKE $`) != 3 || strings.Count(q.sql, `ESCAPE '\'`) != 3 {
+			t.Errorf("every ILIKE needs ESCAPE '\\': %s", q.sql)
+		}
+		if !hasArg(q.args, `%50\%\_off\\%`) {
+			t.Errorf("wildcards are not escaped: %v", q.args)
+		}
+	}
+}
+
+func TestListUsersRejectsUnknownSort(t *testing.T) {
+	for _, sort := range []string{"password", "users.full_name", "fullName; DROP TABLE users"} {
+		rec := &recordingDB{}
+		_, err := service.ListUsers(openRecordingDB(t, rec), models.UserFilter{Sort: sort})
+		if !errors.Is(err, service.ErrInvalidUserSort) {
+			t.Errorf("sort %q: expected ErrInvalidUserSort, got %v", sort, err)
+		}
+		if len(rec.queries) != 0 {
+			t.Errorf("sort %q: no query should run, got %+v", sort, rec.queries)
+		}
+	}
+}
+
+func TestListUsersSortsNullsLast(t *testing.T) {
+	cases := map[string]string{
+		"lastSeenAt/desc": "ORDER BY users.last_seen_at DESC NULLS LAST, users.id",
+		"position/asc":    "ORDER BY employees.position ASC NULLS LAST, users.id",
+		"/":               "ORDER BY users.full_name ASC NULLS LAST, users.id",
+	}
+	for input, want := range cases {
+		sort, order, _ := strings.Cut(input, "/")
+		rec := &recordingDB{}
+		if _, err := service.ListUsers(openRecordingDB(t, rec), models.UserFilter{Sort: sort, Order: order}); err != nil {
+			t.Fatal(err)
+		}
+		if page := rec.pageQuery(t); !strings.Contains(page.sql, want) {
+			t.Errorf("%s: expected %q in %s", input, want, page.sql)
+		}
+	}
+}
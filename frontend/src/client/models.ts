@@ -141,7 +141,9 @@ export type NewPassword = {
 
 export type Token = {
   access_token: string
+  refresh_token?: string
   token_type?: string
+  expires_in?: number
 }
 
 export type UpdatePassword = {
@@ -12,12 +12,9 @@ import {
   UsersService,
 } from "../client"
 
+import { clearTokens, isLoggedIn, revokeSession, setTokens } from "../utils/auth"
 import useCustomToast from "./useCustomToast"
 
-const isLoggedIn = () => {
-  return localStorage.getItem("access_token") !== null
-}
-
 const useAuth = () => {
   const [error, setError] = useState<string | null>(null)
   const navigate = useNavigate()
@@ -58,7 +55,7 @@ const useAuth = () => {
 
   const login = async (data: AccessToken & { domain: string }) => {
     const response = await LoginService.loginAccessToken({ formData: data })
-    localStorage.setItem("access_token", response.access_token)
+    setTokens(response.access_token, response.refresh_token)
   }
 
   const loginMutation = useMutation({
@@ -91,7 +88,9 @@ const useAuth = () => {
   })
 
   const logout = () => {
-    localStorage.removeItem("access_token")
+    revokeSession()
+    clearTokens()
+    queryClient.clear()
     navigate({ to: "/login" })
   }
 
@@ -13,6 +13,7 @@ import { routeTree } from "./routeTree.gen"
 import { StrictMode } from "react"
 import { OpenAPI } from "./client"
 import theme from "./theme"
+import { getAccessToken, installAuthRefresh } from "./utils/auth"
 import { getApiUrl } from "./utils/urls"
 
 import "./i18n"
@@ -22,9 +23,16 @@ import "./i18n"
 OpenAPI.BASE = getApiUrl()
 
 OpenAPI.TOKEN = async () => {
-  return localStorage.getItem("access_token") || ""
+  return getAccessToken() || ""
 }
 
+// Access-токен живе 15 хвилин: після 401 пробуємо оновити його refresh-токеном
+installAuthRefresh(() => {
+  if (window.location.pathname !== "/login") {
+    window.location.href = "/login"
+  }
+})
+
 console.log("OpenAPI.BASE:", getApiUrl())
 
 
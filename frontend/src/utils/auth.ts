@@ -1,9 +1,113 @@
-import Cookies from "js-cookie"
+import axios, { type AxiosError, type InternalAxiosRequestConfig } from "axios"
+
+import { OpenAPI } from "../client"
+
+const ACCESS_TOKEN_KEY = "access_token"
+const REFRESH_TOKEN_KEY = "refresh_token"
 
 export const getAccessToken = (): string | null => {
-    return Cookies.get("access_token") || null
+  return localStorage.getItem(ACCESS_TOKEN_KEY)
+}
+
+export const getRefreshToken = (): string | null => {
+  return localStorage.getItem(REFRESH_TOKEN_KEY)
 }
 
 export const isLoggedIn = (): boolean => {
-    return !!getAccessToken()
+  return !!getAccessToken()
+}
+
+export const setTokens = (accessToken: string, refreshToken?: string) => {
+  localStorage.setItem(ACCESS_TOKEN_KEY, accessToken)
+  if (refreshToken) {
+    localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken)
+  }
+}
+
+export const clearTokens = () => {
+  localStorage.removeItem(ACCESS_TOKEN_KEY)
+  localStorage.removeItem(REFRESH_TOKEN_KEY)
+}
+
+// Окремий клієнт без перехоплювача: запити входу та оновлення не повторюємо
+const authClient = axios.create()
+
+const isAuthRequest = (url?: string) => !!url && url.includes("/v1/login/")
+
+let refreshing: Promise<string | null> | null = null
+
+// Refresh-токен ротується при кожному використанні, а повторне використання
+// старого відкликає всю сесію — тому паралельні 401 чекають на один запит
+export const refreshAccessToken = (): Promise<string | null> => {
+  if (!refreshing) {
+    refreshing = requestNewTokens().finally(() => {
+      refreshing = null
+    })
+  }
+  return refreshing
+}
+
+const requestNewTokens = async (): Promise<string | null> => {
+  const refreshToken = getRefreshToken()
+  if (!refreshToken) {
+    return null
+  }
+  try {
+    const { data } = await authClient.post(
+      `${OpenAPI.BASE}/v1/login/refresh-token`,
+      { refresh_token: refreshToken },
+    )
+    setTokens(data.access_token, data.refresh_token)
+    return data.access_token
+  } catch (error) {
+    if ((error as AxiosError).response?.status === 401) {
+      clearTokens()
+    }
+    return null
+  }
+}
+
+// Завершує поточну сесію на сервері; помилки не заважають вийти локально
+export const revokeSession = async () => {
+  const accessToken = getAccessToken()
+  if (!accessToken) {
+    return
+  }
+  try {
+    await authClient.post(`${OpenAPI.BASE}/v1/logout`, null, {
+      headers: { Authorization: `Bearer ${accessToken}` },
+    })
+  } catch {
+    // токен уже міг протухнути — сесію все одно приберемо локально
+  }
+}
+
+type RetriableRequest = InternalAxiosRequestConfig & { _authRetried?: boolean }
+
+// installAuthRefresh оновлює access-токен після 401 і один раз повторює запит;
+// якщо оновити не вдалося, сесія вважається завершеною
+export const installAuthRefresh = (onSessionExpired: () => void) => {
+  axios.interceptors.response.use(undefined, async (error: AxiosError) => {
+    const config = error.config as RetriableRequest | undefined
+    if (
+      error.response?.status !== 401 ||
+      !config ||
+      config._authRetried ||
+      isAuthRequest(config.url) ||
+      !getRefreshToken()
+    ) {
+      return Promise.reject(error)
+    }
+
+    config._authRetried = true
+    const accessToken = await refreshAccessToken()
+    if (!accessToken) {
+      clearTokens()
+      onSessionExpired()
+      return Promise.reject(error)
+    }
+
+    config.headers.Authorization = `Bearer ${accessToken}`
+    return axios.request(config)
+  })
 }